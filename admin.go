@@ -0,0 +1,226 @@
+package pulseboard
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/server"
+)
+
+// endpointManager adapts a PulseBoard to the server's endpoint management API.
+type endpointManager struct {
+	pb *PulseBoard
+}
+
+// AddEndpoint implements server.EndpointManager.
+func (m endpointManager) AddEndpoint(spec server.EndpointSpec) error {
+	ep, err := endpointFromSpec(spec)
+	if err != nil {
+		return err
+	}
+	return toServerError(m.pb.AddEndpoint(ep))
+}
+
+// UpdateEndpoint implements server.EndpointManager.
+func (m endpointManager) UpdateEndpoint(spec server.EndpointSpec) error {
+	ep, err := endpointFromSpec(spec)
+	if err != nil {
+		return err
+	}
+	return toServerError(m.pb.UpdateEndpoint(ep))
+}
+
+// RemoveEndpoint implements server.EndpointManager.
+func (m endpointManager) RemoveEndpoint(name string) error {
+	return toServerError(m.pb.RemoveEndpoint(name))
+}
+
+// toServerError wraps err with the server error that selects its HTTP status.
+func toServerError(err error) error {
+	switch {
+	case errors.Is(err, ErrEndpointNotFound):
+		return fmt.Errorf("%w: %w", server.ErrNotFound, err)
+	case errors.Is(err, ErrDuplicateEndpoint):
+		return fmt.Errorf("%w: %w", server.ErrConflict, err)
+	default:
+		return err
+	}
+}
+
+// endpointFromSpec builds an Endpoint from an endpoint management request.
+func endpointFromSpec(spec server.EndpointSpec) (Endpoint, error) {
+	var opts []EndpointOption
+
+	if spec.Method != "" {
+		opts = append(opts, WithMethod(spec.Method))
+	}
+	if len(spec.Headers) > 0 {
+		opts = append(opts, WithHeaders(mapToPairs(spec.Headers)...))
+	}
+	if len(spec.Labels) > 0 {
+		opts = append(opts, WithLabels(mapToPairs(spec.Labels)...))
+	}
+	if spec.Timeout != "" {
+		d, err := parseSpecDuration("timeout", spec.Timeout)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		opts = append(opts, WithTimeout(d))
+	}
+	if spec.Interval != "" {
+		d, err := parseSpecDuration("interval", spec.Interval)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		opts = append(opts, WithInterval(d))
+	}
+	if spec.Extractor != nil {
+		switch spec.Extractor.Type {
+		case "", "default":
+		case "http":
+			opts = append(opts, WithExtractor(HTTPStatusExtractor))
+		case "json":
+			if spec.Extractor.Path == "" {
+				return Endpoint{}, errors.New("json extractor requires a path")
+			}
+			opts = append(opts, WithExtractor(JSONFieldExtractor(spec.Extractor.Path)))
+		case "contains":
+			if spec.Extractor.Text == "" {
+				return Endpoint{}, errors.New("contains extractor requires text")
+			}
+			opts = append(opts, WithExtractor(ContainsExtractor(spec.Extractor.Text)))
+		default:
+			return Endpoint{}, fmt.Errorf("unknown extractor type %q", spec.Extractor.Type)
+		}
+	}
+
+	// type-specific options reject endpoints of other types
+	if spec.GraphQL != nil {
+		opts = append(opts, WithGraphQL(spec.GraphQL.Query, spec.GraphQL.Variables))
+	}
+	if spec.Body != "" {
+		opts = append(opts, WithBody(spec.Body))
+	}
+	if spec.Send != "" {
+		opts = append(opts, WithSend(spec.Send))
+	}
+	if spec.Expect != "" {
+		opts = append(opts, WithExpect(spec.Expect))
+	}
+	if spec.CertExpiryWarning != "" {
+		d, err := parseSpecDuration("cert_expiry_warning", spec.CertExpiryWarning)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		opts = append(opts, WithCertExpiryWarning(d))
+	}
+	if spec.RecordType != "" {
+		opts = append(opts, WithRecordType(spec.RecordType))
+	}
+	if spec.Resolver != "" {
+		opts = append(opts, WithResolver(spec.Resolver))
+	}
+	if len(spec.ExpectedAnswers) > 0 {
+		opts = append(opts, WithExpectedAnswers(spec.ExpectedAnswers...))
+	}
+	if spec.Service != "" {
+		opts = append(opts, WithGRPCService(spec.Service))
+	}
+	if spec.Insecure {
+		opts = append(opts, WithInsecure())
+	}
+	if spec.TLSSkipVerify {
+		opts = append(opts, WithTLSSkipVerify())
+	}
+
+	if spec.FailureThreshold != 0 {
+		opts = append(opts, WithFailureThreshold(spec.FailureThreshold))
+	}
+	if spec.SuccessThreshold != 0 {
+		opts = append(opts, WithSuccessThreshold(spec.SuccessThreshold))
+	}
+	if fd := spec.FlapDetection; fd != nil {
+		window, err := parseSpecDuration("flap_detection.window", fd.Window)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		opts = append(opts, WithFlapDetection(fd.Transitions, window))
+	}
+	if spec.Retries == 0 && (spec.RetryBackoff != "" || spec.RetrySharedTimeout) {
+		return Endpoint{}, errors.New("retry_backoff and retry_shared_timeout require retries")
+	}
+	if spec.Retries != 0 {
+		var backoff time.Duration
+		if spec.RetryBackoff != "" {
+			var err error
+			if backoff, err = parseSpecDuration("retry_backoff", spec.RetryBackoff); err != nil {
+				return Endpoint{}, err
+			}
+		}
+		opts = append(opts, WithRetries(spec.Retries, backoff))
+	}
+	if spec.RetrySharedTimeout {
+		opts = append(opts, WithSharedRetryTimeout())
+	}
+	if lt := spec.LatencyThresholds; lt != nil {
+		var degraded, down time.Duration
+		var err error
+		if lt.Degraded != "" {
+			if degraded, err = parseSpecDuration("latency_thresholds.degraded", lt.Degraded); err != nil {
+				return Endpoint{}, err
+			}
+		}
+		if lt.Down != "" {
+			if down, err = parseSpecDuration("latency_thresholds.down", lt.Down); err != nil {
+				return Endpoint{}, err
+			}
+		}
+		opts = append(opts, WithLatencyThresholds(degraded, down))
+	}
+
+	switch EndpointType(spec.Type) {
+	case "", EndpointTypeHTTP:
+		return NewEndpoint(spec.Name, spec.URL, opts...)
+	case EndpointTypeTCP:
+		return NewTCPEndpoint(spec.Name, spec.URL, opts...)
+	case EndpointTypeTLS:
+		return NewTLSEndpoint(spec.Name, spec.URL, opts...)
+	case EndpointTypeDNS:
+		return NewDNSEndpoint(spec.Name, spec.URL, opts...)
+	case EndpointTypeGRPC:
+		return NewGRPCEndpoint(spec.Name, spec.URL, opts...)
+	default:
+		return Endpoint{}, fmt.Errorf("unknown endpoint type %q", spec.Type)
+	}
+}
+
+// parseSpecDuration parses a duration field of an endpoint management
+// request: a Go duration such as "5s", or a whole number of days such as
+// "14d".
+func parseSpecDuration(field, s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", field, s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return d, nil
+}
+
+// mapToPairs flattens a map into the key-value pairs taken by WithHeaders
+// and WithLabels.
+func mapToPairs(m map[string]string) []string {
+	pairs := make([]string, 0, len(m)*2)
+	for k, v := range m {
+		pairs = append(pairs, k, v)
+	}
+	return pairs
+}
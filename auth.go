@@ -0,0 +1,92 @@
+package pulseboard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jpalmerr/pulseboard/internal/server"
+)
+
+// Auth requires requests to the dashboard, API and SSE stream to
+// authenticate. See [WithAuth].
+type Auth struct {
+	// Users maps usernames to bcrypt password hashes, as produced by
+	// "htpasswd -B". [LoadHTPasswd] reads them from a file.
+	Users map[string]string
+
+	// Tokens are static API tokens, sent as "Authorization: Bearer <token>"
+	// or as an access_token query parameter.
+	Tokens []string
+
+	// Public lists paths, relative to the base path, that are served without
+	// authentication, such as "/api/health" for probes or "/badge/" for
+	// badges embedded in other pages. An entry ending in "/" matches every
+	// path beneath it.
+	Public []string
+}
+
+// WithAuth requires every request to authenticate with HTTP basic auth as
+// one of auth.Users or with one of auth.Tokens. Browsers are given a
+// session cookie once authenticated, so the dashboard's SSE stream works;
+// a token-only dashboard can be opened once with ?access_token=<token>.
+// The admin token from [WithAdminToken] is also accepted.
+//
+// Example:
+//
+//	users, err := pulseboard.LoadHTPasswd("/etc/pulseboard/htpasswd")
+//	...
+//	pulseboard.WithAuth(pulseboard.Auth{
+//	    Users:  users,
+//	    Tokens: []string{os.Getenv("PULSEBOARD_API_TOKEN")},
+//	    Public: []string{"/api/health"},
+//	})
+//
+// With [WithOIDC], Users and Tokens may be omitted to only list Public
+// paths.
+//
+// Returns an error if no users or tokens are given, a password is not a
+// bcrypt hash, a token is empty or a public path does not start with "/".
+func WithAuth(auth Auth) Option {
+	return func(cfg *pbConfig) error {
+		if len(auth.Users) == 0 && len(auth.Tokens) == 0 && len(auth.Public) == 0 {
+			return errors.New("auth requires at least one user or token")
+		}
+		if err := server.ValidateAuth(server.Auth(auth)); err != nil {
+			return err
+		}
+		cfg.auth = auth
+		return nil
+	}
+}
+
+// LoadHTPasswd reads users from an htpasswd file of "user:hash" lines.
+// Blank lines and lines starting with "#" are skipped. Only bcrypt hashes
+// ("htpasswd -B") are supported; [WithAuth] rejects any others.
+func LoadHTPasswd(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open htpasswd file: %w", err)
+	}
+	defer f.Close()
+
+	users := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok || user == "" || hash == "" {
+			return nil, fmt.Errorf("%s:%d: expected user:hash", path, n)
+		}
+		users[user] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read htpasswd file: %w", err)
+	}
+	return users, nil
+}
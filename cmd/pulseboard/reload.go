@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jpalmerr/pulseboard"
+	"github.com/jpalmerr/pulseboard/config"
+)
+
+// reloader applies changes in the config file to a running PulseBoard.
+//
+// Endpoints are diffed by name: unchanged endpoints keep their status and
+// polling schedule, changed ones are updated in place, and the rest are
+// added or removed. An invalid config is rejected and the running one kept.
+// Changes that fail to apply are retried by the next reload.
+type reloader struct {
+	path   string
+	pb     *pulseboard.PulseBoard
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	current *config.Config
+	data    []byte // file contents last seen, valid or not
+}
+
+// newReloader creates a reloader for a PulseBoard started from cfg, which
+// was loaded from path.
+func newReloader(path string, cfg *config.Config, pb *pulseboard.PulseBoard, logger *slog.Logger) *reloader {
+	data, _ := os.ReadFile(path)
+	return &reloader{
+		path:    path,
+		pb:      pb,
+		logger:  logger,
+		current: cfg,
+		data:    data,
+	}
+}
+
+// reload re-reads the config file and applies it.
+func (r *reloader) reload() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.apply(data)
+}
+
+// reloadIfChanged reloads the config file if its contents changed since
+// it was last read.
+func (r *reloader) reloadIfChanged() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if bytes.Equal(data, r.data) {
+		return nil
+	}
+	return r.apply(data)
+}
+
+// apply parses data and applies the endpoint changes. Must be called with
+// mu held.
+func (r *reloader) apply(data []byte) error {
+	r.data = data
+
+	next, err := config.Parse(data)
+	if err != nil {
+		return err
+	}
+	if len(next.Endpoints) == 0 && len(next.Grids) == 0 {
+		return errors.New("no endpoints configured")
+	}
+	changes, err := config.DiffEndpoints(r.current, next)
+	if err != nil {
+		return err
+	}
+
+	r.warnRestartRequired(next)
+	r.pb.AddSecrets(next.Secrets()...)
+
+	var failed []string
+	for _, name := range changes.Removed {
+		if err := r.pb.RemoveEndpoint(name); err != nil {
+			r.logger.Warn("config reload: failed to remove endpoint", "endpoint", name, "error", err)
+			failed = append(failed, name)
+		}
+	}
+	for _, ep := range changes.Updated {
+		if err := r.pb.UpdateEndpoint(ep); err != nil {
+			r.logger.Warn("config reload: failed to update endpoint", "endpoint", ep.Name(), "error", err)
+			failed = append(failed, ep.Name())
+		}
+	}
+	for _, ep := range changes.Added {
+		if err := r.pb.AddEndpoint(ep); err != nil {
+			r.logger.Warn("config reload: failed to add endpoint", "endpoint", ep.Name(), "error", err)
+			failed = append(failed, ep.Name())
+		}
+	}
+
+	// record only the changes that applied, so failed ones are retried
+	applied, err := config.KeepEndpoints(r.current, next, failed)
+	if err != nil {
+		return err
+	}
+	r.current = applied
+
+	r.logger.Info("config reloaded",
+		"added", endpointNames(changes.Added),
+		"updated", endpointNames(changes.Updated),
+		"removed", changes.Removed,
+		"unchanged", len(changes.Unchanged),
+		"failed", failed,
+	)
+	return nil
+}
+
+// warnRestartRequired logs settings that changed but only take effect when
+// the server is restarted. Must be called with mu held.
+func (r *reloader) warnRestartRequired(next *config.Config) {
+	var settings []string
+	if next.Title != r.current.Title {
+		settings = append(settings, "title")
+	}
+	if next.Port != r.current.Port {
+		settings = append(settings, "port")
+	}
+	if next.BasePath != r.current.BasePath {
+		settings = append(settings, "base_path")
+	}
+	if next.PollInterval != r.current.PollInterval {
+		settings = append(settings, "poll_interval")
+	}
+	if next.AdminToken != r.current.AdminToken {
+		settings = append(settings, "admin_token")
+	}
+	if !reflect.DeepEqual(next.History, r.current.History) {
+		settings = append(settings, "history")
+	}
+	if !reflect.DeepEqual(next.Storage, r.current.Storage) {
+		settings = append(settings, "storage")
+	}
+	if !reflect.DeepEqual(next.Uptime, r.current.Uptime) {
+		settings = append(settings, "uptime")
+	}
+	if !reflect.DeepEqual(next.Latency, r.current.Latency) {
+		settings = append(settings, "latency")
+	}
+	if !reflect.DeepEqual(next.Health, r.current.Health) {
+		settings = append(settings, "health")
+	}
+	if !reflect.DeepEqual(next.Auth, r.current.Auth) {
+		settings = append(settings, "auth")
+	}
+	if !reflect.DeepEqual(next.Public, r.current.Public) {
+		settings = append(settings, "public")
+	}
+	if !reflect.DeepEqual(next.TLS, r.current.TLS) {
+		settings = append(settings, "tls")
+	}
+	if len(settings) > 0 {
+		r.logger.Warn("config reload: changed settings require a restart", "settings", settings)
+	}
+}
+
+// run reloads on SIGHUP and, if watchInterval is positive, whenever the
+// config file changes. It blocks until ctx is cancelled.
+func (r *reloader) run(ctx context.Context, watchInterval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if watchInterval > 0 {
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.logger.Info("SIGHUP received, reloading config", "path", r.path)
+			err = r.reload()
+		case <-tick:
+			err = r.reloadIfChanged()
+		}
+		if err != nil {
+			r.logger.Error("config reload rejected, keeping current config", "path", r.path, "error", err)
+		}
+	}
+}
+
+// endpointNames returns the names of eps.
+func endpointNames(eps []pulseboard.Endpoint) []string {
+	names := make([]string, len(eps))
+	for i, ep := range eps {
+		names[i] = ep.Name()
+	}
+	return names
+}
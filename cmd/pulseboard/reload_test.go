@@ -0,0 +1,205 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/jpalmerr/pulseboard"
+	"github.com/jpalmerr/pulseboard/config"
+)
+
+// newTestReloader loads content into a temp config file and returns a
+// reloader for a PulseBoard built from it.
+func newTestReloader(t *testing.T, content string) (*reloader, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	endpoints, err := config.BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+	pb, err := pulseboard.New(pulseboard.WithEndpoints(endpoints...))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return newReloader(path, cfg, pb, logger), path
+}
+
+// endpointURLs maps each of the reloader's endpoints to its URL.
+func endpointURLs(r *reloader) map[string]string {
+	urls := make(map[string]string)
+	for _, ep := range r.pb.Endpoints() {
+		urls[ep.Name()] = ep.URL()
+	}
+	return urls
+}
+
+func TestReloader_AppliesChanges(t *testing.T) {
+	r, path := newTestReloader(t, `
+endpoints:
+  - name: Same
+    url: https://same.example.com
+  - name: Changed
+    url: https://old.example.com
+  - name: Gone
+    url: https://gone.example.com
+`)
+
+	next := `
+endpoints:
+  - name: Same
+    url: https://same.example.com
+  - name: Changed
+    url: https://new.example.com
+  - name: Added
+    url: https://added.example.com
+`
+	if err := os.WriteFile(path, []byte(next), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := r.reload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Same":    "https://same.example.com",
+		"Changed": "https://new.example.com",
+		"Added":   "https://added.example.com",
+	}
+	if got := endpointURLs(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+}
+
+func TestReloader_RetriesFailedChanges(t *testing.T) {
+	r, path := newTestReloader(t, `
+endpoints:
+  - name: A
+    url: https://a.example.com
+`)
+
+	// an endpoint added through the admin API blocks the file's one
+	clash, err := pulseboard.NewEndpoint("Added", "https://api-added.example.com")
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+	if err := r.pb.AddEndpoint(clash); err != nil {
+		t.Fatalf("AddEndpoint() error = %v", err)
+	}
+
+	next := `
+endpoints:
+  - name: A
+    url: https://a.example.com
+  - name: Added
+    url: https://added.example.com
+`
+	if err := os.WriteFile(path, []byte(next), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := r.reload(); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	if got := endpointURLs(r)["Added"]; got != "https://api-added.example.com" {
+		t.Fatalf("Added URL after failed add = %q, want the admin API one", got)
+	}
+
+	if err := r.pb.RemoveEndpoint("Added"); err != nil {
+		t.Fatalf("RemoveEndpoint() error = %v", err)
+	}
+	if err := r.reload(); err != nil {
+		t.Fatalf("second reload() error = %v", err)
+	}
+
+	want := map[string]string{
+		"A":     "https://a.example.com",
+		"Added": "https://added.example.com",
+	}
+	if got := endpointURLs(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+}
+
+func TestReloader_RejectsInvalidConfig(t *testing.T) {
+	initial := `
+endpoints:
+  - name: Kept
+    url: https://kept.example.com
+`
+	r, path := newTestReloader(t, initial)
+
+	invalid := []string{
+		"endpoints: [",
+		"endpoints:\n  - name: NoURL\n",
+		"poll_interval: 10s\n",
+		"endpoints:\n  - name: A\n    url: https://a.example.com\n  - name: A\n    url: https://b.example.com\n",
+	}
+	for _, content := range invalid {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+		if err := r.reload(); err == nil {
+			t.Errorf("reload() of %q should fail", content)
+		}
+	}
+
+	want := map[string]string{"Kept": "https://kept.example.com"}
+	if got := endpointURLs(r); !reflect.DeepEqual(got, want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+}
+
+func TestReloader_ReloadIfChanged(t *testing.T) {
+	initial := `
+endpoints:
+  - name: A
+    url: https://a.example.com
+`
+	r, path := newTestReloader(t, initial)
+
+	// unchanged contents are not re-applied
+	if err := r.reloadIfChanged(); err != nil {
+		t.Fatalf("reloadIfChanged() error = %v", err)
+	}
+
+	// an invalid file is only reported once
+	if err := os.WriteFile(path, []byte("endpoints: ["), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := r.reloadIfChanged(); err == nil {
+		t.Error("first reloadIfChanged() of invalid file should fail")
+	}
+	if err := r.reloadIfChanged(); err != nil {
+		t.Errorf("second reloadIfChanged() error = %v, want nil", err)
+	}
+
+	if err := os.WriteFile(path, []byte(initial+"  - name: B\n    url: https://b.example.com\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := r.reloadIfChanged(); err != nil {
+		t.Fatalf("reloadIfChanged() error = %v", err)
+	}
+
+	var names []string
+	for name := range endpointURLs(r) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if want := []string{"A", "B"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("endpoints = %v, want %v", names, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"os/signal"
 	"syscall"
@@ -38,9 +39,14 @@ The server will:
 
 The server runs until interrupted (Ctrl+C) or receives SIGTERM.
 
+Sending SIGHUP reloads the config file: endpoints are added, updated and
+removed without a restart, and unchanged endpoints keep their status. With
+--watch the file is also reloaded whenever it changes. An invalid config is
+rejected and the running one kept.
+
 Example:
   pulseboard serve -c config.yaml
-  pulseboard serve --config /etc/pulseboard/config.yaml`,
+  pulseboard serve --config /etc/pulseboard/config.yaml --watch`,
 	RunE: runServe,
 }
 
@@ -49,6 +55,8 @@ func init() {
 
 	serveCmd.Flags().StringP("config", "c", "", "path to config file (required)")
 	_ = serveCmd.MarkFlagRequired("config")
+	serveCmd.Flags().Bool("watch", false, "reload the config file when it changes")
+	serveCmd.Flags().Duration("watch-interval", 2*time.Second, "how often to check the config file with --watch")
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
@@ -79,6 +87,14 @@ func runServe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no endpoints configured")
 	}
 
+	var watchInterval time.Duration
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		watchInterval, _ = cmd.Flags().GetDuration("watch-interval")
+		if watchInterval <= 0 {
+			return fmt.Errorf("--watch-interval must be positive, got %s", watchInterval)
+		}
+	}
+
 	// create PulseBoard with options
 	opts := []pulseboard.Option{
 		pulseboard.WithEndpoints(endpoints...),
@@ -89,6 +105,97 @@ func runServe(cmd *cobra.Command, args []string) error {
 	if cfg.Title != "" {
 		opts = append(opts, pulseboard.WithTitle(cfg.Title))
 	}
+	if cfg.BasePath != "" {
+		opts = append(opts, pulseboard.WithBasePath(cfg.BasePath))
+	}
+	if cfg.AdminToken != "" {
+		opts = append(opts, pulseboard.WithAdminToken(cfg.AdminToken))
+	}
+	if h := cfg.History; h != nil {
+		opts = append(opts, pulseboard.WithHistory(h.Samples, h.MaxAge.Duration()))
+	}
+	if u := cfg.Uptime; u != nil {
+		windows := make([]time.Duration, len(u.Windows))
+		for i, w := range u.Windows {
+			windows[i] = w.Duration()
+		}
+		opts = append(opts, pulseboard.WithUptime(*u.DegradedCredit, windows...))
+	}
+	if l := cfg.Latency; l != nil && len(l.Windows) > 0 {
+		windows := make([]time.Duration, len(l.Windows))
+		for i, w := range l.Windows {
+			windows[i] = w.Duration()
+		}
+		opts = append(opts, pulseboard.WithLatencyWindows(windows...))
+	}
+	if h := cfg.Health; h != nil {
+		rules := make([]pulseboard.HealthRule, len(h.Rules))
+		for i, r := range h.Rules {
+			rules[i] = pulseboard.HealthRule{
+				Name:          r.Name,
+				Labels:        r.Labels,
+				Endpoints:     r.Endpoints,
+				MinUp:         r.MinUp,
+				AllowDegraded: r.AllowDegraded,
+			}
+		}
+		opts = append(opts, pulseboard.WithHealthCheck(h.Path, rules...))
+	}
+	if a := cfg.Auth; a != nil {
+		users := make(map[string]string, len(a.Users))
+		if a.HTPasswdFile != "" {
+			fileUsers, err := pulseboard.LoadHTPasswd(a.HTPasswdFile)
+			if err != nil {
+				return err
+			}
+			maps.Copy(users, fileUsers)
+		}
+		maps.Copy(users, a.Users)
+		if len(users) > 0 || len(a.Tokens) > 0 || len(a.Public) > 0 {
+			opts = append(opts, pulseboard.WithAuth(pulseboard.Auth{
+				Users:  users,
+				Tokens: a.Tokens,
+				Public: a.Public,
+			}))
+		}
+		if o := a.OIDC; o != nil {
+			opts = append(opts, pulseboard.WithOIDC(pulseboard.OIDC{
+				Issuer:          o.Issuer,
+				ClientID:        o.ClientID,
+				ClientSecret:    o.ClientSecret,
+				RedirectURL:     o.RedirectURL,
+				Scopes:          o.Scopes,
+				AllowedDomains:  o.AllowedDomains,
+				AllowedGroups:   o.AllowedGroups,
+				AllowAnyAccount: o.AllowAnyAccount,
+				GroupsClaim:     o.GroupsClaim,
+			}))
+		}
+	}
+	if p := cfg.Public; p != nil {
+		opts = append(opts, pulseboard.WithPublicPage(pulseboard.PublicPage{
+			Path:   p.Path,
+			Port:   p.Port,
+			Title:  p.Title,
+			Labels: p.Labels,
+			Names:  p.Names,
+		}))
+	}
+	if t := cfg.TLS; t != nil {
+		opts = append(opts, pulseboard.WithTLS(t.CertFile, t.KeyFile))
+		if t.ClientCAFile != "" {
+			opts = append(opts, pulseboard.WithClientCA(t.ClientCAFile, t.RequireClientCert))
+		}
+		if t.DisableHTTP2 {
+			opts = append(opts, pulseboard.WithoutHTTP2())
+		}
+	}
+	if secrets := cfg.Secrets(); len(secrets) > 0 {
+		opts = append(opts, pulseboard.WithSecrets(secrets...))
+	}
+	if st := cfg.Storage; st != nil && st.Type == "file" {
+		opts = append(opts, pulseboard.WithStore(pulseboard.FileStore(st.Path)))
+	}
 
 	pb, err := pulseboard.New(opts...)
 	if err != nil {
@@ -105,6 +212,9 @@ func runServe(cmd *cobra.Command, args []string) error {
 		errChan <- pb.Start(ctx)
 	}()
 
+	// reload on SIGHUP, and on file changes with --watch
+	go newReloader(configFile, cfg, pb, logger).run(ctx, watchInterval)
+
 	// wait for server to finish
 	select {
 	case err := <-errChan:
@@ -14,9 +14,13 @@ import (
 // It processes both direct endpoints and grids, returning a combined slice.
 // Grid dimensions are expanded via cartesian product.
 func BuildEndpoints(cfg *Config) ([]pulseboard.Endpoint, error) {
-	var endpoints []pulseboard.Endpoint
+	configs, err := expandEndpoints(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, ec := range cfg.Endpoints {
+	endpoints := make([]pulseboard.Endpoint, 0, len(configs))
+	for _, ec := range configs {
 		ep, err := buildEndpoint(ec)
 		if err != nil {
 			return nil, err
@@ -24,15 +28,23 @@ func BuildEndpoints(cfg *Config) ([]pulseboard.Endpoint, error) {
 		endpoints = append(endpoints, ep)
 	}
 
+	return endpoints, nil
+}
+
+// expandEndpoints returns the direct endpoints followed by the endpoints of
+// every grid, each as a single EndpointConfig.
+func expandEndpoints(cfg *Config) ([]EndpointConfig, error) {
+	configs := append([]EndpointConfig(nil), cfg.Endpoints...)
+
 	for _, gc := range cfg.Grids {
-		gridEndpoints, err := buildGridEndpoints(gc)
+		gridConfigs, err := expandGrid(gc)
 		if err != nil {
 			return nil, err
 		}
-		endpoints = append(endpoints, gridEndpoints...)
+		configs = append(configs, gridConfigs...)
 	}
 
-	return endpoints, nil
+	return configs, nil
 }
 
 // buildEndpoint converts a single EndpointConfig to an SDK Endpoint.
@@ -64,6 +76,80 @@ func buildEndpoint(ec EndpointConfig) (pulseboard.Endpoint, error) {
 		opts = append(opts, pulseboard.WithInterval(ec.Interval.Duration()))
 	}
 
+	if ec.FailureThreshold != 0 {
+		opts = append(opts, pulseboard.WithFailureThreshold(ec.FailureThreshold))
+	}
+
+	if ec.SuccessThreshold != 0 {
+		opts = append(opts, pulseboard.WithSuccessThreshold(ec.SuccessThreshold))
+	}
+
+	if ec.FlapDetection != nil {
+		opts = append(opts, pulseboard.WithFlapDetection(ec.FlapDetection.Transitions, ec.FlapDetection.Window.Duration()))
+	}
+
+	if ec.Retries != 0 {
+		opts = append(opts, pulseboard.WithRetries(ec.Retries, ec.RetryBackoff.Duration()))
+	}
+
+	if ec.RetrySharedTimeout {
+		opts = append(opts, pulseboard.WithSharedRetryTimeout())
+	}
+
+	if lt := ec.LatencyThresholds; lt != nil {
+		opts = append(opts, pulseboard.WithLatencyThresholds(lt.Degraded.Duration(), lt.Down.Duration()))
+	}
+
+	if ec.CertExpiryWarning != 0 {
+		opts = append(opts, pulseboard.WithCertExpiryWarning(ec.CertExpiryWarning.Duration()))
+	}
+
+	if ec.Type == endpointTypeDNS {
+		if ec.RecordType != "" {
+			opts = append(opts, pulseboard.WithRecordType(ec.RecordType))
+		}
+		if ec.Resolver != "" {
+			opts = append(opts, pulseboard.WithResolver(ec.Resolver))
+		}
+		if len(ec.ExpectedAnswers) > 0 {
+			opts = append(opts, pulseboard.WithExpectedAnswers(ec.ExpectedAnswers...))
+		}
+		return pulseboard.NewDNSEndpoint(ec.Name, ec.URL, opts...)
+	}
+
+	if ec.Type == endpointTypeGRPC {
+		if ec.Service != "" {
+			opts = append(opts, pulseboard.WithGRPCService(ec.Service))
+		}
+		if ec.Insecure {
+			opts = append(opts, pulseboard.WithInsecure())
+		}
+		if ec.TLSSkipVerify {
+			opts = append(opts, pulseboard.WithTLSSkipVerify())
+		}
+		return pulseboard.NewGRPCEndpoint(ec.Name, ec.URL, opts...)
+	}
+
+	if ec.Type == endpointTypeTLS {
+		return pulseboard.NewTLSEndpoint(ec.Name, ec.URL, opts...)
+	}
+
+	if ec.Type == endpointTypeTCP {
+		if ec.Send != "" {
+			opts = append(opts, pulseboard.WithSend(ec.Send))
+		}
+		if ec.Expect != "" {
+			opts = append(opts, pulseboard.WithExpect(ec.Expect))
+		}
+		return pulseboard.NewTCPEndpoint(ec.Name, ec.URL, opts...)
+	}
+
+	if ec.GraphQL != nil {
+		opts = append(opts, pulseboard.WithGraphQL(ec.GraphQL.Query, ec.GraphQL.Variables))
+	} else if ec.Body != "" {
+		opts = append(opts, pulseboard.WithBody(ec.Body))
+	}
+
 	return pulseboard.NewEndpoint(ec.Name, ec.URL, opts...)
 }
 
@@ -84,14 +170,41 @@ func mapToKeyValuePairs(m map[string]string) []string {
 
 // buildGridEndpoints expands a GridConfig into multiple endpoints via cartesian product.
 func buildGridEndpoints(gc GridConfig) ([]pulseboard.Endpoint, error) {
+	configs, err := expandGrid(gc)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]pulseboard.Endpoint, 0, len(configs))
+	for _, ec := range configs {
+		ep, err := buildEndpoint(ec)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, ep)
+	}
+
+	return endpoints, nil
+}
+
+// expandGrid renders the EndpointConfig of every endpoint in a grid.
+func expandGrid(gc GridConfig) ([]EndpointConfig, error) {
 	tmpl, err := template.New("url").Option("missingkey=error").Parse(gc.URLTemplate)
 	if err != nil {
 		return nil, err
 	}
 
+	var bodyTmpl *template.Template
+	if gc.BodyTemplate != "" {
+		bodyTmpl, err = template.New("body").Option("missingkey=error").Parse(gc.BodyTemplate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	combinations := cartesianProduct(gc.Dimensions)
 
-	var endpoints []pulseboard.Endpoint
+	var configs []EndpointConfig
 	for _, combo := range combinations {
 		var buf bytes.Buffer
 		if err := tmpl.Execute(&buf, combo); err != nil {
@@ -100,6 +213,15 @@ func buildGridEndpoints(gc GridConfig) ([]pulseboard.Endpoint, error) {
 		url := buf.String()
 		name := buildGridName(gc.Name, combo)
 
+		var body string
+		if bodyTmpl != nil {
+			buf.Reset()
+			if err := bodyTmpl.Execute(&buf, combo); err != nil {
+				return nil, fmt.Errorf("grid (%s) with dimensions %v: body template execution failed: %w", gc.Name, combo, err)
+			}
+			body = buf.String()
+		}
+
 		// grid labels first, dimension values added on top
 		labels := make(map[string]string)
 		for k, v := range gc.Labels {
@@ -118,16 +240,12 @@ func buildGridEndpoints(gc GridConfig) ([]pulseboard.Endpoint, error) {
 			Labels:    labels,
 			Extractor: gc.Extractor,
 			Interval:  gc.Interval,
+			Body:      body,
 		}
-
-		ep, err := buildEndpoint(ec)
-		if err != nil {
-			return nil, err
-		}
-		endpoints = append(endpoints, ep)
+		configs = append(configs, ec)
 	}
 
-	return endpoints, nil
+	return configs, nil
 }
 
 // buildGridName creates a display name for a grid endpoint.
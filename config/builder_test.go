@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/jpalmerr/pulseboard"
 )
 
 func TestBuildEndpoints_SingleEndpoint(t *testing.T) {
@@ -416,3 +418,259 @@ func TestCartesianProduct_DeterministicOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildEndpoints_TCPEndpoint(t *testing.T) {
+	cfg, err := Parse([]byte(`
+endpoints:
+  - name: Redis
+    url: tcp://redis.internal:6379
+    send: "PING\r\n"
+    expect: "+PONG"
+    labels:
+      tier: cache
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	endpoints, err := BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+
+	ep := endpoints[0]
+	if ep.Type() != pulseboard.EndpointTypeTCP {
+		t.Errorf("Type() = %q, want %q", ep.Type(), pulseboard.EndpointTypeTCP)
+	}
+	if ep.URL() != "tcp://redis.internal:6379" {
+		t.Errorf("URL() = %q, want %q", ep.URL(), "tcp://redis.internal:6379")
+	}
+	if ep.Send() != "PING\r\n" {
+		t.Errorf("Send() = %q, want %q", ep.Send(), "PING\r\n")
+	}
+	if ep.Expect() != "+PONG" {
+		t.Errorf("Expect() = %q, want %q", ep.Expect(), "+PONG")
+	}
+	if ep.Labels()["tier"] != "cache" {
+		t.Errorf("Labels()[tier] = %q, want %q", ep.Labels()["tier"], "cache")
+	}
+}
+
+func TestBuildEndpoints_TLSEndpoint(t *testing.T) {
+	cfg, err := Parse([]byte(`
+endpoints:
+  - name: Mail TLS
+    url: tls://mail.example.com:465
+  - name: API
+    url: https://api.example.com/health
+    cert_expiry_warning: 14d
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	endpoints, err := BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+
+	mail := endpoints[0]
+	if mail.Type() != pulseboard.EndpointTypeTLS {
+		t.Errorf("Type() = %q, want %q", mail.Type(), pulseboard.EndpointTypeTLS)
+	}
+	if mail.CertExpiryWarning() != 21*24*time.Hour {
+		t.Errorf("CertExpiryWarning() = %v, want default 21 days", mail.CertExpiryWarning())
+	}
+
+	api := endpoints[1]
+	if api.Type() != pulseboard.EndpointTypeHTTP {
+		t.Errorf("Type() = %q, want %q", api.Type(), pulseboard.EndpointTypeHTTP)
+	}
+	if api.CertExpiryWarning() != 14*24*time.Hour {
+		t.Errorf("CertExpiryWarning() = %v, want 336h", api.CertExpiryWarning())
+	}
+}
+
+func TestBuildEndpoints_DNSEndpoint(t *testing.T) {
+	cfg, err := Parse([]byte(`
+endpoints:
+  - name: SPF
+    url: dns://example.com
+    record_type: TXT
+    resolver: 10.0.0.2
+    expected_answers: ["v=spf1 -all"]
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	endpoints, err := BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+
+	ep := endpoints[0]
+	if ep.Type() != pulseboard.EndpointTypeDNS {
+		t.Errorf("Type() = %q, want %q", ep.Type(), pulseboard.EndpointTypeDNS)
+	}
+	if ep.RecordType() != pulseboard.RecordTypeTXT {
+		t.Errorf("RecordType() = %q, want %q", ep.RecordType(), pulseboard.RecordTypeTXT)
+	}
+	if ep.Resolver() != "10.0.0.2:53" {
+		t.Errorf("Resolver() = %q, want %q", ep.Resolver(), "10.0.0.2:53")
+	}
+	if got := ep.ExpectedAnswers(); len(got) != 1 || got[0] != "v=spf1 -all" {
+		t.Errorf("ExpectedAnswers() = %v, want [v=spf1 -all]", got)
+	}
+}
+
+func TestBuildEndpoints_GRPCEndpoint(t *testing.T) {
+	cfg, err := Parse([]byte(`
+endpoints:
+  - name: Orders
+    url: grpc://orders.internal:50051
+    service: orders.v1.OrderService
+    tls_skip_verify: true
+    headers:
+      x-api-key: secret
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	endpoints, err := BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+
+	ep := endpoints[0]
+	if ep.Type() != pulseboard.EndpointTypeGRPC {
+		t.Errorf("Type() = %q, want %q", ep.Type(), pulseboard.EndpointTypeGRPC)
+	}
+	if ep.GRPCService() != "orders.v1.OrderService" {
+		t.Errorf("GRPCService() = %q, want %q", ep.GRPCService(), "orders.v1.OrderService")
+	}
+	if !ep.TLSSkipVerify() || ep.Insecure() {
+		t.Errorf("TLSSkipVerify() = %v, Insecure() = %v, want true, false", ep.TLSSkipVerify(), ep.Insecure())
+	}
+	if ep.Headers()["x-api-key"] != "secret" {
+		t.Errorf("Headers()[x-api-key] = %q, want %q", ep.Headers()["x-api-key"], "secret")
+	}
+}
+
+func TestBuildEndpoints_RequestBody(t *testing.T) {
+	cfg, err := Parse([]byte(`
+endpoints:
+  - name: Search
+    url: https://search.example.com/_search
+    body: '{"size":0}'
+  - name: Catalog
+    url: https://catalog.example.com/graphql
+    graphql:
+      query: "{ health { ok } }"
+grids:
+  - name: Tenants
+    url_template: https://api.example.com/check
+    body_template: '{"tenant":"{{.tenant}}"}'
+    dimensions:
+      tenant: [acme corp]
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	endpoints, err := BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+	if len(endpoints) != 3 {
+		t.Fatalf("expected 3 endpoints, got %d", len(endpoints))
+	}
+
+	if ep := endpoints[0]; ep.Body() != `{"size":0}` || ep.Method() != "POST" {
+		t.Errorf("Search: Body() = %q, Method() = %q, want body with POST", ep.Body(), ep.Method())
+	}
+	if ep := endpoints[1]; !ep.GraphQL() || ep.Body() != `{"query":"{ health { ok } }"}` {
+		t.Errorf("Catalog: GraphQL() = %v, Body() = %q", ep.GraphQL(), ep.Body())
+	}
+	if ep := endpoints[2]; ep.Body() != `{"tenant":"acme corp"}` {
+		t.Errorf("grid: Body() = %q, want rendered template", ep.Body())
+	}
+}
+
+func TestBuildEndpoints_Damping(t *testing.T) {
+	cfg, err := Parse([]byte(`
+endpoints:
+  - name: DB
+    url: tcp://db.internal:5432
+    failure_threshold: 3
+    flap_detection:
+      transitions: 4
+      window: 15m
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	endpoints, err := BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+
+	ep := endpoints[0]
+	if ep.FailureThreshold() != 3 || ep.SuccessThreshold() != 1 {
+		t.Errorf("thresholds = %d/%d, want 3/1", ep.FailureThreshold(), ep.SuccessThreshold())
+	}
+	if n, w := ep.FlapDetection(); n != 4 || w != 15*time.Minute {
+		t.Errorf("FlapDetection() = %d, %s, want 4, 15m", n, w)
+	}
+}
+
+func TestBuildEndpoints_Retries(t *testing.T) {
+	cfg, err := Parse([]byte(`
+endpoints:
+  - name: API
+    url: https://api.example.com
+    retries: 3
+    retry_backoff: 200ms
+    retry_shared_timeout: true
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	endpoints, err := BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+
+	ep := endpoints[0]
+	if n, backoff := ep.Retries(); n != 3 || backoff != 200*time.Millisecond {
+		t.Errorf("Retries() = %d, %s, want 3, 200ms", n, backoff)
+	}
+	if !ep.SharedRetryTimeout() {
+		t.Error("SharedRetryTimeout() = false, want true")
+	}
+}
+
+func TestBuildEndpoints_LatencyThresholds(t *testing.T) {
+	cfg, err := Parse([]byte(`
+endpoints:
+  - name: API
+    url: https://api.example.com
+    latency_thresholds:
+      degraded: 1500ms
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	endpoints, err := BuildEndpoints(cfg)
+	if err != nil {
+		t.Fatalf("BuildEndpoints() error = %v", err)
+	}
+
+	if degraded, down := endpoints[0].LatencyThresholds(); degraded != 1500*time.Millisecond || down != 0 {
+		t.Errorf("LatencyThresholds() = %s, %s, want 1.5s, 0s", degraded, down)
+	}
+}
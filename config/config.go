@@ -13,6 +13,23 @@
 //	    url: https://api.github.com
 //	    timeout: 5s
 //	    extractor: json:status
+//	  - name: Postgres
+//	    type: tcp
+//	    url: tcp://db.internal:5432
+//	  - name: Mail TLS
+//	    url: tls://mail.example.com:465
+//	    cert_expiry_warning: 14d
+//	  - name: API DNS
+//	    url: dns://api.example.com
+//	    expected_answers: [203.0.113.10]
+//	  - name: Orders
+//	    url: grpc://orders.internal:50051
+//	    service: orders.v1.OrderService
+//	    insecure: true
+//	  - name: Catalog GraphQL
+//	    url: https://catalog.example.com/graphql
+//	    graphql:
+//	      query: "{ health { ok } }"
 //
 //	grids:
 //	  - name: Platform
@@ -27,17 +44,37 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/jpalmerr/pulseboard/internal/redact"
 )
 
 // minPollInterval is the minimum allowed polling interval for production configs.
 // This prevents accidental DoS of endpoints with overly aggressive polling.
 const minPollInterval = 1 * time.Second
 
+// Storage types accepted in the storage block.
+const (
+	storageTypeMemory = "memory"
+	storageTypeFile   = "file"
+)
+
+// Defaults for a history block that leaves a limit unset.
+const (
+	defaultHistorySamples = 1000
+	defaultHistoryAge     = 24 * time.Hour
+)
+
+// defaultDegradedCredit is the degraded credit of an uptime block that
+// leaves it unset.
+const defaultDegradedCredit = 0.5
+
 // Config is the root configuration structure for PulseBoard.
 //
 // It maps directly to the YAML configuration file structure.
@@ -49,6 +86,10 @@ type Config struct {
 	// Port is the HTTP server port. Defaults to 8080.
 	Port int `yaml:"port"`
 
+	// BasePath serves the dashboard under a path prefix such as "/status",
+	// for use behind a reverse proxy. Defaults to the root.
+	BasePath string `yaml:"base_path"`
+
 	// PollInterval is the time between health check cycles.
 	// Accepts duration strings like "10s", "1m", "500ms".
 	// Defaults to 10s.
@@ -59,14 +100,241 @@ type Config struct {
 
 	// Grids defines endpoint grids that expand via cartesian product.
 	Grids []GridConfig `yaml:"grids"`
+
+	// AdminToken enables the /api/endpoints management API, authenticated
+	// with this bearer token. Supports ${VAR} expansion.
+	AdminToken string `yaml:"admin_token"`
+
+	// History bounds the status history kept per endpoint.
+	History *HistoryConfig `yaml:"history"`
+
+	// Storage selects where status and history are kept.
+	Storage *StorageConfig `yaml:"storage"`
+
+	// Uptime configures how uptime percentages are computed.
+	Uptime *UptimeConfig `yaml:"uptime"`
+
+	// Latency configures the windows of latency percentiles.
+	Latency *LatencyConfig `yaml:"latency"`
+
+	// Health configures the health check rollup.
+	Health *HealthConfig `yaml:"health"`
+
+	// Auth requires requests to the dashboard and API to authenticate.
+	Auth *AuthConfig `yaml:"auth"`
+
+	// Public serves a redacted status page for customers.
+	Public *PublicConfig `yaml:"public"`
+
+	// TLS serves the dashboard over HTTPS.
+	TLS *TLSConfig `yaml:"tls"`
+
+	// secrets are values masked in output; see Secrets
+	secrets []string
+}
+
+// Secrets returns the values that must not appear in the dashboard, API or
+// logs: values substituted by ${VAR} expansion into headers, request bodies,
+// TCP send strings or a URL's userinfo or query string, the admin token,
+// auth tokens, the OIDC client secret and the values of credential headers
+// such as Authorization.
+func (c *Config) Secrets() []string {
+	return slices.Clone(c.secrets)
+}
+
+// addSecret records value as a secret.
+func (c *Config) addSecret(value string) {
+	if value != "" && !slices.Contains(c.secrets, value) {
+		c.secrets = append(c.secrets, value)
+	}
+}
+
+// addCredential records a credential header value as a secret, along with
+// the credentials following an auth scheme such as "Bearer ".
+func (c *Config) addCredential(value string) {
+	c.addSecret(value)
+	if _, credentials, ok := strings.Cut(value, " "); ok {
+		c.addSecret(strings.TrimSpace(credentials))
+	}
+}
+
+// TLSConfig configures HTTPS. File paths support ${VAR} expansion.
+type TLSConfig struct {
+	// CertFile and KeyFile are the PEM-encoded certificate chain and
+	// private key. They are reloaded when they change on disk.
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+
+	// ClientCAFile enables client certificate verification against the
+	// CAs it contains.
+	ClientCAFile string `yaml:"client_ca_file"`
+
+	// RequireClientCert rejects clients without a verified certificate.
+	RequireClientCert bool `yaml:"require_client_cert"`
+
+	// DisableHTTP2 serves HTTP/1.1 only.
+	DisableHTTP2 bool `yaml:"disable_http2"`
+}
+
+// PublicConfig configures the public status page.
+type PublicConfig struct {
+	// Path serves the page under this path on the dashboard's port. With
+	// Port set, it is the page's base path on that port instead.
+	Path string `yaml:"path"`
+
+	// Port serves the page on a port of its own.
+	Port int `yaml:"port"`
+
+	// Title is the page's title. Defaults to the dashboard's title.
+	Title string `yaml:"title"`
+
+	// Labels lists the label keys shown on the page.
+	Labels []string `yaml:"labels"`
+
+	// Names maps endpoint names to the names shown on the page.
+	Names map[string]string `yaml:"names"`
+}
+
+// AuthConfig configures authentication for the dashboard and API.
+type AuthConfig struct {
+	// Users maps usernames to bcrypt password hashes, as produced by
+	// "htpasswd -B". Not expanded, since hashes contain "$".
+	Users map[string]string `yaml:"users"`
+
+	// HTPasswdFile is an htpasswd file of further users. Supports ${VAR}
+	// expansion.
+	HTPasswdFile string `yaml:"htpasswd_file"`
+
+	// Tokens are static API tokens. Supports ${VAR} expansion.
+	Tokens []string `yaml:"tokens"`
+
+	// Public lists paths served without authentication, such as
+	// /api/health. An entry ending in "/" matches every path beneath it.
+	Public []string `yaml:"public"`
+
+	// OIDC enables single sign-on with an OpenID Connect provider.
+	OIDC *OIDCConfig `yaml:"oidc"`
+}
+
+// OIDCConfig configures single sign-on with an OpenID Connect provider.
+type OIDCConfig struct {
+	// Issuer is the provider's issuer URL. Supports ${VAR} expansion.
+	Issuer string `yaml:"issuer"`
+
+	// ClientID and ClientSecret identify PulseBoard to the provider.
+	// Both support ${VAR} expansion.
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+
+	// RedirectURL is the absolute URL of the dashboard's /auth/callback
+	// route. Supports ${VAR} expansion.
+	RedirectURL string `yaml:"redirect_url"`
+
+	// Scopes are requested in addition to "openid". Defaults to email and
+	// profile.
+	Scopes []string `yaml:"scopes"`
+
+	// AllowedDomains admits only users with a verified email in one of
+	// these domains.
+	AllowedDomains []string `yaml:"allowed_domains"`
+
+	// AllowedGroups admits only users in one of these groups.
+	AllowedGroups []string `yaml:"allowed_groups"`
+
+	// AllowAnyAccount admits every user the provider authenticates. It is
+	// required when neither AllowedDomains nor AllowedGroups is set.
+	AllowAnyAccount bool `yaml:"allow_any_account"`
+
+	// GroupsClaim names the ID token claim listing the user's groups.
+	// Defaults to "groups".
+	GroupsClaim string `yaml:"groups_claim"`
+}
+
+// HealthConfig configures the health check rollup.
+type HealthConfig struct {
+	// Path is where the health check is served. Defaults to /api/health.
+	Path string `yaml:"path"`
+
+	// Rules must all pass for the check to pass. With no rules every
+	// endpoint must be up.
+	Rules []HealthRuleConfig `yaml:"rules"`
 }
 
+// HealthRuleConfig selects a group of endpoints that must be up.
+type HealthRuleConfig struct {
+	// Name identifies the rule in the response.
+	Name string `yaml:"name"`
+
+	// Labels selects endpoints whose labels include every pair.
+	Labels map[string]string `yaml:"labels"`
+
+	// Endpoints selects endpoints by name.
+	Endpoints []string `yaml:"endpoints"`
+
+	// MinUp is how many selected endpoints must be up. Zero means all.
+	MinUp int `yaml:"min_up"`
+
+	// AllowDegraded counts degraded endpoints as up.
+	AllowDegraded bool `yaml:"allow_degraded"`
+}
+
+// LatencyConfig configures the windows of latency percentiles.
+type LatencyConfig struct {
+	// Windows are the rolling windows reported, such as "1h" or "7d".
+	// Defaults to 1h and 24h.
+	Windows []Duration `yaml:"windows"`
+}
+
+// UptimeConfig configures how uptime percentages are computed.
+type UptimeConfig struct {
+	// DegradedCredit is the fraction of degraded time, between 0 and 1,
+	// counted as available. Defaults to 0.5.
+	DegradedCredit *float64 `yaml:"degraded_credit"`
+
+	// Windows are the rolling windows reported, such as "24h" or "7d".
+	// Defaults to 24h, 7d and 30d.
+	Windows []Duration `yaml:"windows"`
+}
+
+// StorageConfig selects where status and history are kept.
+type StorageConfig struct {
+	// Type is "memory" (default) or "file".
+	Type string `yaml:"type"`
+
+	// Path is the directory of a file store. Supports ${VAR} expansion.
+	Path string `yaml:"path"`
+}
+
+// HistoryConfig bounds the status history kept per endpoint.
+type HistoryConfig struct {
+	// Samples is the maximum number of results kept. Defaults to 1000.
+	Samples int `yaml:"samples"`
+
+	// MaxAge is how long results are kept. Defaults to 24h.
+	MaxAge Duration `yaml:"max_age"`
+}
+
+// Endpoint types accepted in [EndpointConfig.Type].
+const (
+	endpointTypeHTTP = "http"
+	endpointTypeTCP  = "tcp"
+	endpointTypeTLS  = "tls"
+	endpointTypeDNS  = "dns"
+	endpointTypeGRPC = "grpc"
+)
+
 // EndpointConfig defines a single health check endpoint.
 type EndpointConfig struct {
 	// Name is the display name shown in the dashboard.
 	Name string `yaml:"name"`
 
-	// URL is the health check endpoint URL.
+	// Type is the check type: "http" (default), "tcp", "tls", "dns" or "grpc".
+	// If omitted, it is inferred from the URL scheme.
+	Type string `yaml:"type"`
+
+	// URL is the health check endpoint URL, tcp://host:port for TCP checks,
+	// tls://host:port for certificate checks, dns://name for DNS checks, or
+	// grpc://host:port for gRPC health checks.
 	// Supports environment variable substitution: ${VAR} or ${VAR:-default}
 	URL string `yaml:"url"`
 
@@ -76,7 +344,8 @@ type EndpointConfig struct {
 	// Timeout is the request timeout. Defaults to 10s.
 	Timeout Duration `yaml:"timeout"`
 
-	// Headers are custom HTTP headers sent with each request.
+	// Headers are custom HTTP headers sent with each request, or call
+	// metadata for grpc endpoints.
 	// Values support environment variable substitution.
 	Headers map[string]string `yaml:"headers"`
 
@@ -91,6 +360,106 @@ type EndpointConfig struct {
 	// If not specified, uses the global poll_interval.
 	// Must be between 1s and 1h.
 	Interval Duration `yaml:"interval"`
+
+	// Send is data written after connecting (tcp only).
+	// Supports environment variable substitution.
+	Send string `yaml:"send"`
+
+	// Expect is text the response must contain for the check to pass (tcp only).
+	Expect string `yaml:"expect"`
+
+	// CertExpiryWarning is how long before certificate expiry the endpoint is
+	// degraded. Defaults to 21d for tls endpoints. Setting it on an https
+	// endpoint enables the certificate check alongside the health check.
+	CertExpiryWarning Duration `yaml:"cert_expiry_warning"`
+
+	// RecordType is the DNS record type to look up: A (default), AAAA,
+	// CNAME, TXT or SRV (dns only).
+	RecordType string `yaml:"record_type"`
+
+	// Resolver is the DNS server to query as host or host:port (dns only).
+	// Defaults to the system resolver.
+	Resolver string `yaml:"resolver"`
+
+	// ExpectedAnswers is the set of answers the lookup must return (dns only).
+	// If the answers differ, the endpoint is degraded.
+	ExpectedAnswers []string `yaml:"expected_answers"`
+
+	// Service is the service name sent in the health check (grpc only).
+	// Empty checks the server as a whole.
+	Service string `yaml:"service"`
+
+	// Insecure calls the server over plaintext HTTP/2 (grpc only).
+	Insecure bool `yaml:"insecure"`
+
+	// TLSSkipVerify disables server certificate verification (grpc only).
+	TLSSkipVerify bool `yaml:"tls_skip_verify"`
+
+	// Body is the request body sent with each poll (http only). The method
+	// defaults to POST when a body is set.
+	// Supports environment variable substitution.
+	Body string `yaml:"body"`
+
+	// GraphQL sends a GraphQL query instead of Body (http only). A response
+	// with a non-empty errors array marks the endpoint down.
+	GraphQL *GraphQLConfig `yaml:"graphql"`
+
+	// FailureThreshold is the number of consecutive failed checks before the
+	// published status changes away from up. Defaults to 1.
+	FailureThreshold int `yaml:"failure_threshold"`
+
+	// SuccessThreshold is the number of consecutive successful checks before
+	// the published status changes back to up. Defaults to 1.
+	SuccessThreshold int `yaml:"success_threshold"`
+
+	// FlapDetection marks the endpoint as flapping when its status changes
+	// too often. Disabled if not set.
+	FlapDetection *FlapDetectionConfig `yaml:"flap_detection"`
+
+	// Retries is the number of times a failed check is retried within a
+	// single poll (0-10). Transport errors, timeouts, 5xx and 429 responses
+	// are retried.
+	Retries int `yaml:"retries"`
+
+	// RetryBackoff is the wait before the first retry, doubling for each
+	// retry after that. Defaults to no wait.
+	RetryBackoff Duration `yaml:"retry_backoff"`
+
+	// RetrySharedTimeout makes all attempts and the waits between them fit
+	// within Timeout, instead of each attempt getting the full Timeout.
+	RetrySharedTimeout bool `yaml:"retry_shared_timeout"`
+
+	// LatencyThresholds marks slow checks as degraded or down.
+	LatencyThresholds *LatencyThresholdsConfig `yaml:"latency_thresholds"`
+}
+
+// LatencyThresholdsConfig sets the latencies above which an endpoint is
+// degraded or down. Either may be omitted.
+type LatencyThresholdsConfig struct {
+	// Degraded is the latency above which the endpoint is degraded.
+	Degraded Duration `yaml:"degraded"`
+
+	// Down is the latency above which the endpoint is down.
+	Down Duration `yaml:"down"`
+}
+
+// FlapDetectionConfig configures flap detection for an endpoint.
+type FlapDetectionConfig struct {
+	// Transitions is the number of status changes within Window at which
+	// the endpoint is flapping. Must be at least 2.
+	Transitions int `yaml:"transitions"`
+
+	// Window is the period over which status changes are counted.
+	Window Duration `yaml:"window"`
+}
+
+// GraphQLConfig is a GraphQL query sent as an endpoint's request body.
+type GraphQLConfig struct {
+	// Query is the GraphQL query document.
+	Query string `yaml:"query"`
+
+	// Variables are the query variables, sent as JSON.
+	Variables map[string]any `yaml:"variables"`
 }
 
 // GridConfig defines an endpoint grid that expands via cartesian product.
@@ -106,6 +475,11 @@ type GridConfig struct {
 	// Supports environment variable substitution in the template.
 	URLTemplate string `yaml:"url_template"`
 
+	// BodyTemplate is a Go template for generating request bodies, with the
+	// same variables as URLTemplate. Values are not URL-encoded.
+	// Supports environment variable substitution in the template.
+	BodyTemplate string `yaml:"body_template"`
+
 	// Dimensions maps dimension names to their possible values.
 	// The cartesian product of all dimensions generates the endpoints.
 	Dimensions map[string][]string `yaml:"dimensions"`
@@ -163,12 +537,22 @@ type ExtractorConfig struct {
 type Duration time.Duration
 
 // UnmarshalYAML implements yaml.Unmarshaler for Duration.
+// In addition to Go duration strings, whole days may be given as "14d".
 func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
 	var s string
 	if err := node.Decode(&s); err != nil {
 		return err
 	}
 
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		*d = Duration(time.Duration(n) * 24 * time.Hour)
+		return nil
+	}
+
 	parsed, err := time.ParseDuration(s)
 	if err != nil {
 		return fmt.Errorf("invalid duration %q: %w", s, err)
@@ -255,9 +639,62 @@ func (e *ExtractorConfig) parseShorthand(s string) error {
 // Group 3: the default value (may be empty for ${VAR:-})
 var envVarPattern = regexp.MustCompile(`\$\{([^}:]+)(:-([^}]*))?\}`)
 
-// expandEnvVars replaces ${VAR} and ${VAR:-default} patterns with environment values.
-func expandEnvVars(s string) (string, error) {
+// expandEnvVars replaces ${VAR} and ${VAR:-default} patterns with environment
+// values.
+func (c *Config) expandEnvVars(s string) (string, error) {
+	expanded, _, err := expandEnv(s)
+	return expanded, err
+}
+
+// expandCredential expands s as expandEnvVars does, recording each value
+// taken from the environment as a secret. It is used for header values,
+// request bodies and TCP send strings.
+func (c *Config) expandCredential(s string) (string, error) {
+	expanded, values, err := expandEnv(s)
+	for _, v := range values {
+		c.addSecret(v)
+	}
+	return expanded, err
+}
+
+// expandURL expands rawURL as expandEnvVars does, recording the values taken
+// from the environment into its userinfo or query string as secrets. Values
+// in the host or path, such as a region, are not secrets.
+func (c *Config) expandURL(rawURL string) (string, error) {
+	expanded, values, err := expandEnv(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	rest := expanded
+	if _, after, ok := strings.Cut(rest, "://"); ok {
+		rest = after
+	}
+	authority, pathQuery := rest, ""
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		authority, pathQuery = rest[:i], rest[i:]
+	}
+	var userinfo, query string
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		userinfo = authority[:i]
+	}
+	if _, q, ok := strings.Cut(pathQuery, "?"); ok {
+		query, _, _ = strings.Cut(q, "#")
+	}
+
+	for _, v := range values {
+		if strings.Contains(userinfo, v) || strings.Contains(query, v) {
+			c.addSecret(v)
+		}
+	}
+	return expanded, nil
+}
+
+// expandEnv replaces ${VAR} and ${VAR:-default} patterns with environment
+// values, returning the values taken from the environment.
+func expandEnv(s string) (string, []string, error) {
 	var firstErr error
+	var values []string
 
 	result := envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
 		// already have an error, skip processing
@@ -287,13 +724,14 @@ func expandEnvVars(s string) (string, error) {
 			firstErr = fmt.Errorf("environment variable %q is not set", varName)
 			return match
 		}
+		values = append(values, value)
 		return value
 	})
 
 	if firstErr != nil {
-		return "", firstErr
+		return "", nil, firstErr
 	}
-	return result, nil
+	return result, values, nil
 }
 
 // Load reads and parses a YAML configuration file.
@@ -310,8 +748,11 @@ func Load(path string) (*Config, error) {
 
 // Parse parses YAML configuration data.
 //
-// Environment variables are expanded in URL, URLTemplate, and Header values.
-// Defaults are applied for Port (8080) and PollInterval (10s).
+// Environment variables are expanded in URL, URLTemplate, BodyTemplate, Body,
+// Header values and AdminToken. The values substituted are reported by
+// [Config.Secrets].
+// Defaults are applied for Port (8080), PollInterval (10s), unset History
+// limits and an unset uptime DegradedCredit.
 func Parse(data []byte) (*Config, error) {
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
@@ -324,6 +765,18 @@ func Parse(data []byte) (*Config, error) {
 	if cfg.PollInterval == 0 {
 		cfg.PollInterval = Duration(15 * time.Second)
 	}
+	if h := cfg.History; h != nil {
+		if h.Samples == 0 {
+			h.Samples = defaultHistorySamples
+		}
+		if h.MaxAge == 0 {
+			h.MaxAge = Duration(defaultHistoryAge)
+		}
+	}
+	if u := cfg.Uptime; u != nil && u.DegradedCredit == nil {
+		credit := defaultDegradedCredit
+		u.DegradedCredit = &credit
+	}
 
 	if err := cfg.expandAndValidate(); err != nil {
 		return nil, err
@@ -338,6 +791,185 @@ func (c *Config) expandAndValidate() error {
 		return fmt.Errorf("poll_interval must be at least %s, got %s", minPollInterval, c.PollInterval.Duration())
 	}
 
+	if c.BasePath != "" && !strings.HasPrefix(c.BasePath, "/") {
+		return fmt.Errorf("base_path must start with /, got %q", c.BasePath)
+	}
+
+	if h := c.History; h != nil {
+		if h.Samples < 0 {
+			return fmt.Errorf("history.samples must be positive, got %d", h.Samples)
+		}
+		if h.MaxAge < 0 {
+			return fmt.Errorf("history.max_age cannot be negative, got %s", h.MaxAge.Duration())
+		}
+	}
+
+	if u := c.Uptime; u != nil {
+		if u.DegradedCredit != nil && (*u.DegradedCredit < 0 || *u.DegradedCredit > 1) {
+			return fmt.Errorf("uptime.degraded_credit must be between 0 and 1, got %g", *u.DegradedCredit)
+		}
+		for i, w := range u.Windows {
+			if w <= 0 {
+				return fmt.Errorf("uptime.windows[%d] must be positive, got %s", i, w.Duration())
+			}
+		}
+	}
+
+	if l := c.Latency; l != nil {
+		for i, w := range l.Windows {
+			if w <= 0 {
+				return fmt.Errorf("latency.windows[%d] must be positive, got %s", i, w.Duration())
+			}
+		}
+	}
+
+	if h := c.Health; h != nil {
+		if h.Path != "" && !strings.HasPrefix(h.Path, "/") {
+			return fmt.Errorf("health.path must start with /, got %q", h.Path)
+		}
+		for i, r := range h.Rules {
+			if r.MinUp < 0 {
+				return fmt.Errorf("health.rules[%d]: min_up cannot be negative, got %d", i, r.MinUp)
+			}
+		}
+	}
+
+	if a := c.Auth; a != nil {
+		if len(a.Users) == 0 && a.HTPasswdFile == "" && len(a.Tokens) == 0 && a.OIDC == nil {
+			return errors.New("auth requires users, htpasswd_file, tokens or oidc")
+		}
+		for user, hash := range a.Users {
+			if !strings.HasPrefix(hash, "$2") {
+				return fmt.Errorf("auth.users.%s must be a bcrypt hash", user)
+			}
+		}
+		if a.HTPasswdFile != "" {
+			expanded, err := c.expandEnvVars(a.HTPasswdFile)
+			if err != nil {
+				return fmt.Errorf("auth.htpasswd_file: %w", err)
+			}
+			a.HTPasswdFile = expanded
+		}
+		for i, token := range a.Tokens {
+			expanded, err := c.expandEnvVars(token)
+			if err != nil {
+				return fmt.Errorf("auth.tokens[%d]: %w", i, err)
+			}
+			if expanded == "" {
+				return fmt.Errorf("auth.tokens[%d] is empty", i)
+			}
+			c.addSecret(expanded)
+			a.Tokens[i] = expanded
+		}
+		for i, path := range a.Public {
+			if !strings.HasPrefix(path, "/") {
+				return fmt.Errorf("auth.public[%d] must start with /, got %q", i, path)
+			}
+		}
+		if o := a.OIDC; o != nil {
+			for _, f := range []struct {
+				name  string
+				value *string
+			}{
+				{"issuer", &o.Issuer},
+				{"client_id", &o.ClientID},
+				{"client_secret", &o.ClientSecret},
+				{"redirect_url", &o.RedirectURL},
+			} {
+				expanded, err := c.expandEnvVars(*f.value)
+				if err != nil {
+					return fmt.Errorf("auth.oidc.%s: %w", f.name, err)
+				}
+				*f.value = expanded
+			}
+			if o.Issuer == "" {
+				return errors.New("auth.oidc.issuer is required")
+			}
+			if o.ClientID == "" {
+				return errors.New("auth.oidc.client_id is required")
+			}
+			if o.RedirectURL == "" {
+				return errors.New("auth.oidc.redirect_url is required")
+			}
+			if len(o.AllowedDomains) == 0 && len(o.AllowedGroups) == 0 && !o.AllowAnyAccount {
+				return errors.New("auth.oidc requires allowed_domains or allowed_groups, or allow_any_account: true")
+			}
+			c.addSecret(o.ClientSecret)
+		}
+	}
+
+	if p := c.Public; p != nil {
+		if p.Path != "" && !strings.HasPrefix(p.Path, "/") {
+			return fmt.Errorf("public.path must start with /, got %q", p.Path)
+		}
+		if p.Port == 0 && strings.TrimSuffix(p.Path, "/") == "" {
+			return errors.New("public requires a path other than / or a port")
+		}
+		if p.Port < 0 || p.Port > 65535 {
+			return fmt.Errorf("public.port must be between 1 and 65535, got %d", p.Port)
+		}
+		for name, display := range p.Names {
+			if display == "" {
+				return fmt.Errorf("public.names.%s is empty", name)
+			}
+		}
+	}
+
+	if t := c.TLS; t != nil {
+		for _, f := range []struct {
+			name  string
+			value *string
+		}{
+			{"cert_file", &t.CertFile},
+			{"key_file", &t.KeyFile},
+			{"client_ca_file", &t.ClientCAFile},
+		} {
+			expanded, err := c.expandEnvVars(*f.value)
+			if err != nil {
+				return fmt.Errorf("tls.%s: %w", f.name, err)
+			}
+			*f.value = expanded
+		}
+		if t.CertFile == "" {
+			return errors.New("tls.cert_file is required")
+		}
+		if t.KeyFile == "" {
+			return errors.New("tls.key_file is required")
+		}
+		if t.RequireClientCert && t.ClientCAFile == "" {
+			return errors.New("tls.require_client_cert requires client_ca_file")
+		}
+	}
+
+	if st := c.Storage; st != nil {
+		switch st.Type {
+		case "", storageTypeMemory:
+			if st.Path != "" {
+				return errors.New("storage.path is only valid for file storage")
+			}
+		case storageTypeFile:
+			if st.Path == "" {
+				return errors.New("storage.path is required for file storage")
+			}
+			expanded, err := c.expandEnvVars(st.Path)
+			if err != nil {
+				return fmt.Errorf("storage.path: %w", err)
+			}
+			st.Path = expanded
+		default:
+			return fmt.Errorf("storage.type must be %q or %q, got %q", storageTypeMemory, storageTypeFile, st.Type)
+		}
+	}
+
+	if c.AdminToken != "" {
+		expanded, err := c.expandEnvVars(c.AdminToken)
+		if err != nil {
+			return fmt.Errorf("admin_token: %w", err)
+		}
+		c.addSecret(expanded)
+		c.AdminToken = expanded
+	}
+
 	for i := range c.Endpoints {
 		ep := &c.Endpoints[i]
 
@@ -348,7 +980,7 @@ func (c *Config) expandAndValidate() error {
 		if ep.URL == "" {
 			return fmt.Errorf("endpoints[%d] (%s): url is required", i, ep.Name)
 		}
-		expanded, err := expandEnvVars(ep.URL)
+		expanded, err := c.expandURL(ep.URL)
 		if err != nil {
 			return fmt.Errorf("endpoints[%d] (%s): url: %w", i, ep.Name, err)
 		}
@@ -361,15 +993,80 @@ func (c *Config) expandAndValidate() error {
 		if parsedURL.Scheme == "" {
 			return fmt.Errorf("endpoints[%d] (%s): url must have a scheme (http:// or https://)", i, ep.Name)
 		}
-		if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-			return fmt.Errorf("endpoints[%d] (%s): url scheme must be http or https, got %q", i, ep.Name, parsedURL.Scheme)
+
+		if ep.Type == "" {
+			ep.Type = endpointTypeHTTP
+			switch parsedURL.Scheme {
+			case "tcp":
+				ep.Type = endpointTypeTCP
+			case "tls":
+				ep.Type = endpointTypeTLS
+			case "dns":
+				ep.Type = endpointTypeDNS
+			case "grpc":
+				ep.Type = endpointTypeGRPC
+			}
+		}
+
+		if ep.Type != endpointTypeGRPC && (ep.Service != "" || ep.Insecure || ep.TLSSkipVerify) {
+			return fmt.Errorf("endpoints[%d] (%s): service, insecure and tls_skip_verify are only valid for grpc endpoints", i, ep.Name)
+		}
+
+		if ep.Type != endpointTypeDNS && (ep.RecordType != "" || ep.Resolver != "" || len(ep.ExpectedAnswers) > 0) {
+			return fmt.Errorf("endpoints[%d] (%s): record_type, resolver and expected_answers are only valid for dns endpoints", i, ep.Name)
+		}
+
+		if ep.Type != endpointTypeHTTP && (ep.Body != "" || ep.GraphQL != nil) {
+			return fmt.Errorf("endpoints[%d] (%s): body and graphql are only valid for http endpoints", i, ep.Name)
+		}
+
+		if ep.CertExpiryWarning < 0 {
+			return fmt.Errorf("endpoints[%d] (%s): cert_expiry_warning cannot be negative, got %s",
+				i, ep.Name, ep.CertExpiryWarning.Duration())
+		}
+
+		switch ep.Type {
+		case endpointTypeHTTP:
+			if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+				return fmt.Errorf("endpoints[%d] (%s): url scheme must be http or https, got %q", i, ep.Name, parsedURL.Scheme)
+			}
+			if ep.Send != "" || ep.Expect != "" {
+				return fmt.Errorf("endpoints[%d] (%s): send and expect are only valid for tcp endpoints", i, ep.Name)
+			}
+			if ep.CertExpiryWarning != 0 && parsedURL.Scheme != "https" {
+				return fmt.Errorf("endpoints[%d] (%s): cert_expiry_warning requires an https url", i, ep.Name)
+			}
+			if err := c.validateHTTPBody(ep); err != nil {
+				return fmt.Errorf("endpoints[%d] (%s): %w", i, ep.Name, err)
+			}
+		case endpointTypeTCP:
+			if err := c.validateTCPEndpoint(ep, parsedURL); err != nil {
+				return fmt.Errorf("endpoints[%d] (%s): %w", i, ep.Name, err)
+			}
+		case endpointTypeTLS:
+			if err := validateTLSEndpoint(ep, parsedURL); err != nil {
+				return fmt.Errorf("endpoints[%d] (%s): %w", i, ep.Name, err)
+			}
+		case endpointTypeDNS:
+			if err := c.validateDNSEndpoint(ep, parsedURL); err != nil {
+				return fmt.Errorf("endpoints[%d] (%s): %w", i, ep.Name, err)
+			}
+		case endpointTypeGRPC:
+			if err := validateGRPCEndpoint(ep, parsedURL); err != nil {
+				return fmt.Errorf("endpoints[%d] (%s): %w", i, ep.Name, err)
+			}
+		default:
+			return fmt.Errorf("endpoints[%d] (%s): unknown type %q (expected http, tcp, tls, dns or grpc)", i, ep.Name, ep.Type)
 		}
 
 		for k, v := range ep.Headers {
-			expanded, err := expandEnvVars(v)
+			expanded, err := c.expandCredential(v)
 			if err != nil {
 				return fmt.Errorf("endpoints[%d] (%s): headers[%s]: %w", i, ep.Name, k, err)
 			}
+			if redact.IsSensitiveParam(k) {
+				c.addCredential(expanded)
+			}
 			ep.Headers[k] = expanded
 		}
 
@@ -402,6 +1099,18 @@ func (c *Config) expandAndValidate() error {
 		if err := validateExtractor(&ep.Extractor, fmt.Sprintf("endpoints[%d] (%s)", i, ep.Name)); err != nil {
 			return err
 		}
+
+		if err := validateDamping(ep); err != nil {
+			return fmt.Errorf("endpoints[%d] (%s): %w", i, ep.Name, err)
+		}
+
+		if err := validateRetries(ep); err != nil {
+			return fmt.Errorf("endpoints[%d] (%s): %w", i, ep.Name, err)
+		}
+
+		if err := validateLatencyThresholds(ep.LatencyThresholds); err != nil {
+			return fmt.Errorf("endpoints[%d] (%s): %w", i, ep.Name, err)
+		}
 	}
 
 	for i := range c.Grids {
@@ -414,7 +1123,7 @@ func (c *Config) expandAndValidate() error {
 		if g.URLTemplate == "" {
 			return fmt.Errorf("grids[%d] (%s): url_template is required", i, g.Name)
 		}
-		expanded, err := expandEnvVars(g.URLTemplate)
+		expanded, err := c.expandURL(g.URLTemplate)
 		if err != nil {
 			return fmt.Errorf("grids[%d] (%s): url_template: %w", i, g.Name, err)
 		}
@@ -425,6 +1134,21 @@ func (c *Config) expandAndValidate() error {
 			return fmt.Errorf("grids[%d] (%s): invalid url_template: %w", i, g.Name, err)
 		}
 
+		if g.BodyTemplate != "" {
+			expanded, err := c.expandCredential(g.BodyTemplate)
+			if err != nil {
+				return fmt.Errorf("grids[%d] (%s): body_template: %w", i, g.Name, err)
+			}
+			g.BodyTemplate = expanded
+
+			if _, err := template.New("").Parse(g.BodyTemplate); err != nil {
+				return fmt.Errorf("grids[%d] (%s): invalid body_template: %w", i, g.Name, err)
+			}
+			if g.Method == "GET" || g.Method == "HEAD" {
+				return fmt.Errorf("grids[%d] (%s): body_template requires method POST, got %s", i, g.Name, g.Method)
+			}
+		}
+
 		if len(g.Dimensions) == 0 {
 			return fmt.Errorf("grids[%d] (%s): at least one dimension is required", i, g.Name)
 		}
@@ -442,10 +1166,13 @@ func (c *Config) expandAndValidate() error {
 		}
 
 		for k, v := range g.Headers {
-			expanded, err := expandEnvVars(v)
+			expanded, err := c.expandCredential(v)
 			if err != nil {
 				return fmt.Errorf("grids[%d] (%s): headers[%s]: %w", i, g.Name, k, err)
 			}
+			if redact.IsSensitiveParam(k) {
+				c.addCredential(expanded)
+			}
 			g.Headers[k] = expanded
 		}
 
@@ -487,6 +1214,182 @@ func (c *Config) expandAndValidate() error {
 	return nil
 }
 
+// validateDamping validates the threshold and flap detection fields of an
+// endpoint.
+func validateDamping(ep *EndpointConfig) error {
+	if ep.FailureThreshold < 0 {
+		return fmt.Errorf("failure_threshold cannot be negative, got %d", ep.FailureThreshold)
+	}
+	if ep.SuccessThreshold < 0 {
+		return fmt.Errorf("success_threshold cannot be negative, got %d", ep.SuccessThreshold)
+	}
+	if f := ep.FlapDetection; f != nil {
+		if f.Transitions < 2 {
+			return fmt.Errorf("flap_detection.transitions must be at least 2, got %d", f.Transitions)
+		}
+		if f.Window.Duration() <= 0 {
+			return errors.New("flap_detection.window must be positive")
+		}
+	}
+	return nil
+}
+
+// validateRetries validates the retry fields of an endpoint.
+func validateRetries(ep *EndpointConfig) error {
+	if ep.Retries < 0 || ep.Retries > 10 {
+		return fmt.Errorf("retries must be between 0 and 10, got %d", ep.Retries)
+	}
+	if ep.RetryBackoff < 0 {
+		return fmt.Errorf("retry_backoff cannot be negative, got %s", ep.RetryBackoff.Duration())
+	}
+	if ep.Retries == 0 && (ep.RetryBackoff != 0 || ep.RetrySharedTimeout) {
+		return errors.New("retry_backoff and retry_shared_timeout require retries")
+	}
+	return nil
+}
+
+// validateLatencyThresholds validates an endpoint's latency thresholds, if set.
+func validateLatencyThresholds(lt *LatencyThresholdsConfig) error {
+	if lt == nil {
+		return nil
+	}
+	if lt.Degraded < 0 || lt.Down < 0 {
+		return errors.New("latency_thresholds cannot be negative")
+	}
+	if lt.Degraded == 0 && lt.Down == 0 {
+		return errors.New("latency_thresholds requires degraded or down")
+	}
+	if lt.Degraded > 0 && lt.Down > 0 && lt.Degraded >= lt.Down {
+		return fmt.Errorf("latency_thresholds.degraded (%s) must be less than down (%s)",
+			lt.Degraded.Duration(), lt.Down.Duration())
+	}
+	return nil
+}
+
+// validateHTTPBody validates the request body fields of an http endpoint and
+// expands environment variables in Body.
+func (c *Config) validateHTTPBody(ep *EndpointConfig) error {
+	if ep.Body == "" && ep.GraphQL == nil {
+		return nil
+	}
+	if ep.Body != "" && ep.GraphQL != nil {
+		return errors.New("body and graphql are mutually exclusive")
+	}
+	if ep.GraphQL != nil && strings.TrimSpace(ep.GraphQL.Query) == "" {
+		return errors.New("graphql.query is required")
+	}
+	if ep.Method == "GET" || ep.Method == "HEAD" {
+		return fmt.Errorf("a request body requires method POST, got %s", ep.Method)
+	}
+
+	expanded, err := c.expandCredential(ep.Body)
+	if err != nil {
+		return fmt.Errorf("body: %w", err)
+	}
+	ep.Body = expanded
+	return nil
+}
+
+// validateTCPEndpoint validates the tcp-specific fields of an endpoint and
+// expands environment variables in Send.
+func (c *Config) validateTCPEndpoint(ep *EndpointConfig, u *url.URL) error {
+	if u.Scheme != "tcp" {
+		return fmt.Errorf("url scheme must be tcp for tcp endpoints, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return errors.New("url must be tcp://host:port")
+	}
+	if ep.Method != "" || len(ep.Headers) > 0 || ep.Extractor.Type != "" {
+		return errors.New("method, headers and extractor are not supported on tcp endpoints")
+	}
+	if ep.CertExpiryWarning != 0 {
+		return errors.New("cert_expiry_warning is not supported on tcp endpoints")
+	}
+
+	expanded, err := c.expandCredential(ep.Send)
+	if err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+	ep.Send = expanded
+	return nil
+}
+
+// validateTLSEndpoint validates the fields of a tls endpoint.
+func validateTLSEndpoint(ep *EndpointConfig, u *url.URL) error {
+	if u.Scheme != "tls" {
+		return fmt.Errorf("url scheme must be tls for tls endpoints, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return errors.New("url must be tls://host:port")
+	}
+	if ep.Method != "" || len(ep.Headers) > 0 || ep.Extractor.Type != "" {
+		return errors.New("method, headers and extractor are not supported on tls endpoints")
+	}
+	if ep.Send != "" || ep.Expect != "" {
+		return errors.New("send and expect are only valid for tcp endpoints")
+	}
+	return nil
+}
+
+// validateDNSEndpoint validates the fields of a dns endpoint and normalises
+// its record type.
+func (c *Config) validateDNSEndpoint(ep *EndpointConfig, u *url.URL) error {
+	if u.Scheme != "dns" {
+		return fmt.Errorf("url scheme must be dns for dns endpoints, got %q", u.Scheme)
+	}
+	if u.Host == "" || u.Port() != "" {
+		return errors.New("url must be dns://name")
+	}
+	if ep.Method != "" || len(ep.Headers) > 0 || ep.Extractor.Type != "" {
+		return errors.New("method, headers and extractor are not supported on dns endpoints")
+	}
+	if ep.Send != "" || ep.Expect != "" {
+		return errors.New("send and expect are only valid for tcp endpoints")
+	}
+	if ep.CertExpiryWarning != 0 {
+		return errors.New("cert_expiry_warning is not supported on dns endpoints")
+	}
+
+	if ep.RecordType != "" {
+		ep.RecordType = strings.ToUpper(ep.RecordType)
+		switch ep.RecordType {
+		case "A", "AAAA", "CNAME", "TXT", "SRV":
+		default:
+			return fmt.Errorf("unsupported record_type %q (expected A, AAAA, CNAME, TXT or SRV)", ep.RecordType)
+		}
+	}
+
+	expanded, err := c.expandEnvVars(ep.Resolver)
+	if err != nil {
+		return fmt.Errorf("resolver: %w", err)
+	}
+	ep.Resolver = expanded
+	return nil
+}
+
+// validateGRPCEndpoint validates the fields of a grpc endpoint.
+func validateGRPCEndpoint(ep *EndpointConfig, u *url.URL) error {
+	if u.Scheme != "grpc" {
+		return fmt.Errorf("url scheme must be grpc for grpc endpoints, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return errors.New("url must be grpc://host:port")
+	}
+	if ep.Method != "" || ep.Extractor.Type != "" {
+		return errors.New("method and extractor are not supported on grpc endpoints")
+	}
+	if ep.Send != "" || ep.Expect != "" {
+		return errors.New("send and expect are only valid for tcp endpoints")
+	}
+	if ep.CertExpiryWarning != 0 {
+		return errors.New("cert_expiry_warning is not supported on grpc endpoints")
+	}
+	if ep.Insecure && ep.TLSSkipVerify {
+		return errors.New("insecure and tls_skip_verify cannot both be set")
+	}
+	return nil
+}
+
 // validateExtractor validates an extractor configuration.
 func validateExtractor(e *ExtractorConfig, context string) error {
 	if e.Type == "" {
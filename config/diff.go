@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jpalmerr/pulseboard"
+)
+
+// EndpointChanges describes how the endpoints of two configs differ.
+// Endpoints are matched by name; grid endpoints are compared individually,
+// so adding a dimension value only adds the new combinations.
+type EndpointChanges struct {
+	// Added holds endpoints present only in the new config.
+	Added []pulseboard.Endpoint
+
+	// Updated holds endpoints whose configuration changed.
+	Updated []pulseboard.Endpoint
+
+	// Removed holds the names of endpoints present only in the old config.
+	Removed []string
+
+	// Unchanged holds the names of endpoints configured identically in both.
+	Unchanged []string
+}
+
+// Empty reports whether no endpoint was added, updated or removed.
+func (c EndpointChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// DiffEndpoints compares the endpoints of prev and next.
+//
+// Returns an error if next's endpoints cannot be built or two of them share
+// a name, in which case next should be rejected.
+func DiffEndpoints(prev, next *Config) (EndpointChanges, error) {
+	var changes EndpointChanges
+
+	prevConfigs, err := expandEndpoints(prev)
+	if err != nil {
+		return changes, err
+	}
+	nextConfigs, err := expandEndpoints(next)
+	if err != nil {
+		return changes, err
+	}
+
+	byName := make(map[string]EndpointConfig, len(prevConfigs))
+	for _, ec := range prevConfigs {
+		byName[ec.Name] = ec
+	}
+
+	seen := make(map[string]bool, len(nextConfigs))
+	for _, ec := range nextConfigs {
+		if seen[ec.Name] {
+			return EndpointChanges{}, fmt.Errorf("duplicate endpoint name: %q", ec.Name)
+		}
+		seen[ec.Name] = true
+
+		old, existed := byName[ec.Name]
+		if existed && reflect.DeepEqual(old, ec) {
+			changes.Unchanged = append(changes.Unchanged, ec.Name)
+			continue
+		}
+
+		ep, err := buildEndpoint(ec)
+		if err != nil {
+			return EndpointChanges{}, err
+		}
+		if existed {
+			changes.Updated = append(changes.Updated, ep)
+		} else {
+			changes.Added = append(changes.Added, ep)
+		}
+	}
+
+	for _, ec := range prevConfigs {
+		if !seen[ec.Name] {
+			changes.Removed = append(changes.Removed, ec.Name)
+		}
+	}
+
+	return changes, nil
+}
+
+// KeepEndpoints returns a copy of next in which the named endpoints are
+// configured as in prev, or left out if prev does not have them. A reloader
+// uses it to record the configuration actually running when some changes
+// failed to apply, so that the next diff includes them again. Grid endpoints
+// are listed individually in the copy's Endpoints.
+func KeepEndpoints(prev, next *Config, names []string) (*Config, error) {
+	if len(names) == 0 {
+		return next, nil
+	}
+	prevConfigs, err := expandEndpoints(prev)
+	if err != nil {
+		return nil, err
+	}
+	nextConfigs, err := expandEndpoints(next)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(names))
+	for _, name := range names {
+		keep[name] = true
+	}
+	byName := make(map[string]EndpointConfig, len(prevConfigs))
+	for _, ec := range prevConfigs {
+		byName[ec.Name] = ec
+	}
+
+	endpoints := make([]EndpointConfig, 0, len(nextConfigs))
+	inNext := make(map[string]bool, len(nextConfigs))
+	for _, ec := range nextConfigs {
+		inNext[ec.Name] = true
+		if !keep[ec.Name] {
+			endpoints = append(endpoints, ec)
+		} else if old, ok := byName[ec.Name]; ok {
+			endpoints = append(endpoints, old)
+		}
+	}
+	// endpoints that failed to be removed are still running
+	for _, ec := range prevConfigs {
+		if keep[ec.Name] && !inNext[ec.Name] {
+			endpoints = append(endpoints, ec)
+		}
+	}
+
+	kept := *next
+	kept.Endpoints, kept.Grids = endpoints, nil
+	return &kept, nil
+}
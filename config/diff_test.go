@@ -0,0 +1,159 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jpalmerr/pulseboard"
+)
+
+func mustParse(t *testing.T, yaml string) *Config {
+	t.Helper()
+	cfg, err := Parse([]byte(yaml))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return cfg
+}
+
+func endpointNames(eps []pulseboard.Endpoint) []string {
+	var names []string
+	for _, ep := range eps {
+		names = append(names, ep.Name())
+	}
+	return names
+}
+
+func TestDiffEndpoints(t *testing.T) {
+	prev := mustParse(t, `
+endpoints:
+  - name: Same
+    url: https://same.example.com
+  - name: Changed
+    url: https://changed.example.com
+  - name: Gone
+    url: https://gone.example.com
+grids:
+  - name: API
+    url_template: https://{{.env}}.example.com
+    dimensions:
+      env: [prod, staging]
+`)
+	next := mustParse(t, `
+endpoints:
+  - name: Same
+    url: https://same.example.com
+  - name: Changed
+    url: https://changed.example.com
+    timeout: 3s
+  - name: New
+    url: https://new.example.com
+grids:
+  - name: API
+    url_template: https://{{.env}}.example.com
+    dimensions:
+      env: [prod, staging, dev]
+`)
+
+	changes, err := DiffEndpoints(prev, next)
+	if err != nil {
+		t.Fatalf("DiffEndpoints() error = %v", err)
+	}
+
+	if got, want := endpointNames(changes.Added), []string{"New", "API dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+	if got, want := endpointNames(changes.Updated), []string{"Changed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Updated = %v, want %v", got, want)
+	}
+	if want := []string{"Gone"}; !reflect.DeepEqual(changes.Removed, want) {
+		t.Errorf("Removed = %v, want %v", changes.Removed, want)
+	}
+	if want := []string{"Same", "API prod", "API staging"}; !reflect.DeepEqual(changes.Unchanged, want) {
+		t.Errorf("Unchanged = %v, want %v", changes.Unchanged, want)
+	}
+	if changes.Empty() {
+		t.Error("Empty() = true, want false")
+	}
+}
+
+func TestDiffEndpoints_NoChanges(t *testing.T) {
+	yaml := `
+endpoints:
+  - name: Same
+    url: https://same.example.com
+    labels:
+      env: prod
+`
+	changes, err := DiffEndpoints(mustParse(t, yaml), mustParse(t, yaml))
+	if err != nil {
+		t.Fatalf("DiffEndpoints() error = %v", err)
+	}
+	if !changes.Empty() {
+		t.Errorf("changes = %+v, want none", changes)
+	}
+}
+
+func TestDiffEndpoints_DuplicateNames(t *testing.T) {
+	prev := mustParse(t, `
+endpoints:
+  - name: A
+    url: https://a.example.com
+`)
+	next := mustParse(t, `
+endpoints:
+  - name: API prod
+    url: https://a.example.com
+grids:
+  - name: API
+    url_template: https://{{.env}}.example.com
+    dimensions:
+      env: [prod]
+`)
+	_, err := DiffEndpoints(prev, next)
+	if err == nil || !strings.Contains(err.Error(), `duplicate endpoint name: "API prod"`) {
+		t.Errorf("DiffEndpoints() error = %v, want duplicate name error", err)
+	}
+}
+
+func TestKeepEndpoints(t *testing.T) {
+	prev := mustParse(t, `
+endpoints:
+  - name: Same
+    url: https://same.example.com
+  - name: Changed
+    url: https://old.example.com
+  - name: Gone
+    url: https://gone.example.com
+`)
+	next := mustParse(t, `
+endpoints:
+  - name: Same
+    url: https://same.example.com
+  - name: Changed
+    url: https://new.example.com
+  - name: New
+    url: https://new.example.com
+`)
+
+	kept, err := KeepEndpoints(prev, next, []string{"Changed", "Gone", "New"})
+	if err != nil {
+		t.Fatalf("KeepEndpoints() error = %v", err)
+	}
+
+	// every failed change shows up again in the next diff
+	changes, err := DiffEndpoints(kept, next)
+	if err != nil {
+		t.Fatalf("DiffEndpoints() error = %v", err)
+	}
+	if got, want := endpointNames(changes.Added), []string{"New"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+	if got, want := endpointNames(changes.Updated), []string{"Changed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Updated = %v, want %v", got, want)
+	}
+	if want := []string{"Gone"}; !reflect.DeepEqual(changes.Removed, want) {
+		t.Errorf("Removed = %v, want %v", changes.Removed, want)
+	}
+}
@@ -2,12 +2,53 @@ package pulseboard
 
 import (
 	"errors"
+	"net"
+	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
 const defaultEndpointTimeout = 10 * time.Second
 
+// EndpointType identifies how an [Endpoint] is checked.
+type EndpointType string
+
+const (
+	// EndpointTypeHTTP polls a URL over HTTP(S) and interprets the response
+	// with a [StatusExtractor]. Created with [NewEndpoint].
+	EndpointTypeHTTP EndpointType = "http"
+
+	// EndpointTypeTCP opens a TCP connection to host:port, optionally
+	// exchanging bytes. Created with [NewTCPEndpoint].
+	EndpointTypeTCP EndpointType = "tcp"
+
+	// EndpointTypeTLS performs a TLS handshake with host:port and checks the
+	// server certificate's validity and expiry. Created with [NewTLSEndpoint].
+	EndpointTypeTLS EndpointType = "tls"
+
+	// EndpointTypeDNS resolves a name and optionally checks the answers.
+	// Created with [NewDNSEndpoint].
+	EndpointTypeDNS EndpointType = "dns"
+
+	// EndpointTypeGRPC calls the gRPC Health Checking Protocol
+	// (grpc.health.v1.Health/Check). Created with [NewGRPCEndpoint].
+	EndpointTypeGRPC EndpointType = "grpc"
+)
+
+// DNS record types accepted by [WithRecordType].
+const (
+	RecordTypeA     = "A"
+	RecordTypeAAAA  = "AAAA"
+	RecordTypeCNAME = "CNAME"
+	RecordTypeTXT   = "TXT"
+	RecordTypeSRV   = "SRV"
+)
+
+// defaultCertExpiryWarning is how long before expiry a TLS endpoint turns
+// degraded when [WithCertExpiryWarning] is not set.
+const defaultCertExpiryWarning = 21 * 24 * time.Hour
+
 // Endpoint represents a target URL to monitor for health status.
 //
 // Endpoint is immutable after creation via [NewEndpoint]. All fields are
@@ -26,6 +67,46 @@ type Endpoint struct {
 	extractor StatusExtractor
 	method    string
 	interval  time.Duration
+	body      string
+	graphQL   bool
+
+	endpointType EndpointType
+	send         string
+	expect       string
+	certWarning  time.Duration
+
+	recordType      string
+	resolver        string
+	expectedAnswers []string
+
+	grpcService   string
+	insecure      bool
+	tlsSkipVerify bool
+
+	damping damping
+	retry   retryPolicy
+	latency latencyThresholds
+}
+
+// latencyThresholds holds the latency limits shared by all endpoint types.
+type latencyThresholds struct {
+	degraded time.Duration
+	down     time.Duration
+}
+
+// retryPolicy holds the retry settings shared by all endpoint types.
+type retryPolicy struct {
+	retries      int
+	backoff      time.Duration
+	shareTimeout bool
+}
+
+// damping holds the status damping settings shared by all endpoint types.
+type damping struct {
+	failureThreshold int
+	successThreshold int
+	flapTransitions  int
+	flapWindow       time.Duration
 }
 
 // Name returns the endpoint's display name.
@@ -69,10 +150,59 @@ func (e Endpoint) Extractor() StatusExtractor {
 
 // Method returns the HTTP method for health check requests.
 // Returns empty string if not explicitly set, which means GET will be used.
+// Endpoints with a request body default to POST.
 func (e Endpoint) Method() string {
 	return e.method
 }
 
+// Body returns the request body sent with each poll.
+// Returns empty string if no body is set.
+func (e Endpoint) Body() string {
+	return e.body
+}
+
+// GraphQL reports whether the endpoint was configured with [WithGraphQL].
+func (e Endpoint) GraphQL() bool {
+	return e.graphQL
+}
+
+// FailureThreshold returns the number of consecutive non-up results required
+// before the published status changes away from up. Defaults to 1.
+func (e Endpoint) FailureThreshold() int {
+	return max(e.damping.failureThreshold, 1)
+}
+
+// SuccessThreshold returns the number of consecutive up results required
+// before the published status changes to up. Defaults to 1.
+func (e Endpoint) SuccessThreshold() int {
+	return max(e.damping.successThreshold, 1)
+}
+
+// FlapDetection returns the flap detection settings set with
+// [WithFlapDetection]. transitions is zero if flap detection is disabled.
+func (e Endpoint) FlapDetection() (transitions int, window time.Duration) {
+	return e.damping.flapTransitions, e.damping.flapWindow
+}
+
+// Retries returns the number of retries made after a failed attempt within
+// a single poll, and the backoff before the first retry. Zero retries means
+// retries are disabled.
+func (e Endpoint) Retries() (retries int, backoff time.Duration) {
+	return e.retry.retries, e.retry.backoff
+}
+
+// SharedRetryTimeout reports whether all attempts of a poll share the
+// endpoint's timeout. See [WithSharedRetryTimeout].
+func (e Endpoint) SharedRetryTimeout() bool {
+	return e.retry.shareTimeout
+}
+
+// LatencyThresholds returns the latencies above which the endpoint is
+// degraded and down. A zero value means that threshold is not set.
+func (e Endpoint) LatencyThresholds() (degraded, down time.Duration) {
+	return e.latency.degraded, e.latency.down
+}
+
 // Interval returns the endpoint's custom polling interval.
 // Returns 0 if no custom interval was specified, meaning the global
 // polling interval configured via [WithPollingInterval] should be used.
@@ -80,6 +210,76 @@ func (e Endpoint) Interval() time.Duration {
 	return e.interval
 }
 
+// Type returns how the endpoint is checked.
+// Returns [EndpointTypeHTTP] for endpoints created with [NewEndpoint],
+// [EndpointTypeTCP] for [NewTCPEndpoint], [EndpointTypeTLS] for
+// [NewTLSEndpoint], [EndpointTypeDNS] for [NewDNSEndpoint] and
+// [EndpointTypeGRPC] for [NewGRPCEndpoint].
+func (e Endpoint) Type() EndpointType {
+	if e.endpointType == "" {
+		return EndpointTypeHTTP
+	}
+	return e.endpointType
+}
+
+// Send returns the data written after connecting to a TCP endpoint.
+// Returns empty string if not set via [WithSend].
+func (e Endpoint) Send() string {
+	return e.send
+}
+
+// Expect returns the text a TCP endpoint must respond with to be up.
+// Returns empty string if not set via [WithExpect].
+func (e Endpoint) Expect() string {
+	return e.expect
+}
+
+// CertExpiryWarning returns how long before certificate expiry the endpoint
+// is reported as degraded. Returns 0 if certificate checks are disabled,
+// which is the default for HTTPS endpoints.
+func (e Endpoint) CertExpiryWarning() time.Duration {
+	return e.certWarning
+}
+
+// RecordType returns the DNS record type looked up by a DNS endpoint.
+// Returns empty string for other endpoint types.
+func (e Endpoint) RecordType() string {
+	return e.recordType
+}
+
+// Resolver returns the host:port of the DNS server queried by a DNS endpoint.
+// Returns empty string if the system resolver is used.
+func (e Endpoint) Resolver() string {
+	return e.resolver
+}
+
+// ExpectedAnswers returns a copy of the answers a DNS endpoint must resolve to.
+// Returns nil if any answer is accepted.
+func (e Endpoint) ExpectedAnswers() []string {
+	if e.expectedAnswers == nil {
+		return nil
+	}
+	return append([]string(nil), e.expectedAnswers...)
+}
+
+// GRPCService returns the service name sent in a gRPC health check.
+// Returns empty string if the server's overall health is checked.
+func (e Endpoint) GRPCService() string {
+	return e.grpcService
+}
+
+// Insecure reports whether a gRPC endpoint is called over plaintext HTTP/2
+// instead of TLS.
+func (e Endpoint) Insecure() bool {
+	return e.insecure
+}
+
+// TLSSkipVerify reports whether a gRPC endpoint skips verification of the
+// server's certificate.
+func (e Endpoint) TLSSkipVerify() bool {
+	return e.tlsSkipVerify
+}
+
 // NewEndpoint creates an [Endpoint] with the given name, URL, and options.
 //
 // The name parameter is a human-readable identifier displayed in the dashboard.
@@ -110,9 +310,10 @@ func NewEndpoint(name, rawURL string, opts ...EndpointOption) (Endpoint, error)
 	}
 
 	cfg := &endpointConfig{
-		labels:  make(map[string]string),
-		headers: make(map[string]string),
-		timeout: defaultEndpointTimeout,
+		labels:       make(map[string]string),
+		headers:      make(map[string]string),
+		timeout:      defaultEndpointTimeout,
+		endpointType: EndpointTypeHTTP,
 	}
 
 	for _, opt := range opts {
@@ -121,18 +322,329 @@ func NewEndpoint(name, rawURL string, opts ...EndpointOption) (Endpoint, error)
 		}
 	}
 
+	if cfg.certWarning > 0 && parsedURL.Scheme != "https" {
+		return Endpoint{}, errors.New("certificate expiry checks require an https:// URL")
+	}
+
+	if cfg.body != "" {
+		switch cfg.method {
+		case "":
+			cfg.method = http.MethodPost
+		case http.MethodGet, http.MethodHead:
+			return Endpoint{}, errors.New("request body requires POST method, got " + cfg.method)
+		}
+		if cfg.contentType != "" && !hasHeader(cfg.headers, "Content-Type") {
+			cfg.headers["Content-Type"] = cfg.contentType
+		}
+	}
+
 	return Endpoint{
-		name:      name,
-		url:       rawURL,
-		labels:    cfg.labels,
-		headers:   cfg.headers,
-		timeout:   cfg.timeout,
-		extractor: cfg.extractor,
-		method:    cfg.method,
-		interval:  cfg.interval,
+		name:         name,
+		url:          rawURL,
+		labels:       cfg.labels,
+		headers:      cfg.headers,
+		timeout:      cfg.timeout,
+		extractor:    cfg.extractor,
+		method:       cfg.method,
+		interval:     cfg.interval,
+		body:         cfg.body,
+		graphQL:      cfg.graphQL,
+		endpointType: EndpointTypeHTTP,
+		certWarning:  cfg.certWarning,
+		damping:      cfg.damping,
+		retry:        cfg.retry,
+		latency:      cfg.latency,
 	}, nil
 }
 
+// NewTCPEndpoint creates an [Endpoint] that checks a TCP port by connecting to it.
+//
+// Use this for services that don't speak HTTP, such as databases, caches and
+// mail servers. The address may be given as "host:port" or "tcp://host:port".
+// The endpoint is up if the connection succeeds, and the reported latency is
+// the connect time.
+//
+// [WithSend] and [WithExpect] add an optional byte exchange after connecting,
+// for example to verify an SMTP banner. [WithLabels], [WithTimeout] and
+// [WithInterval] behave as for HTTP endpoints. HTTP-only options
+// ([WithHeaders], [WithMethod], [WithExtractor]) are rejected.
+//
+// Returns an error if the name is empty or the address is not host:port.
+//
+// Example:
+//
+//	smtp, err := pulseboard.NewTCPEndpoint("Mail", "tcp://mail.internal:25",
+//	    pulseboard.WithExpect("220"),
+//	    pulseboard.WithTimeout(3 * time.Second),
+//	)
+func NewTCPEndpoint(name, address string, opts ...EndpointOption) (Endpoint, error) {
+	if name == "" {
+		return Endpoint{}, errors.New("endpoint name cannot be empty")
+	}
+
+	hostPort := strings.TrimPrefix(address, "tcp://")
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return Endpoint{}, errors.New("invalid TCP address: " + err.Error())
+	}
+	if host == "" || port == "" {
+		return Endpoint{}, errors.New("TCP address must be host:port")
+	}
+
+	cfg := &endpointConfig{
+		labels:       make(map[string]string),
+		headers:      make(map[string]string),
+		timeout:      defaultEndpointTimeout,
+		endpointType: EndpointTypeTCP,
+	}
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return Endpoint{}, err
+		}
+	}
+
+	if len(cfg.headers) > 0 || cfg.method != "" || cfg.extractor != nil {
+		return Endpoint{}, errors.New("headers, method and extractor are not supported on TCP endpoints")
+	}
+
+	return Endpoint{
+		name:         name,
+		url:          "tcp://" + hostPort,
+		labels:       cfg.labels,
+		headers:      cfg.headers,
+		timeout:      cfg.timeout,
+		interval:     cfg.interval,
+		endpointType: EndpointTypeTCP,
+		send:         cfg.send,
+		expect:       cfg.expect,
+		damping:      cfg.damping,
+		retry:        cfg.retry,
+		latency:      cfg.latency,
+	}, nil
+}
+
+// NewTLSEndpoint creates an [Endpoint] that checks the certificate served on a
+// TLS port.
+//
+// The address may be given as "host:port" or "tls://host:port". Each poll
+// performs a TLS handshake and verifies the certificate chain against the
+// host name. The endpoint is:
+//   - [StatusDown] if the handshake fails, the chain does not verify, or the
+//     certificate has expired
+//   - [StatusDegraded] if the certificate expires within the warning window
+//     (21 days unless set with [WithCertExpiryWarning])
+//   - [StatusUp] otherwise
+//
+// The certificate's expiry, issuer and names are reported in
+// [StatusResult].Cert. [WithLabels], [WithTimeout] and [WithInterval] behave
+// as for HTTP endpoints. HTTP-only options ([WithHeaders], [WithMethod],
+// [WithExtractor]) are rejected.
+//
+// To check the certificate of an HTTPS endpoint alongside its normal health
+// check, use [WithCertExpiryWarning] with [NewEndpoint] instead.
+//
+// Example:
+//
+//	cert, err := pulseboard.NewTLSEndpoint("Mail TLS", "mail.example.com:465",
+//	    pulseboard.WithCertExpiryWarning(14 * 24 * time.Hour),
+//	)
+func NewTLSEndpoint(name, address string, opts ...EndpointOption) (Endpoint, error) {
+	if name == "" {
+		return Endpoint{}, errors.New("endpoint name cannot be empty")
+	}
+
+	hostPort := strings.TrimPrefix(address, "tls://")
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return Endpoint{}, errors.New("invalid TLS address: " + err.Error())
+	}
+	if host == "" || port == "" {
+		return Endpoint{}, errors.New("TLS address must be host:port")
+	}
+
+	cfg := &endpointConfig{
+		labels:       make(map[string]string),
+		headers:      make(map[string]string),
+		timeout:      defaultEndpointTimeout,
+		endpointType: EndpointTypeTLS,
+		certWarning:  defaultCertExpiryWarning,
+	}
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return Endpoint{}, err
+		}
+	}
+
+	if len(cfg.headers) > 0 || cfg.method != "" || cfg.extractor != nil {
+		return Endpoint{}, errors.New("headers, method and extractor are not supported on TLS endpoints")
+	}
+
+	return Endpoint{
+		name:         name,
+		url:          "tls://" + hostPort,
+		labels:       cfg.labels,
+		headers:      cfg.headers,
+		timeout:      cfg.timeout,
+		interval:     cfg.interval,
+		endpointType: EndpointTypeTLS,
+		certWarning:  cfg.certWarning,
+		damping:      cfg.damping,
+		retry:        cfg.retry,
+		latency:      cfg.latency,
+	}, nil
+}
+
+// NewDNSEndpoint creates an [Endpoint] that checks a DNS name resolves.
+//
+// The host may be given as "api.example.com" or "dns://api.example.com". By
+// default an A lookup is made through the system resolver; use
+// [WithRecordType] and [WithResolver] to change either. The endpoint is:
+//   - [StatusDown] if the lookup fails, including NXDOMAIN and SERVFAIL
+//   - [StatusDegraded] if [WithExpectedAnswers] is set and the answers differ
+//   - [StatusUp] otherwise
+//
+// The answers are available in [StatusResult].RawResponse, one per line.
+// [WithLabels], [WithTimeout] and [WithInterval] behave as for HTTP
+// endpoints. HTTP-only options ([WithHeaders], [WithMethod],
+// [WithExtractor]) are rejected.
+//
+// Example:
+//
+//	dns, err := pulseboard.NewDNSEndpoint("API DNS", "api.example.com",
+//	    pulseboard.WithResolver("10.0.0.2:53"),
+//	    pulseboard.WithExpectedAnswers("203.0.113.10", "203.0.113.11"),
+//	)
+func NewDNSEndpoint(name, host string, opts ...EndpointOption) (Endpoint, error) {
+	if name == "" {
+		return Endpoint{}, errors.New("endpoint name cannot be empty")
+	}
+
+	host = strings.TrimPrefix(host, "dns://")
+	if host == "" || strings.ContainsAny(host, ":/ ") {
+		return Endpoint{}, errors.New("DNS endpoint requires a host name")
+	}
+
+	cfg := &endpointConfig{
+		labels:       make(map[string]string),
+		headers:      make(map[string]string),
+		timeout:      defaultEndpointTimeout,
+		endpointType: EndpointTypeDNS,
+		recordType:   RecordTypeA,
+	}
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return Endpoint{}, err
+		}
+	}
+
+	if len(cfg.headers) > 0 || cfg.method != "" || cfg.extractor != nil {
+		return Endpoint{}, errors.New("headers, method and extractor are not supported on DNS endpoints")
+	}
+
+	return Endpoint{
+		name:            name,
+		url:             "dns://" + host,
+		labels:          cfg.labels,
+		headers:         cfg.headers,
+		timeout:         cfg.timeout,
+		interval:        cfg.interval,
+		endpointType:    EndpointTypeDNS,
+		recordType:      cfg.recordType,
+		resolver:        cfg.resolver,
+		expectedAnswers: cfg.expectedAnswers,
+		damping:         cfg.damping,
+		retry:           cfg.retry,
+		latency:         cfg.latency,
+	}, nil
+}
+
+// NewGRPCEndpoint creates an [Endpoint] that checks a gRPC server using the
+// gRPC Health Checking Protocol.
+//
+// Each poll calls grpc.health.v1.Health/Check. The address may be given as
+// "host:port" or "grpc://host:port". The reported serving status maps to:
+//   - SERVING: [StatusUp]
+//   - NOT_SERVING, SERVICE_UNKNOWN: [StatusDown]
+//   - UNKNOWN: [StatusUnknown]
+//
+// Failed calls, including non-OK gRPC status codes, are [StatusDown].
+//
+// Calls use TLS by default. Use [WithInsecure] for plaintext servers and
+// [WithTLSSkipVerify] for servers with self-signed certificates.
+// [WithGRPCService] checks a specific service instead of the whole server.
+// [WithHeaders] sets call metadata, for example authorization tokens.
+// [WithMethod] and [WithExtractor] are rejected.
+//
+// Example:
+//
+//	orders, err := pulseboard.NewGRPCEndpoint("Orders", "orders.internal:50051",
+//	    pulseboard.WithGRPCService("orders.v1.OrderService"),
+//	    pulseboard.WithHeaders("authorization", "Bearer "+token),
+//	)
+func NewGRPCEndpoint(name, address string, opts ...EndpointOption) (Endpoint, error) {
+	if name == "" {
+		return Endpoint{}, errors.New("endpoint name cannot be empty")
+	}
+
+	hostPort := strings.TrimPrefix(address, "grpc://")
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return Endpoint{}, errors.New("invalid gRPC address: " + err.Error())
+	}
+	if host == "" || port == "" {
+		return Endpoint{}, errors.New("gRPC address must be host:port")
+	}
+
+	cfg := &endpointConfig{
+		labels:       make(map[string]string),
+		headers:      make(map[string]string),
+		timeout:      defaultEndpointTimeout,
+		endpointType: EndpointTypeGRPC,
+	}
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return Endpoint{}, err
+		}
+	}
+
+	if cfg.method != "" || cfg.extractor != nil {
+		return Endpoint{}, errors.New("method and extractor are not supported on gRPC endpoints")
+	}
+	if cfg.insecure && cfg.tlsSkipVerify {
+		return Endpoint{}, errors.New("WithInsecure and WithTLSSkipVerify cannot be combined")
+	}
+
+	return Endpoint{
+		name:          name,
+		url:           "grpc://" + hostPort,
+		labels:        cfg.labels,
+		headers:       cfg.headers,
+		timeout:       cfg.timeout,
+		interval:      cfg.interval,
+		endpointType:  EndpointTypeGRPC,
+		grpcService:   cfg.grpcService,
+		insecure:      cfg.insecure,
+		tlsSkipVerify: cfg.tlsSkipVerify,
+		damping:       cfg.damping,
+		retry:         cfg.retry,
+		latency:       cfg.latency,
+	}, nil
+}
+
+// hasHeader reports whether headers contains key, ignoring case.
+func hasHeader(headers map[string]string, key string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
 // copyMap returns a shallow copy of the map.
 func copyMap(m map[string]string) map[string]string {
 	if m == nil {
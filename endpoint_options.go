@@ -1,8 +1,12 @@
 package pulseboard
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -14,6 +18,27 @@ type endpointConfig struct {
 	extractor StatusExtractor
 	method    string
 	interval  time.Duration
+
+	body        string
+	contentType string
+	graphQL     bool
+
+	endpointType EndpointType
+	send         string
+	expect       string
+	certWarning  time.Duration
+
+	recordType      string
+	resolver        string
+	expectedAnswers []string
+
+	grpcService   string
+	insecure      bool
+	tlsSkipVerify bool
+
+	damping damping
+	retry   retryPolicy
+	latency latencyThresholds
 }
 
 // EndpointOption is a function that configures an [Endpoint] during construction.
@@ -22,7 +47,12 @@ type endpointConfig struct {
 // configuration to be passed to [NewEndpoint] in a type-safe, extensible way.
 // Options return an error if validation fails.
 //
-// Built-in options: [WithLabels], [WithHeaders], [WithTimeout], [WithExtractor].
+// Built-in options: [WithLabels], [WithHeaders], [WithTimeout], [WithExtractor],
+// [WithMethod], [WithInterval], [WithSend], [WithExpect], [WithCertExpiryWarning],
+// [WithRecordType], [WithResolver], [WithExpectedAnswers], [WithGRPCService],
+// [WithInsecure], [WithTLSSkipVerify], [WithBody], [WithJSONBody], [WithGraphQL],
+// [WithFailureThreshold], [WithSuccessThreshold], [WithFlapDetection],
+// [WithRetries], [WithSharedRetryTimeout], [WithLatencyThresholds].
 type EndpointOption func(*endpointConfig) error
 
 // WithLabels adds metadata labels to the endpoint for grouping and filtering.
@@ -54,7 +84,8 @@ func WithLabels(keyValues ...string) EndpointOption {
 // WithHeaders adds custom HTTP headers to poll requests for this endpoint.
 //
 // Use this for endpoints that require authentication or custom headers.
-// Headers are sent with every poll request to this endpoint.
+// Headers are sent with every poll request to this endpoint. On gRPC
+// endpoints they are sent as call metadata.
 //
 // Accepts variadic key-value pairs. The number of arguments must be even.
 //
@@ -124,7 +155,8 @@ func WithExtractor(e StatusExtractor) EndpointOption {
 // endpoints where you only need to check reachability without downloading
 // the response body. Use POST for health endpoints that require it.
 //
-// If not specified, GET is used.
+// If not specified, GET is used, or POST when a request body is set with
+// [WithBody], [WithJSONBody] or [WithGraphQL].
 //
 // Example:
 //
@@ -157,8 +189,9 @@ func WithMethod(method string) EndpointOption {
 // If not specified, the endpoint uses the global polling interval
 // configured via [WithPollingInterval].
 //
-// Note: The interval is measured from when a poll starts, not when it completes.
-// For slow endpoints, effective interval = configured interval + poll duration.
+// Note: The interval is measured from when a poll is due, not when it completes.
+// If a poll is still in flight when the next one is due, that poll is skipped,
+// so an endpoint is never polled concurrently with itself.
 //
 // Example:
 //
@@ -181,3 +214,476 @@ func WithInterval(d time.Duration) EndpointOption {
 		return nil
 	}
 }
+
+// WithSend sets data to write after connecting to a TCP endpoint.
+//
+// Use this with [WithExpect] for protocols that answer a request, such as
+// Redis PING. Only valid on endpoints created with [NewTCPEndpoint].
+//
+// Example:
+//
+//	redis, err := pulseboard.NewTCPEndpoint("Redis", "redis.internal:6379",
+//	    pulseboard.WithSend("PING\r\n"),
+//	    pulseboard.WithExpect("+PONG"),
+//	)
+//
+// Returns an error if used on a non-TCP endpoint.
+func WithSend(data string) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeTCP {
+			return errors.New("WithSend is only supported on TCP endpoints")
+		}
+		cfg.send = data
+		return nil
+	}
+}
+
+// WithExpect sets text that a TCP endpoint must send back for it to be up.
+//
+// After connecting (and writing any [WithSend] data), the response is read
+// until it contains the expected text or the timeout elapses. If the text is
+// not found, the endpoint is [StatusDown]. Only valid on endpoints created
+// with [NewTCPEndpoint].
+//
+// Example:
+//
+//	smtp, err := pulseboard.NewTCPEndpoint("Mail", "mail.internal:25",
+//	    pulseboard.WithExpect("220"),
+//	)
+//
+// Returns an error if used on a non-TCP endpoint.
+func WithExpect(text string) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeTCP {
+			return errors.New("WithExpect is only supported on TCP endpoints")
+		}
+		cfg.expect = text
+		return nil
+	}
+}
+
+// WithCertExpiryWarning sets how long before certificate expiry the endpoint
+// is reported as [StatusDegraded].
+//
+// On endpoints created with [NewTLSEndpoint] this overrides the default
+// 21-day window. On HTTPS endpoints created with [NewEndpoint] it enables a
+// certificate check alongside the normal health check: the endpoint is
+// degraded when the certificate is about to expire, even if the response
+// itself is healthy. The certificate details are reported in
+// [StatusResult].Cert.
+//
+// Example:
+//
+//	api, err := pulseboard.NewEndpoint("API", "https://api.example.com/health",
+//	    pulseboard.WithCertExpiryWarning(14 * 24 * time.Hour),
+//	)
+//
+// Returns an error if the duration is not positive, or if used on a TCP or
+// DNS endpoint. [NewEndpoint] also rejects it for non-https URLs.
+func WithCertExpiryWarning(d time.Duration) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if d <= 0 {
+			return errors.New("certificate expiry warning must be positive")
+		}
+		if cfg.endpointType != EndpointTypeHTTP && cfg.endpointType != EndpointTypeTLS {
+			return errors.New("WithCertExpiryWarning is only supported on TLS and HTTPS endpoints")
+		}
+		cfg.certWarning = d
+		return nil
+	}
+}
+
+// WithRecordType sets the DNS record type looked up by a DNS endpoint.
+//
+// Supported types are [RecordTypeA] (the default), [RecordTypeAAAA],
+// [RecordTypeCNAME], [RecordTypeTXT] and [RecordTypeSRV]. The type is
+// case-insensitive. Only valid on endpoints created with [NewDNSEndpoint].
+//
+// Example:
+//
+//	ep, err := pulseboard.NewDNSEndpoint("SPF", "example.com",
+//	    pulseboard.WithRecordType(pulseboard.RecordTypeTXT),
+//	)
+//
+// Returns an error if the type is unsupported or used on a non-DNS endpoint.
+func WithRecordType(recordType string) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeDNS {
+			return errors.New("WithRecordType is only supported on DNS endpoints")
+		}
+		rt := strings.ToUpper(recordType)
+		switch rt {
+		case RecordTypeA, RecordTypeAAAA, RecordTypeCNAME, RecordTypeTXT, RecordTypeSRV:
+			cfg.recordType = rt
+			return nil
+		default:
+			return errors.New("unsupported record type " + recordType + " (expected A, AAAA, CNAME, TXT or SRV)")
+		}
+	}
+}
+
+// WithResolver sets the DNS server queried by a DNS endpoint instead of the
+// system resolver.
+//
+// The address is host:port; if the port is omitted, 53 is used. Only valid
+// on endpoints created with [NewDNSEndpoint].
+//
+// Example:
+//
+//	ep, err := pulseboard.NewDNSEndpoint("Internal DNS", "db.corp.internal",
+//	    pulseboard.WithResolver("10.0.0.2"),
+//	)
+//
+// Returns an error if the address is empty or used on a non-DNS endpoint.
+func WithResolver(address string) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeDNS {
+			return errors.New("WithResolver is only supported on DNS endpoints")
+		}
+		if address == "" {
+			return errors.New("resolver address cannot be empty")
+		}
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(strings.Trim(address, "[]"), "53")
+		}
+		cfg.resolver = address
+		return nil
+	}
+}
+
+// WithExpectedAnswers sets the answers a DNS endpoint must resolve to.
+//
+// Answers are compared as a set: order, duplicates, letter case and trailing
+// dots are ignored, and IP addresses are compared by value. If the lookup
+// succeeds but returns a different set, the endpoint is [StatusDegraded].
+// SRV answers are written as "priority weight port target". Only valid on
+// endpoints created with [NewDNSEndpoint].
+//
+// Example:
+//
+//	ep, err := pulseboard.NewDNSEndpoint("API DNS", "api.example.com",
+//	    pulseboard.WithExpectedAnswers("203.0.113.10", "203.0.113.11"),
+//	)
+//
+// Returns an error if no answers are given or used on a non-DNS endpoint.
+func WithExpectedAnswers(answers ...string) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeDNS {
+			return errors.New("WithExpectedAnswers is only supported on DNS endpoints")
+		}
+		if len(answers) == 0 {
+			return errors.New("WithExpectedAnswers requires at least one answer")
+		}
+		cfg.expectedAnswers = append([]string(nil), answers...)
+		return nil
+	}
+}
+
+// WithGRPCService sets the service name sent in a gRPC health check.
+//
+// Servers report health per service, conventionally keyed by the fully
+// qualified service name. Without this option the server's overall health is
+// checked. Only valid on endpoints created with [NewGRPCEndpoint].
+//
+// Example:
+//
+//	ep, err := pulseboard.NewGRPCEndpoint("Orders", "orders.internal:50051",
+//	    pulseboard.WithGRPCService("orders.v1.OrderService"),
+//	)
+//
+// Returns an error if used on a non-gRPC endpoint.
+func WithGRPCService(service string) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeGRPC {
+			return errors.New("WithGRPCService is only supported on gRPC endpoints")
+		}
+		cfg.grpcService = service
+		return nil
+	}
+}
+
+// WithInsecure calls a gRPC endpoint over plaintext HTTP/2 instead of TLS.
+//
+// Use this for servers started without transport credentials, typically
+// inside a private network. Only valid on endpoints created with
+// [NewGRPCEndpoint].
+//
+// Returns an error if used on a non-gRPC endpoint.
+func WithInsecure() EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeGRPC {
+			return errors.New("WithInsecure is only supported on gRPC endpoints")
+		}
+		cfg.insecure = true
+		return nil
+	}
+}
+
+// WithTLSSkipVerify disables verification of a gRPC server's certificate.
+//
+// The connection is still encrypted, but the server's identity is not
+// checked. Use this for servers with self-signed certificates. Only valid on
+// endpoints created with [NewGRPCEndpoint].
+//
+// Returns an error if used on a non-gRPC endpoint.
+func WithTLSSkipVerify() EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeGRPC {
+			return errors.New("WithTLSSkipVerify is only supported on gRPC endpoints")
+		}
+		cfg.tlsSkipVerify = true
+		return nil
+	}
+}
+
+// WithBody sets the request body sent with each poll.
+//
+// The body is sent as-is; set a Content-Type with [WithHeaders] if the server
+// requires one. If no method is set with [WithMethod], POST is used.
+//
+// Example:
+//
+//	ep, err := pulseboard.NewEndpoint("Search", "https://search.internal/_search",
+//	    pulseboard.WithBody(`{"size":0}`),
+//	    pulseboard.WithHeaders("Content-Type", "application/json"),
+//	)
+//
+// Returns an error if used on a non-HTTP endpoint. [NewEndpoint] also
+// rejects a body combined with GET or HEAD.
+func WithBody(body string) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeHTTP {
+			return errors.New("WithBody is only supported on HTTP endpoints")
+		}
+		cfg.body = body
+		cfg.contentType = ""
+		cfg.graphQL = false
+		return nil
+	}
+}
+
+// WithJSONBody sets the request body to the JSON encoding of v.
+//
+// The Content-Type header is set to application/json unless one is given
+// with [WithHeaders]. If no method is set with [WithMethod], POST is used.
+//
+// Example:
+//
+//	ep, err := pulseboard.NewEndpoint("Orders", "https://orders.internal/check",
+//	    pulseboard.WithJSONBody(map[string]any{"dry_run": true}),
+//	)
+//
+// Returns an error if v cannot be encoded or if used on a non-HTTP endpoint.
+func WithJSONBody(v any) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeHTTP {
+			return errors.New("WithJSONBody is only supported on HTTP endpoints")
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return errors.New("WithJSONBody: " + err.Error())
+		}
+		cfg.body = string(b)
+		cfg.contentType = "application/json"
+		cfg.graphQL = false
+		return nil
+	}
+}
+
+// WithGraphQL makes the endpoint a GraphQL query.
+//
+// The query and optional variables are POSTed as a standard GraphQL request
+// with Content-Type application/json. GraphQL servers usually report failures
+// with a 200 status and an errors array in the body, so a response with a
+// non-empty errors array is [StatusDown] regardless of the extractor's result.
+//
+// Example:
+//
+//	ep, err := pulseboard.NewEndpoint("GraphQL", "https://api.example.com/graphql",
+//	    pulseboard.WithGraphQL(`query($id: ID!) { service(id: $id) { healthy } }`,
+//	        map[string]any{"id": "checkout"}),
+//	    pulseboard.WithExtractor(pulseboard.JSONFieldExtractor("data.service.healthy")),
+//	)
+//
+// Returns an error if the query is empty, the variables cannot be encoded,
+// or if used on a non-HTTP endpoint.
+func WithGraphQL(query string, variables map[string]any) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if cfg.endpointType != EndpointTypeHTTP {
+			return errors.New("WithGraphQL is only supported on HTTP endpoints")
+		}
+		if query == "" {
+			return errors.New("GraphQL query cannot be empty")
+		}
+		payload := struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables,omitempty"`
+		}{query, variables}
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return errors.New("WithGraphQL: " + err.Error())
+		}
+		cfg.body = string(b)
+		cfg.contentType = "application/json"
+		cfg.graphQL = true
+		return nil
+	}
+}
+
+// WithFailureThreshold sets how many consecutive failed checks are needed
+// before the endpoint's published status changes away from [StatusUp].
+//
+// A single dropped packet or slow response then no longer flips the card or
+// fires status callbacks with a down status. The status produced by each
+// check is still available in [StatusResult.RawStatus].
+//
+// The default of 1 publishes every change immediately. Applies to all
+// endpoint types.
+//
+// Example:
+//
+//	ep, err := pulseboard.NewEndpoint("API", url,
+//	    pulseboard.WithFailureThreshold(3),
+//	)
+//
+// Returns an error if n is less than 1.
+func WithFailureThreshold(n int) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if n < 1 {
+			return errors.New("failure threshold must be at least 1")
+		}
+		cfg.damping.failureThreshold = n
+		return nil
+	}
+}
+
+// WithSuccessThreshold sets how many consecutive successful checks are
+// needed before the endpoint's published status changes back to [StatusUp].
+//
+// The default of 1 publishes recovery immediately. Applies to all endpoint
+// types.
+//
+// Returns an error if n is less than 1.
+func WithSuccessThreshold(n int) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if n < 1 {
+			return errors.New("success threshold must be at least 1")
+		}
+		cfg.damping.successThreshold = n
+		return nil
+	}
+}
+
+// WithFlapDetection marks the endpoint as flapping when its checks change
+// status at least transitions times within window.
+//
+// While flapping, the published status is held at its last value and
+// [StatusResult.Flapping] is set, instead of the status oscillating. The
+// endpoint stops flapping once the changes age out of the window. Applies to
+// all endpoint types.
+//
+// Example:
+//
+//	// flapping after 5 status changes within 10 minutes
+//	pulseboard.WithFlapDetection(5, 10*time.Minute)
+//
+// Returns an error if transitions is less than 2 or window is not positive.
+func WithFlapDetection(transitions int, window time.Duration) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if transitions < 2 {
+			return errors.New("flap detection requires at least 2 transitions")
+		}
+		if window <= 0 {
+			return errors.New("flap detection window must be positive")
+		}
+		cfg.damping.flapTransitions = transitions
+		cfg.damping.flapWindow = window
+		return nil
+	}
+}
+
+// maxRetries caps [WithRetries] so a single poll cannot run indefinitely.
+const maxRetries = 10
+
+// WithRetries retries a failed check up to n times within a single poll
+// before the result is reported.
+//
+// Attempts that fail with a transport error or timeout, or with a 5xx or 429
+// response, are retried. Definite answers, such as unexpected DNS records,
+// a NOT_SERVING health check or an expiring certificate, are reported
+// without retrying. The first retry waits for backoff, and the wait
+// doubles for each retry after that. A Retry-After header on 429 and 503
+// responses is honoured when it asks for longer; if it asks for more than
+// 30 seconds the poll stops retrying.
+//
+// The reported [StatusResult] records the number of attempts in Attempts and
+// the retried attempts' errors in AttemptErrors. Applies to all endpoint
+// types.
+//
+// Example:
+//
+//	ep, err := pulseboard.NewEndpoint("API", url,
+//	    pulseboard.WithRetries(2, 500*time.Millisecond),
+//	)
+//
+// Returns an error if n is not between 1 and 10, or backoff is negative.
+func WithRetries(n int, backoff time.Duration) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if n < 1 || n > maxRetries {
+			return fmt.Errorf("retries must be between 1 and %d, got %d", maxRetries, n)
+		}
+		if backoff < 0 {
+			return errors.New("retry backoff cannot be negative")
+		}
+		cfg.retry.retries = n
+		cfg.retry.backoff = backoff
+		return nil
+	}
+}
+
+// WithSharedRetryTimeout makes all attempts of a poll, and the waits between
+// them, fit within the endpoint's timeout set by [WithTimeout].
+//
+// By default each attempt gets the full timeout, so a poll with retries can
+// take several times longer than the timeout. Has no effect without
+// [WithRetries].
+func WithSharedRetryTimeout() EndpointOption {
+	return func(cfg *endpointConfig) error {
+		cfg.retry.shareTimeout = true
+		return nil
+	}
+}
+
+// WithLatencyThresholds marks the endpoint [StatusDegraded] when a check takes
+// longer than degraded, and [StatusDown] when it takes longer than down.
+//
+// Extractors only see the response, so without thresholds a slow but
+// successful response is up. Thresholds are applied after extraction and can
+// only make the status worse. The reason, such as "latency 8.1s > 2s", is
+// reported as the result's error. Applies to all endpoint types.
+//
+// Either threshold may be zero to leave it unset.
+//
+// Example:
+//
+//	ep, err := pulseboard.NewEndpoint("API", url,
+//	    pulseboard.WithLatencyThresholds(2*time.Second, 5*time.Second),
+//	)
+//
+// Returns an error if either threshold is negative, both are zero, or
+// degraded is not less than down.
+func WithLatencyThresholds(degraded, down time.Duration) EndpointOption {
+	return func(cfg *endpointConfig) error {
+		if degraded < 0 || down < 0 {
+			return errors.New("latency thresholds cannot be negative")
+		}
+		if degraded == 0 && down == 0 {
+			return errors.New("at least one latency threshold is required")
+		}
+		if degraded > 0 && down > 0 && degraded >= down {
+			return errors.New("degraded latency threshold must be less than the down threshold")
+		}
+		cfg.latency = latencyThresholds{degraded: degraded, down: down}
+		return nil
+	}
+}
@@ -402,3 +402,602 @@ func TestWithInterval_WithOtherOptions(t *testing.T) {
 		t.Errorf("Labels()[env] = %v, want %v", ep.Labels()["env"], "prod")
 	}
 }
+
+func TestNewEndpoint_TypeIsHTTP(t *testing.T) {
+	ep, err := NewEndpoint("Test", "https://api.example.com/health")
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+	if ep.Type() != EndpointTypeHTTP {
+		t.Errorf("Type() = %q, want %q", ep.Type(), EndpointTypeHTTP)
+	}
+}
+
+func TestNewTCPEndpoint_Valid(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantURL string
+	}{
+		{"host:port", "db.internal:5432", "tcp://db.internal:5432"},
+		{"tcp scheme", "tcp://db.internal:5432", "tcp://db.internal:5432"},
+		{"ipv6", "[::1]:6379", "tcp://[::1]:6379"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep, err := NewTCPEndpoint("DB", tt.address)
+			if err != nil {
+				t.Fatalf("NewTCPEndpoint() error = %v", err)
+			}
+			if ep.Type() != EndpointTypeTCP {
+				t.Errorf("Type() = %q, want %q", ep.Type(), EndpointTypeTCP)
+			}
+			if ep.URL() != tt.wantURL {
+				t.Errorf("URL() = %q, want %q", ep.URL(), tt.wantURL)
+			}
+			if ep.Timeout() != 10*time.Second {
+				t.Errorf("Timeout() = %v, want %v", ep.Timeout(), 10*time.Second)
+			}
+		})
+	}
+}
+
+func TestNewTCPEndpoint_InvalidAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+	}{
+		{"empty", ""},
+		{"missing port", "db.internal"},
+		{"missing host", ":5432"},
+		{"empty port", "db.internal:"},
+		{"http url", "https://db.internal:5432"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTCPEndpoint("DB", tt.address); err == nil {
+				t.Errorf("NewTCPEndpoint() expected error for address %q, got nil", tt.address)
+			}
+		})
+	}
+}
+
+func TestNewTCPEndpoint_EmptyName(t *testing.T) {
+	if _, err := NewTCPEndpoint("", "db.internal:5432"); err == nil {
+		t.Error("NewTCPEndpoint() expected error for empty name, got nil")
+	}
+}
+
+func TestNewTCPEndpoint_SendExpect(t *testing.T) {
+	ep, err := NewTCPEndpoint("Redis", "redis.internal:6379",
+		WithSend("PING\r\n"),
+		WithExpect("+PONG"),
+		WithLabels("tier", "cache"),
+		WithInterval(5*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("NewTCPEndpoint() error = %v", err)
+	}
+
+	if ep.Send() != "PING\r\n" {
+		t.Errorf("Send() = %q, want %q", ep.Send(), "PING\r\n")
+	}
+	if ep.Expect() != "+PONG" {
+		t.Errorf("Expect() = %q, want %q", ep.Expect(), "+PONG")
+	}
+	if ep.Labels()["tier"] != "cache" {
+		t.Errorf("Labels()[tier] = %q, want %q", ep.Labels()["tier"], "cache")
+	}
+	if ep.Interval() != 5*time.Second {
+		t.Errorf("Interval() = %v, want %v", ep.Interval(), 5*time.Second)
+	}
+}
+
+func TestNewTCPEndpoint_RejectsHTTPOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  EndpointOption
+	}{
+		{"headers", WithHeaders("Authorization", "Bearer x")},
+		{"method", WithMethod("HEAD")},
+		{"extractor", WithExtractor(HTTPStatusExtractor)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTCPEndpoint("DB", "db.internal:5432", tt.opt); err == nil {
+				t.Errorf("NewTCPEndpoint() expected error for %s option, got nil", tt.name)
+			}
+		})
+	}
+}
+
+func TestWithSendExpect_RejectedOnHTTP(t *testing.T) {
+	if _, err := NewEndpoint("Test", "https://example.com", WithSend("PING")); err == nil {
+		t.Error("NewEndpoint() expected error for WithSend, got nil")
+	}
+	if _, err := NewEndpoint("Test", "https://example.com", WithExpect("ok")); err == nil {
+		t.Error("NewEndpoint() expected error for WithExpect, got nil")
+	}
+}
+
+func TestNewTLSEndpoint_Valid(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantURL string
+	}{
+		{"host:port", "mail.example.com:465", "tls://mail.example.com:465"},
+		{"tls scheme", "tls://mail.example.com:465", "tls://mail.example.com:465"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep, err := NewTLSEndpoint("Mail", tt.address)
+			if err != nil {
+				t.Fatalf("NewTLSEndpoint() error = %v", err)
+			}
+			if ep.Type() != EndpointTypeTLS {
+				t.Errorf("Type() = %q, want %q", ep.Type(), EndpointTypeTLS)
+			}
+			if ep.URL() != tt.wantURL {
+				t.Errorf("URL() = %q, want %q", ep.URL(), tt.wantURL)
+			}
+			if ep.CertExpiryWarning() != defaultCertExpiryWarning {
+				t.Errorf("CertExpiryWarning() = %v, want %v", ep.CertExpiryWarning(), defaultCertExpiryWarning)
+			}
+		})
+	}
+}
+
+func TestNewTLSEndpoint_InvalidAddress(t *testing.T) {
+	for _, address := range []string{"mail.example.com", "tls://", ":465"} {
+		if _, err := NewTLSEndpoint("Mail", address); err == nil {
+			t.Errorf("NewTLSEndpoint() expected error for address %q, got nil", address)
+		}
+	}
+}
+
+func TestNewTLSEndpoint_RejectsUnsupportedOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  EndpointOption
+	}{
+		{"headers", WithHeaders("Authorization", "Bearer x")},
+		{"method", WithMethod("HEAD")},
+		{"extractor", WithExtractor(HTTPStatusExtractor)},
+		{"send", WithSend("PING")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTLSEndpoint("Mail", "mail.example.com:465", tt.opt); err == nil {
+				t.Errorf("NewTLSEndpoint() expected error for %s option, got nil", tt.name)
+			}
+		})
+	}
+}
+
+func TestWithCertExpiryWarning(t *testing.T) {
+	tls, err := NewTLSEndpoint("Mail", "mail.example.com:465", WithCertExpiryWarning(7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("NewTLSEndpoint() error = %v", err)
+	}
+	if tls.CertExpiryWarning() != 7*24*time.Hour {
+		t.Errorf("TLS CertExpiryWarning() = %v, want 168h", tls.CertExpiryWarning())
+	}
+
+	https, err := NewEndpoint("API", "https://api.example.com", WithCertExpiryWarning(14*24*time.Hour))
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+	if https.CertExpiryWarning() != 14*24*time.Hour {
+		t.Errorf("HTTPS CertExpiryWarning() = %v, want 336h", https.CertExpiryWarning())
+	}
+
+	plain, err := NewEndpoint("API", "https://api.example.com")
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+	if plain.CertExpiryWarning() != 0 {
+		t.Errorf("default HTTPS CertExpiryWarning() = %v, want 0", plain.CertExpiryWarning())
+	}
+}
+
+func TestWithCertExpiryWarning_Invalid(t *testing.T) {
+	if _, err := NewEndpoint("API", "http://api.example.com", WithCertExpiryWarning(time.Hour)); err == nil {
+		t.Error("NewEndpoint() expected error for http:// URL, got nil")
+	}
+	if _, err := NewTLSEndpoint("Mail", "mail.example.com:465", WithCertExpiryWarning(0)); err == nil {
+		t.Error("NewTLSEndpoint() expected error for zero warning, got nil")
+	}
+	if _, err := NewTCPEndpoint("DB", "db.internal:5432", WithCertExpiryWarning(time.Hour)); err == nil {
+		t.Error("NewTCPEndpoint() expected error for WithCertExpiryWarning, got nil")
+	}
+}
+
+func TestNewDNSEndpoint_Defaults(t *testing.T) {
+	for _, host := range []string{"api.example.com", "dns://api.example.com"} {
+		ep, err := NewDNSEndpoint("API DNS", host)
+		if err != nil {
+			t.Fatalf("NewDNSEndpoint(%q) error = %v", host, err)
+		}
+		if ep.Type() != EndpointTypeDNS {
+			t.Errorf("Type() = %q, want %q", ep.Type(), EndpointTypeDNS)
+		}
+		if ep.URL() != "dns://api.example.com" {
+			t.Errorf("URL() = %q, want %q", ep.URL(), "dns://api.example.com")
+		}
+		if ep.RecordType() != RecordTypeA {
+			t.Errorf("RecordType() = %q, want %q", ep.RecordType(), RecordTypeA)
+		}
+		if ep.Resolver() != "" {
+			t.Errorf("Resolver() = %q, want empty", ep.Resolver())
+		}
+		if ep.ExpectedAnswers() != nil {
+			t.Errorf("ExpectedAnswers() = %v, want nil", ep.ExpectedAnswers())
+		}
+	}
+}
+
+func TestNewDNSEndpoint_Options(t *testing.T) {
+	ep, err := NewDNSEndpoint("SIP", "_sip._tcp.example.com",
+		WithRecordType("srv"),
+		WithResolver("10.0.0.2"),
+		WithExpectedAnswers("10 5 5060 sip.example.com"),
+	)
+	if err != nil {
+		t.Fatalf("NewDNSEndpoint() error = %v", err)
+	}
+
+	if ep.RecordType() != RecordTypeSRV {
+		t.Errorf("RecordType() = %q, want %q", ep.RecordType(), RecordTypeSRV)
+	}
+	if ep.Resolver() != "10.0.0.2:53" {
+		t.Errorf("Resolver() = %q, want %q", ep.Resolver(), "10.0.0.2:53")
+	}
+
+	answers := ep.ExpectedAnswers()
+	if len(answers) != 1 || answers[0] != "10 5 5060 sip.example.com" {
+		t.Fatalf("ExpectedAnswers() = %v, want SRV answer", answers)
+	}
+	answers[0] = "changed"
+	if ep.ExpectedAnswers()[0] == "changed" {
+		t.Error("ExpectedAnswers() returned a slice aliasing the endpoint")
+	}
+}
+
+func TestNewDNSEndpoint_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		opts []EndpointOption
+	}{
+		{"empty host", "", nil},
+		{"host with port", "api.example.com:53", nil},
+		{"unsupported record type", "api.example.com", []EndpointOption{WithRecordType("MX")}},
+		{"empty resolver", "api.example.com", []EndpointOption{WithResolver("")}},
+		{"no expected answers", "api.example.com", []EndpointOption{WithExpectedAnswers()}},
+		{"headers", "api.example.com", []EndpointOption{WithHeaders("X-Foo", "bar")}},
+		{"cert warning", "api.example.com", []EndpointOption{WithCertExpiryWarning(time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDNSEndpoint("DNS", tt.host, tt.opts...); err == nil {
+				t.Error("NewDNSEndpoint() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestDNSOptions_RejectedOnHTTP(t *testing.T) {
+	opts := []EndpointOption{
+		WithRecordType("A"),
+		WithResolver("10.0.0.2:53"),
+		WithExpectedAnswers("10.0.0.1"),
+	}
+	for _, opt := range opts {
+		if _, err := NewEndpoint("Test", "https://example.com", opt); err == nil {
+			t.Error("NewEndpoint() expected error for DNS option, got nil")
+		}
+	}
+}
+
+func TestNewGRPCEndpoint(t *testing.T) {
+	ep, err := NewGRPCEndpoint("Orders", "grpc://orders.internal:50051",
+		WithGRPCService("orders.v1.OrderService"),
+		WithInsecure(),
+		WithHeaders("authorization", "Bearer token"),
+	)
+	if err != nil {
+		t.Fatalf("NewGRPCEndpoint() error = %v", err)
+	}
+
+	if ep.Type() != EndpointTypeGRPC {
+		t.Errorf("Type() = %q, want %q", ep.Type(), EndpointTypeGRPC)
+	}
+	if ep.URL() != "grpc://orders.internal:50051" {
+		t.Errorf("URL() = %q, want %q", ep.URL(), "grpc://orders.internal:50051")
+	}
+	if ep.GRPCService() != "orders.v1.OrderService" {
+		t.Errorf("GRPCService() = %q, want %q", ep.GRPCService(), "orders.v1.OrderService")
+	}
+	if !ep.Insecure() {
+		t.Error("Insecure() = false, want true")
+	}
+	if ep.TLSSkipVerify() {
+		t.Error("TLSSkipVerify() = true, want false")
+	}
+	if ep.Headers()["authorization"] != "Bearer token" {
+		t.Errorf("Headers()[authorization] = %q, want metadata", ep.Headers()["authorization"])
+	}
+}
+
+func TestNewGRPCEndpoint_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		opts    []EndpointOption
+	}{
+		{"missing port", "orders.internal", nil},
+		{"method", "orders.internal:50051", []EndpointOption{WithMethod("POST")}},
+		{"extractor", "orders.internal:50051", []EndpointOption{WithExtractor(HTTPStatusExtractor)}},
+		{"insecure with skip verify", "orders.internal:50051", []EndpointOption{WithInsecure(), WithTLSSkipVerify()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGRPCEndpoint("Orders", tt.address, tt.opts...); err == nil {
+				t.Error("NewGRPCEndpoint() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestGRPCOptions_RejectedOnHTTP(t *testing.T) {
+	for _, opt := range []EndpointOption{WithGRPCService("svc"), WithInsecure(), WithTLSSkipVerify()} {
+		if _, err := NewEndpoint("Test", "https://example.com", opt); err == nil {
+			t.Error("NewEndpoint() expected error for gRPC option, got nil")
+		}
+	}
+}
+
+func TestWithBody(t *testing.T) {
+	ep, err := NewEndpoint("Search", "https://search.example.com/_search",
+		WithBody(`{"size":0}`),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	if ep.Body() != `{"size":0}` {
+		t.Errorf("Body() = %q, want %q", ep.Body(), `{"size":0}`)
+	}
+	if ep.Method() != "POST" {
+		t.Errorf("Method() = %q, want POST when a body is set", ep.Method())
+	}
+	if _, ok := ep.Headers()["Content-Type"]; ok {
+		t.Error("WithBody should not set a Content-Type")
+	}
+}
+
+func TestWithJSONBody(t *testing.T) {
+	ep, err := NewEndpoint("Orders", "https://orders.example.com/check",
+		WithJSONBody(map[string]any{"dry_run": true}),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	if ep.Body() != `{"dry_run":true}` {
+		t.Errorf("Body() = %q, want %q", ep.Body(), `{"dry_run":true}`)
+	}
+	if got := ep.Headers()["Content-Type"]; got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	// an explicit Content-Type wins, regardless of case
+	ep, err = NewEndpoint("Orders", "https://orders.example.com/check",
+		WithJSONBody(map[string]any{"dry_run": true}),
+		WithHeaders("content-type", "application/vnd.api+json"),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+	if _, ok := ep.Headers()["Content-Type"]; ok {
+		t.Error("WithJSONBody overrode an explicit content-type header")
+	}
+}
+
+func TestWithGraphQL(t *testing.T) {
+	ep, err := NewEndpoint("GraphQL", "https://api.example.com/graphql",
+		WithGraphQL("query($id: ID!) { service(id: $id) { healthy } }", map[string]any{"id": "checkout"}),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	want := `{"query":"query($id: ID!) { service(id: $id) { healthy } }","variables":{"id":"checkout"}}`
+	if ep.Body() != want {
+		t.Errorf("Body() = %q, want %q", ep.Body(), want)
+	}
+	if !ep.GraphQL() {
+		t.Error("GraphQL() = false, want true")
+	}
+	if ep.Method() != "POST" {
+		t.Errorf("Method() = %q, want POST", ep.Method())
+	}
+	if got := ep.Headers()["Content-Type"]; got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+}
+
+func TestBodyOptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []EndpointOption
+	}{
+		{"body with GET", []EndpointOption{WithMethod("GET"), WithBody("x")}},
+		{"body with HEAD", []EndpointOption{WithBody("x"), WithMethod("HEAD")}},
+		{"unencodable json", []EndpointOption{WithJSONBody(make(chan int))}},
+		{"empty graphql query", []EndpointOption{WithGraphQL("", nil)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEndpoint("Test", "https://example.com", tt.opts...); err == nil {
+				t.Error("NewEndpoint() expected error, got nil")
+			}
+		})
+	}
+
+	for _, opt := range []EndpointOption{WithBody("x"), WithJSONBody(1), WithGraphQL("{ a }", nil)} {
+		if _, err := NewTCPEndpoint("Test", "db.internal:5432", opt); err == nil {
+			t.Error("NewTCPEndpoint() expected error for body option, got nil")
+		}
+	}
+}
+
+func TestDampingOptions(t *testing.T) {
+	ep, err := NewTCPEndpoint("DB", "db.internal:5432",
+		WithFailureThreshold(3),
+		WithSuccessThreshold(2),
+		WithFlapDetection(5, 10*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("NewTCPEndpoint() error = %v", err)
+	}
+
+	if ep.FailureThreshold() != 3 {
+		t.Errorf("FailureThreshold() = %d, want 3", ep.FailureThreshold())
+	}
+	if ep.SuccessThreshold() != 2 {
+		t.Errorf("SuccessThreshold() = %d, want 2", ep.SuccessThreshold())
+	}
+	if n, w := ep.FlapDetection(); n != 5 || w != 10*time.Minute {
+		t.Errorf("FlapDetection() = %d, %s, want 5, 10m", n, w)
+	}
+}
+
+func TestDampingOptions_Defaults(t *testing.T) {
+	ep, err := NewEndpoint("API", "https://api.example.com")
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	if ep.FailureThreshold() != 1 || ep.SuccessThreshold() != 1 {
+		t.Errorf("thresholds = %d/%d, want 1/1", ep.FailureThreshold(), ep.SuccessThreshold())
+	}
+	if n, _ := ep.FlapDetection(); n != 0 {
+		t.Errorf("FlapDetection() transitions = %d, want 0 (disabled)", n)
+	}
+}
+
+func TestDampingOptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  EndpointOption
+	}{
+		{"zero failure threshold", WithFailureThreshold(0)},
+		{"zero success threshold", WithSuccessThreshold(0)},
+		{"one transition", WithFlapDetection(1, time.Minute)},
+		{"zero window", WithFlapDetection(3, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEndpoint("API", "https://api.example.com", tt.opt); err == nil {
+				t.Error("NewEndpoint() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestWithRetries(t *testing.T) {
+	ep, err := NewEndpoint("API", "https://api.example.com",
+		WithRetries(2, 250*time.Millisecond),
+		WithSharedRetryTimeout(),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	if n, backoff := ep.Retries(); n != 2 || backoff != 250*time.Millisecond {
+		t.Errorf("Retries() = %d, %s, want 2, 250ms", n, backoff)
+	}
+	if !ep.SharedRetryTimeout() {
+		t.Error("SharedRetryTimeout() = false, want true")
+	}
+
+	dns, err := NewDNSEndpoint("DNS", "api.example.com", WithRetries(1, 0))
+	if err != nil {
+		t.Fatalf("NewDNSEndpoint() error = %v", err)
+	}
+	if n, _ := dns.Retries(); n != 1 {
+		t.Errorf("DNS Retries() = %d, want 1", n)
+	}
+}
+
+func TestWithRetries_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  EndpointOption
+	}{
+		{"zero", WithRetries(0, time.Second)},
+		{"too many", WithRetries(11, time.Second)},
+		{"negative backoff", WithRetries(2, -time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEndpoint("API", "https://api.example.com", tt.opt); err == nil {
+				t.Error("NewEndpoint() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestWithLatencyThresholds(t *testing.T) {
+	ep, err := NewEndpoint("API", "https://api.example.com",
+		WithLatencyThresholds(2*time.Second, 5*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+	if degraded, down := ep.LatencyThresholds(); degraded != 2*time.Second || down != 5*time.Second {
+		t.Errorf("LatencyThresholds() = %s, %s, want 2s, 5s", degraded, down)
+	}
+
+	tcp, err := NewTCPEndpoint("DB", "db.internal:5432", WithLatencyThresholds(100*time.Millisecond, 0))
+	if err != nil {
+		t.Fatalf("NewTCPEndpoint() error = %v", err)
+	}
+	if degraded, down := tcp.LatencyThresholds(); degraded != 100*time.Millisecond || down != 0 {
+		t.Errorf("TCP LatencyThresholds() = %s, %s, want 100ms, 0s", degraded, down)
+	}
+}
+
+func TestWithLatencyThresholds_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		degraded time.Duration
+		down     time.Duration
+	}{
+		{"both zero", 0, 0},
+		{"negative", -time.Second, 0},
+		{"degraded above down", 5 * time.Second, 2 * time.Second},
+		{"equal", time.Second, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEndpoint("API", "https://api.example.com", WithLatencyThresholds(tt.degraded, tt.down))
+			if err == nil {
+				t.Error("NewEndpoint() expected error, got nil")
+			}
+		})
+	}
+}
@@ -13,7 +13,9 @@ import (
 // using cartesian product expansion.
 //
 // The URL template uses Go's text/template syntax. Dimension values are URL-encoded
-// before interpolation. Missing template keys cause an error (fail-fast).
+// before interpolation. Missing template keys cause an error (fail-fast). An
+// optional body template set with [WithBodyTemplate] is rendered the same way,
+// without URL encoding.
 //
 // Each endpoint name includes dimension values in the format:
 // "Base Name (val1/val2)" (values from alphabetically sorted keys).
@@ -59,6 +61,14 @@ func NewEndpointGrid(baseName string, opts ...GridOption) ([]Endpoint, error) {
 		return nil, fmt.Errorf("invalid URL template: %w", err)
 	}
 
+	var bodyTmpl *template.Template
+	if cfg.bodyTemplate != "" {
+		bodyTmpl, err = template.New("body").Option("missingkey=error").Parse(cfg.bodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body template: %w", err)
+		}
+	}
+
 	combinations := cartesianProduct(cfg.dimensions)
 	if len(combinations) == 0 {
 		return nil, nil
@@ -97,6 +107,14 @@ func NewEndpointGrid(baseName string, opts ...GridOption) ([]Endpoint, error) {
 		if cfg.interval > 0 {
 			epOpts = append(epOpts, WithInterval(cfg.interval))
 		}
+		if bodyTmpl != nil {
+			// body values are not URL-encoded
+			body, err := executeTemplate(bodyTmpl, combo)
+			if err != nil {
+				return nil, fmt.Errorf("body template execution failed: %w", err)
+			}
+			epOpts = append(epOpts, WithBody(body))
+		}
 
 		ep, err := NewEndpoint(name, urlStr, epOpts...)
 		if err != nil {
@@ -10,6 +10,7 @@ import (
 // gridConfig holds configuration during endpoint grid construction.
 type gridConfig struct {
 	urlTemplate  string
+	bodyTemplate string
 	dimensions   map[string][]string
 	staticLabels map[string]string
 	headers      map[string]string
@@ -41,6 +42,29 @@ func WithURLTemplate(tmpl string) GridOption {
 	}
 }
 
+// WithBodyTemplate sets a request body template for endpoint generation.
+// The template uses Go's text/template syntax with dimension keys as variables,
+// like [WithURLTemplate], but dimension values are interpolated as-is rather
+// than URL-encoded.
+//
+// Generated endpoints send the rendered body as with [WithBody], so they
+// default to POST.
+//
+// Example:
+//
+//	WithBodyTemplate(`{"tenant":"{{.tenant}}","dry_run":true}`)
+//
+// Returns an error if the template string is empty.
+func WithBodyTemplate(tmpl string) GridOption {
+	return func(cfg *gridConfig) error {
+		if tmpl == "" {
+			return errors.New("body template cannot be empty")
+		}
+		cfg.bodyTemplate = tmpl
+		return nil
+	}
+}
+
 // WithDimensions sets the dimension values for cartesian product expansion.
 // Each key in the map becomes a template variable, and the cartesian product
 // of all values generates the endpoint combinations.
@@ -166,8 +190,9 @@ func WithGridMethod(method string) GridOption {
 // The interval must be between 1 second and 1 hour.
 // A zero duration means use the global polling interval.
 //
-// Note: The interval is measured from when a poll starts, not when it completes.
-// For slow endpoints, effective interval = configured interval + poll duration.
+// Note: The interval is measured from when a poll is due, not when it completes.
+// If a poll is still in flight when the next one is due, that poll is skipped,
+// so an endpoint is never polled concurrently with itself.
 //
 // Example:
 //
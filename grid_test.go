@@ -454,6 +454,57 @@ func TestNewEndpointGrid_URLEncoding(t *testing.T) {
 	}
 }
 
+func TestNewEndpointGrid_BodyTemplate(t *testing.T) {
+	endpoints, err := NewEndpointGrid("Tenant",
+		WithURLTemplate("https://api.example.com/check"),
+		WithBodyTemplate(`{"tenant":"{{.tenant}}"}`),
+		WithDimensions(map[string][]string{
+			"tenant": {"acme corp", "globex"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpointGrid() error = %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(endpoints))
+	}
+
+	// values are interpolated without URL encoding
+	if got, want := endpoints[0].Body(), `{"tenant":"acme corp"}`; got != want {
+		t.Errorf("Body() = %q, want %q", got, want)
+	}
+	if got, want := endpoints[1].Body(), `{"tenant":"globex"}`; got != want {
+		t.Errorf("Body() = %q, want %q", got, want)
+	}
+	if endpoints[0].Method() != "POST" {
+		t.Errorf("Method() = %q, want POST", endpoints[0].Method())
+	}
+}
+
+func TestNewEndpointGrid_BodyTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  GridOption
+	}{
+		{"empty", WithBodyTemplate("")},
+		{"invalid syntax", WithBodyTemplate("{{.tenant")},
+		{"missing key", WithBodyTemplate("{{.missing}}")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEndpointGrid("Tenant",
+				WithURLTemplate("https://api.example.com/check"),
+				WithDimensions(map[string][]string{"tenant": {"acme"}}),
+				tt.opt,
+			)
+			if err == nil {
+				t.Error("NewEndpointGrid() expected error, got nil")
+			}
+		})
+	}
+}
+
 func TestNewEndpointGrid_EndpointNaming(t *testing.T) {
 	endpoints, err := NewEndpointGrid("Stream Status",
 		WithURLTemplate("https://api.example.com/health?channel={{.channel}}&env={{.env}}"),
@@ -0,0 +1,71 @@
+package pulseboard
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WithBasePath serves the dashboard, API and SSE stream under path, such as
+// "/status", instead of at the root. It applies both to the server run by
+// [PulseBoard.Start] and to [PulseBoard.Handler].
+//
+// Example:
+//
+//	pb, err := pulseboard.New(
+//	    pulseboard.WithEndpoint(ep),
+//	    pulseboard.WithBasePath("/status"),
+//	)
+//
+// Returns an error if path does not start with "/".
+func WithBasePath(path string) Option {
+	return func(cfg *pbConfig) error {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("base path must start with /, got %q", path)
+		}
+		cfg.basePath = strings.TrimSuffix(path, "/")
+		return nil
+	}
+}
+
+// Handler returns an [http.Handler] serving the dashboard, API and SSE
+// stream under the base path set by [WithBasePath], for mounting on an
+// existing HTTP server. Pair it with [PulseBoard.Run], which polls without
+// listening on a port of its own:
+//
+//	pb, err := pulseboard.New(
+//	    pulseboard.WithEndpoints(endpoints...),
+//	    pulseboard.WithBasePath("/status"),
+//	)
+//	...
+//	mux.Handle("/status/", pb.Handler())
+//	go pb.Run(ctx)
+//
+// The handler may be mounted before Run is called; until Run starts, and
+// after it returns, requests get 503 Service Unavailable. SSE streams end
+// when Run's context is cancelled.
+func (pb *PulseBoard) Handler() http.Handler {
+	return http.HandlerFunc(pb.serveHTTP)
+}
+
+// serveHTTP serves a request with the handler of the running PulseBoard.
+func (pb *PulseBoard) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	pb.mu.RLock()
+	handler, runCtx := pb.handler, pb.runCtx
+	pb.mu.RUnlock()
+
+	if handler == nil {
+		http.Error(w, "PulseBoard is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	// end long-lived requests such as SSE when polling stops, not only when
+	// the embedding server shuts down
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	stop := context.AfterFunc(runCtx, cancel)
+	defer stop()
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,82 @@
+package pulseboard
+
+import (
+	"fmt"
+
+	"github.com/jpalmerr/pulseboard/internal/server"
+)
+
+// HealthRule selects a group of endpoints that must be up for the health
+// check served at /api/health to pass. See [WithHealthCheck].
+//
+// Members are the endpoints whose labels include every pair in Labels, plus
+// those named in Endpoints. A rule that selects no endpoints fails, as do
+// named endpoints that have not been polled yet.
+type HealthRule struct {
+	// Name identifies the rule in the response. Defaults to a description
+	// of the selection.
+	Name string
+
+	// Labels selects endpoints by label, e.g. {"critical": "true"}.
+	Labels map[string]string
+
+	// Endpoints selects endpoints by name.
+	Endpoints []string
+
+	// MinUp is how many members must be up. Zero means all of them.
+	MinUp int
+
+	// AllowDegraded counts degraded members as up.
+	AllowDegraded bool
+}
+
+// WithHealthCheck configures the health check, which responds 200 when
+// every rule passes and 503 otherwise, listing the failing endpoints. It
+// suits a readiness probe that gates traffic on downstream dependencies.
+//
+// The check is served at path, or /api/health if path is empty. With no
+// rules every endpoint must be up, which is also the default without this
+// option.
+//
+// Example:
+//
+//	pulseboard.WithHealthCheck("/healthz",
+//	    pulseboard.HealthRule{Labels: map[string]string{"critical": "true"}},
+//	    pulseboard.HealthRule{Name: "replicas", Labels: map[string]string{"service": "api"}, MinUp: 2},
+//	)
+//
+// Returns an error if path is already served by PulseBoard or does not
+// start with "/", or if a rule has a negative MinUp.
+func WithHealthCheck(path string, rules ...HealthRule) Option {
+	return func(cfg *pbConfig) error {
+		if path == "" {
+			path = server.DefaultHealthPath
+		}
+		if err := server.ValidateHealthPath(path); err != nil {
+			return err
+		}
+		for i, r := range rules {
+			if r.MinUp < 0 {
+				return fmt.Errorf("health rule %d: min up cannot be negative, got %d", i, r.MinUp)
+			}
+		}
+		cfg.healthPath = path
+		cfg.healthRules = rules
+		return nil
+	}
+}
+
+// toServerHealthRules converts health rules to the server's representation.
+func toServerHealthRules(rules []HealthRule) []server.HealthRule {
+	out := make([]server.HealthRule, len(rules))
+	for i, r := range rules {
+		out[i] = server.HealthRule{
+			Name:          r.Name,
+			Labels:        copyMap(r.Labels),
+			Endpoints:     append([]string(nil), r.Endpoints...),
+			MinUp:         r.MinUp,
+			AllowDegraded: r.AllowDegraded,
+		}
+	}
+	return out
+}
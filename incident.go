@@ -0,0 +1,81 @@
+package pulseboard
+
+import (
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+// Incident is a period during which an endpoint was not up.
+//
+// An incident opens when an endpoint's published [Status] leaves
+// [StatusUp], or when its first result is not up, and closes when the
+// endpoint is up again. Incidents are also served at /api/incidents.
+type Incident struct {
+	// EndpointName is the name of the affected endpoint.
+	EndpointName string
+
+	// Labels are the endpoint's labels when the incident opened.
+	Labels map[string]string
+
+	// Start is when the first result that was not up was checked.
+	Start time.Time
+
+	// End is when the endpoint was next up, or the zero time while the
+	// incident is open. An incident whose endpoint is removed ends at the
+	// endpoint's last check.
+	End time.Time
+
+	// Duration is the incident's length, up to the time of the call to
+	// [PulseBoard.Incidents] while it is open.
+	Duration time.Duration
+
+	// WorstStatus is the most severe status seen during the incident.
+	WorstStatus Status
+
+	// FirstError is the first error reported during the incident, or empty.
+	FirstError string
+}
+
+// Open reports whether the incident is still open.
+func (i Incident) Open() bool {
+	return i.End.IsZero()
+}
+
+// Incidents returns all open incidents and the most recent closed ones,
+// newest first. With [FileStore] incidents survive restarts.
+//
+// Returns nil while PulseBoard is not running.
+func (pb *PulseBoard) Incidents() []Incident {
+	pb.mu.RLock()
+	st := pb.store
+	pb.mu.RUnlock()
+	if st == nil {
+		return nil
+	}
+
+	stored := st.Incidents()
+	incidents := make([]Incident, len(stored))
+	for i, inc := range stored {
+		incidents[i] = storeIncidentToPublic(inc)
+	}
+	return incidents
+}
+
+// storeIncidentToPublic converts a stored incident to the public API type.
+func storeIncidentToPublic(inc store.Incident) Incident {
+	out := Incident{
+		EndpointName: inc.Name,
+		Labels:       copyMap(inc.Labels),
+		Start:        inc.StartedAt,
+		Duration:     time.Duration(inc.DurationSeconds * float64(time.Second)),
+		WorstStatus:  Status(inc.WorstStatus),
+	}
+	if inc.EndedAt != nil {
+		out.End = *inc.EndedAt
+	}
+	if inc.FirstError != nil {
+		out.FirstError = *inc.FirstError
+	}
+	return out
+}
@@ -1,7 +1,9 @@
 package poller
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -36,6 +38,32 @@ type Response struct {
 	// Error contains any error that occurred during the request.
 	// nil indicates the request completed (though status may indicate an error).
 	Error error
+
+	// Cert describes the server's leaf certificate for TLS connections.
+	// nil for plain-text connections or if the handshake failed.
+	Cert *CertInfo
+
+	// RetryAfter is the wait requested by the Retry-After header of a 429 or
+	// 503 response. Zero otherwise.
+	RetryAfter time.Duration
+}
+
+// TransportError marks a check that got no answer from the endpoint: it
+// could not be reached, the connection failed or the check timed out.
+// Answers that report a problem, such as an HTTP status, a failed health
+// check or unexpected DNS records, are not transport errors.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string { return e.Err.Error() }
+
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// IsTransportError reports whether err is or wraps a [TransportError].
+func IsTransportError(err error) bool {
+	var te *TransportError
+	return errors.As(err, &te)
 }
 
 // Client is an HTTP client wrapper optimized for polling health endpoints.
@@ -75,13 +103,14 @@ func NewClient() *Client {
 
 // Fetch performs an HTTP request and returns a structured [Response].
 //
-// The request is made with the provided context, method, URL, headers, and timeout.
-// If method is empty, GET is used. The timeout is applied via context cancellation.
+// The request is made with the provided context, method, URL, headers, body and
+// timeout. If method is empty, GET is used. A nil or empty body sends no request
+// body. The timeout is applied via context cancellation.
 // Response bodies are limited to 1MB to prevent memory exhaustion.
 //
 // Fetch always returns a Response; errors are captured in the Error field
 // rather than returned separately. This simplifies handling in the scheduler.
-func (c *Client) Fetch(ctx context.Context, method, url string, headers map[string]string, timeout time.Duration) Response {
+func (c *Client) Fetch(ctx context.Context, method, url string, headers map[string]string, body []byte, timeout time.Duration) Response {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -91,7 +120,12 @@ func (c *Client) Fetch(ctx context.Context, method, url string, headers map[stri
 		method = http.MethodGet
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	var reqBody io.Reader
+	if len(body) > 0 {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return Response{
 			Latency: time.Since(start),
@@ -107,27 +141,34 @@ func (c *Client) Fetch(ctx context.Context, method, url string, headers map[stri
 	if err != nil {
 		return Response{
 			Latency: time.Since(start),
-			Error:   fmt.Errorf("request failed: %w", err),
+			Error:   &TransportError{Err: fmt.Errorf("request failed: %w", err)},
 		}
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	limitedReader := io.LimitReader(resp.Body, maxResponseBodySize)
-	body, err := io.ReadAll(limitedReader)
+	respBody, err := io.ReadAll(limitedReader)
 	if err != nil {
 		return Response{
 			StatusCode: resp.StatusCode,
 			Latency:    time.Since(start),
-			Error:      fmt.Errorf("failed to read response body: %w", err),
+			Error:      &TransportError{Err: fmt.Errorf("failed to read response body: %w", err)},
 		}
 	}
 
-	return Response{
-		Body:       body,
+	result := Response{
+		Body:       respBody,
 		StatusCode: resp.StatusCode,
 		Latency:    time.Since(start),
 		Error:      nil,
 	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		result.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		result.Cert = newCertInfo(resp.TLS.PeerCertificates[0])
+	}
+	return result
 }
 
 // Close closes all idle connections in the client's connection pool.
@@ -2,6 +2,7 @@ package poller
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
@@ -35,7 +36,7 @@ func TestClient_ConnectionReuse(t *testing.T) {
 	// make sequential requests to ensure pool has opportunity to reuse
 	for i := 0; i < numRequests; i++ {
 		ctx := httptrace.WithClientTrace(context.Background(), trace)
-		resp := client.Fetch(ctx, "", server.URL, nil, 5*time.Second)
+		resp := client.Fetch(ctx, "", server.URL, nil, nil, 5*time.Second)
 		if resp.Error != nil {
 			t.Fatalf("request %d failed: %v", i, resp.Error)
 		}
@@ -83,7 +84,7 @@ func TestClient_Close_ActuallyClosesConnections(t *testing.T) {
 
 	// establish connections
 	for i := 0; i < 5; i++ {
-		resp := client.Fetch(context.Background(), "", server.URL, nil, time.Second)
+		resp := client.Fetch(context.Background(), "", server.URL, nil, nil, time.Second)
 		if resp.Error != nil {
 			t.Fatalf("request %d failed: %v", i, resp.Error)
 		}
@@ -93,7 +94,7 @@ func TestClient_Close_ActuallyClosesConnections(t *testing.T) {
 	client.Close()
 
 	// subsequent requests should still work (new connections established)
-	resp := client.Fetch(context.Background(), "", server.URL, nil, time.Second)
+	resp := client.Fetch(context.Background(), "", server.URL, nil, nil, time.Second)
 	if resp.Error != nil {
 		t.Errorf("request after Close failed: %v", resp.Error)
 	}
@@ -101,3 +102,33 @@ func TestClient_Close_ActuallyClosesConnections(t *testing.T) {
 		t.Errorf("expected status 200, got %d", resp.StatusCode)
 	}
 }
+
+// TestClient_FetchSendsBody verifies the request body and method reach the server.
+func TestClient_FetchSendsBody(t *testing.T) {
+	var gotMethod, gotBody, gotType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	headers := map[string]string{"Content-Type": "application/json"}
+	resp := client.Fetch(context.Background(), http.MethodPost, server.URL, headers, []byte(`{"ping":true}`), time.Second)
+	if resp.Error != nil {
+		t.Fatalf("Fetch() error = %v", resp.Error)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %q, want POST", gotMethod)
+	}
+	if gotBody != `{"ping":true}` {
+		t.Errorf("body = %q, want %q", gotBody, `{"ping":true}`)
+	}
+	if gotType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", gotType)
+	}
+}
@@ -0,0 +1,119 @@
+package poller
+
+import "time"
+
+// FlapOptions configures flap detection for an endpoint.
+type FlapOptions struct {
+	// Transitions is the number of raw status changes within Window at which
+	// the endpoint is considered flapping. Zero disables flap detection.
+	Transitions int
+
+	// Window is the period over which transitions are counted.
+	Window time.Duration
+}
+
+// damperState is the per-endpoint state tracked by a [Damper].
+type damperState struct {
+	published string // status currently published
+	candidate string // status the raw results are trending towards
+	streak    int    // consecutive raw results equal to candidate
+	lastRaw   string
+	changes   []time.Time // raw status changes within the flap window
+	flapping  bool
+}
+
+// Damper smooths raw poll results into a published status.
+//
+// The published status only changes once the configured number of
+// consecutive raw results agree on the new status: FailureThreshold for a
+// change to anything other than up, SuccessThreshold for a change to up. The
+// first result for an endpoint is published as-is. While an endpoint is
+// flapping the published status is held and the result is marked Flapping.
+//
+// Damper is not safe for concurrent use; it is meant to be driven by the
+// single goroutine consuming [Scheduler.Results]. Callers changing the
+// endpoint set with [Damper.Set] or [Damper.Remove] must synchronise with
+// that goroutine.
+type Damper struct {
+	endpoints map[string]EndpointInfo
+	states    map[string]*damperState
+}
+
+// NewDamper creates a Damper for the given endpoints.
+func NewDamper(endpoints []EndpointInfo) *Damper {
+	d := &Damper{
+		endpoints: make(map[string]EndpointInfo, len(endpoints)),
+		states:    make(map[string]*damperState, len(endpoints)),
+	}
+	for _, ep := range endpoints {
+		d.endpoints[ep.Name] = ep
+	}
+	return d
+}
+
+// Set adds an endpoint or replaces its configuration. Replacing an endpoint
+// resets its state, so the next result is published as-is.
+func (d *Damper) Set(ep EndpointInfo) {
+	d.endpoints[ep.Name] = ep
+	delete(d.states, ep.Name)
+}
+
+// Remove forgets the named endpoint and its state.
+func (d *Damper) Remove(name string) {
+	delete(d.endpoints, name)
+	delete(d.states, name)
+}
+
+// Apply records a raw result and returns it with Status replaced by the
+// published status. RawStatus holds the status the poll actually produced.
+// Results for unknown endpoints are returned undamped.
+func (d *Damper) Apply(r StatusResult) StatusResult {
+	r.RawStatus = r.Status
+
+	ep, ok := d.endpoints[r.EndpointName]
+	if !ok {
+		return r
+	}
+	st := d.states[r.EndpointName]
+	if st == nil {
+		st = &damperState{}
+		d.states[r.EndpointName] = st
+	}
+
+	if ep.Flap.Transitions > 0 {
+		if st.lastRaw != "" && r.Status != st.lastRaw {
+			st.changes = append(st.changes, r.CheckedAt)
+		}
+		cutoff := r.CheckedAt.Add(-ep.Flap.Window)
+		for len(st.changes) > 0 && st.changes[0].Before(cutoff) {
+			st.changes = st.changes[1:]
+		}
+		st.flapping = len(st.changes) >= ep.Flap.Transitions
+	}
+	st.lastRaw = r.Status
+
+	switch {
+	case st.published == "":
+		st.published = r.Status
+	case st.flapping || r.Status == st.published:
+		st.candidate, st.streak = "", 0
+	default:
+		if r.Status == st.candidate {
+			st.streak++
+		} else {
+			st.candidate, st.streak = r.Status, 1
+		}
+		threshold := ep.FailureThreshold
+		if r.Status == "up" {
+			threshold = ep.SuccessThreshold
+		}
+		if st.streak >= threshold {
+			st.published = r.Status
+			st.candidate, st.streak = "", 0
+		}
+	}
+
+	r.Status = st.published
+	r.Flapping = st.flapping
+	return r
+}
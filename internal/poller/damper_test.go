@@ -0,0 +1,125 @@
+package poller
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// applyAll feeds raw statuses through d one minute apart and returns the
+// published statuses as a space-separated string.
+func applyAll(d *Damper, name string, raw ...string) string {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]string, len(raw))
+	for i, status := range raw {
+		r := d.Apply(StatusResult{
+			EndpointName: name,
+			Status:       status,
+			CheckedAt:    start.Add(time.Duration(i) * time.Minute),
+		})
+		out[i] = r.Status
+		if r.Flapping {
+			out[i] += "~"
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func TestDamper_Thresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		failure int
+		success int
+		raw     []string
+		want    string
+	}{
+		{
+			name: "defaults publish every change",
+			raw:  []string{"up", "down", "up", "degraded"},
+			want: "up down up degraded",
+		},
+		{
+			name:    "single failure is absorbed",
+			failure: 3,
+			raw:     []string{"up", "down", "up", "down", "down", "down"},
+			want:    "up up up up up down",
+		},
+		{
+			name:    "streak must agree",
+			failure: 2,
+			raw:     []string{"up", "down", "degraded", "degraded"},
+			want:    "up up up degraded",
+		},
+		{
+			name:    "recovery uses success threshold",
+			failure: 1,
+			success: 2,
+			raw:     []string{"down", "up", "down", "up", "up"},
+			want:    "down down down down up",
+		},
+		{
+			name:    "first result is published immediately",
+			failure: 5,
+			raw:     []string{"down", "down"},
+			want:    "down down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDamper([]EndpointInfo{{Name: "API", FailureThreshold: tt.failure, SuccessThreshold: tt.success}})
+			if got := applyAll(d, "API", tt.raw...); got != tt.want {
+				t.Errorf("published = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDamper_KeepsRawStatus(t *testing.T) {
+	d := NewDamper([]EndpointInfo{{Name: "API", FailureThreshold: 3}})
+	d.Apply(StatusResult{EndpointName: "API", Status: "up"})
+
+	r := d.Apply(StatusResult{EndpointName: "API", Status: "down"})
+	if r.Status != "up" || r.RawStatus != "down" {
+		t.Errorf("Status = %q, RawStatus = %q, want up, down", r.Status, r.RawStatus)
+	}
+}
+
+func TestDamper_Flapping(t *testing.T) {
+	d := NewDamper([]EndpointInfo{{
+		Name: "API",
+		Flap: FlapOptions{Transitions: 3, Window: 3 * time.Minute},
+	}})
+
+	// the third change within 3 minutes marks the endpoint flapping and holds
+	// the published status; once changes age out of the window it resumes
+	got := applyAll(d, "API", "up", "down", "up", "down", "up", "up", "up", "up", "down")
+	want := "up down up up~ up~ up~ up up down"
+	if got != want {
+		t.Errorf("published = %q, want %q", got, want)
+	}
+}
+
+func TestDamper_UnknownEndpoint(t *testing.T) {
+	d := NewDamper(nil)
+	r := d.Apply(StatusResult{EndpointName: "Other", Status: "down"})
+	if r.Status != "down" || r.RawStatus != "down" {
+		t.Errorf("Status = %q, RawStatus = %q, want down, down", r.Status, r.RawStatus)
+	}
+}
+
+func TestDamper_SetResetsState(t *testing.T) {
+	d := NewDamper([]EndpointInfo{{Name: "API", FailureThreshold: 3}})
+	d.Apply(StatusResult{EndpointName: "API", Status: "up"})
+
+	d.Set(EndpointInfo{Name: "API", FailureThreshold: 3})
+	if r := d.Apply(StatusResult{EndpointName: "API", Status: "down"}); r.Status != "down" {
+		t.Errorf("Status after Set = %q, want down", r.Status)
+	}
+
+	d.Remove("API")
+	d.Set(EndpointInfo{Name: "Other", FailureThreshold: 3})
+	if got := applyAll(d, "Other", "up", "down"); got != "up up" {
+		t.Errorf("published = %q, want %q", got, "up up")
+	}
+}
@@ -0,0 +1,232 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DNS record types supported by [ProbeDNS].
+const (
+	RecordA     = "A"
+	RecordAAAA  = "AAAA"
+	RecordCNAME = "CNAME"
+	RecordTXT   = "TXT"
+	RecordSRV   = "SRV"
+)
+
+// DNSOptions configures a DNS resolution probe.
+type DNSOptions struct {
+	// Server is the resolver to query as host:port. Empty uses the system
+	// resolver.
+	Server string
+
+	// RecordType is the record type to look up. Empty means [RecordA].
+	RecordType string
+
+	// Expect is the set of answers the lookup must return, compared without
+	// regard to order. If empty, any successful answer is accepted.
+	Expect []string
+}
+
+// ProbeDNS resolves name and returns the answers as newline-separated text in
+// the Body of the returned [Response], formatted as:
+//   - A, AAAA: IP addresses
+//   - CNAME: the canonical name, without a trailing dot
+//   - TXT: each record's text
+//   - SRV: "priority weight port target"
+//
+// Latency is the time taken by the lookup. Lookup failures are reported in
+// the Error field, prefixed with NXDOMAIN or SERVFAIL where the resolver
+// makes the distinction.
+func ProbeDNS(ctx context.Context, name string, opts DNSOptions, timeout time.Duration) Response {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resolver := net.DefaultResolver
+	if opts.Server != "" {
+		server := opts.Server
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	start := time.Now()
+	answers, err := lookup(ctx, resolver, name, opts.RecordType)
+	latency := time.Since(start)
+	if err != nil {
+		return Response{
+			Latency: latency,
+			Error:   dnsLookupError(err),
+		}
+	}
+
+	return Response{
+		Body:    []byte(strings.Join(answers, "\n")),
+		Latency: latency,
+	}
+}
+
+// lookup performs a single lookup of the given record type and formats the
+// answers.
+func lookup(ctx context.Context, r *net.Resolver, name, recordType string) ([]string, error) {
+	switch strings.ToUpper(recordType) {
+	case "", RecordA, RecordAAAA:
+		network := "ip4"
+		if strings.EqualFold(recordType, RecordAAAA) {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		answers := make([]string, len(ips))
+		for i, ip := range ips {
+			answers[i] = ip.String()
+		}
+		return answers, nil
+	case RecordCNAME:
+		cname, err := r.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return []string{strings.TrimSuffix(cname, ".")}, nil
+	case RecordTXT:
+		return r.LookupTXT(ctx, name)
+	case RecordSRV:
+		_, srvs, err := r.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+		answers := make([]string, len(srvs))
+		for i, srv := range srvs {
+			answers[i] = fmt.Sprintf("%d %d %d %s",
+				srv.Priority, srv.Weight, srv.Port, strings.TrimSuffix(srv.Target, "."))
+		}
+		return answers, nil
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+}
+
+// dnsLookupError labels a resolver error with the DNS failure it represents.
+// Everything but NXDOMAIN, a definite answer, is a [TransportError].
+func dnsLookupError(err error) error {
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) {
+		return fmt.Errorf("lookup failed: %w", err)
+	}
+	switch {
+	case dnsErr.IsNotFound:
+		return fmt.Errorf("NXDOMAIN: %w", err)
+	case dnsErr.IsTimeout:
+		return &TransportError{Err: fmt.Errorf("resolver timeout: %w", err)}
+	case dnsErr.Err == "server misbehaving":
+		return &TransportError{Err: fmt.Errorf("SERVFAIL: %w", err)}
+	default:
+		return &TransportError{Err: fmt.Errorf("lookup failed: %w", err)}
+	}
+}
+
+// answersMatch reports whether got and want contain the same answers,
+// ignoring order, duplicates, letter case and trailing dots.
+func answersMatch(got, want []string) bool {
+	return slices.Equal(normalizeAnswers(got), normalizeAnswers(want))
+}
+
+// normalizeAnswers returns a sorted, de-duplicated, canonical copy of answers.
+func normalizeAnswers(answers []string) []string {
+	out := make([]string, 0, len(answers))
+	for _, a := range answers {
+		a = strings.TrimSuffix(strings.TrimSpace(a), ".")
+		if ip := net.ParseIP(a); ip != nil {
+			a = ip.String()
+		}
+		out = append(out, strings.ToLower(a))
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// pollDNS performs a DNS probe for ep and converts it into a StatusResult.
+//
+// The endpoint is down if the lookup fails (including NXDOMAIN and SERVFAIL)
+// and degraded if the answers differ from the expected set.
+func (s *Scheduler) pollDNS(ctx context.Context, ep EndpointInfo) StatusResult {
+	result := StatusResult{
+		EndpointName: ep.Name,
+		URL:          ep.URL,
+		Labels:       ep.Labels,
+	}
+
+	name, err := dnsName(ep.URL)
+	if err != nil {
+		result.Status = "down"
+		result.Error = err
+		result.CheckedAt = time.Now()
+		return result
+	}
+
+	resp := ProbeDNS(ctx, name, ep.DNS, ep.Timeout)
+	result.Latency = resp.Latency
+	result.CheckedAt = time.Now()
+	result.RawResponse = resp.Body
+	result.Error = resp.Error
+
+	switch {
+	case resp.Error != nil:
+		result.Status = "down"
+	case len(ep.DNS.Expect) > 0:
+		answers := splitAnswers(resp.Body)
+		if answersMatch(answers, ep.DNS.Expect) {
+			result.Status = "up"
+		} else {
+			result.Status = "degraded"
+			result.Error = fmt.Errorf("answers %s do not match expected %s",
+				formatAnswers(answers), formatAnswers(ep.DNS.Expect))
+		}
+	default:
+		result.Status = "up"
+	}
+	return result
+}
+
+// splitAnswers splits a body written by [ProbeDNS] back into its answers.
+// An empty body holds no answers rather than a single empty one.
+func splitAnswers(body []byte) []string {
+	if len(body) == 0 {
+		return nil
+	}
+	return strings.Split(string(body), "\n")
+}
+
+// formatAnswers renders answers as a bracketed, quoted list for messages.
+func formatAnswers(answers []string) string {
+	quoted := make([]string, len(answers))
+	for i, a := range answers {
+		quoted[i] = strconv.Quote(a)
+	}
+	return "[" + strings.Join(quoted, " ") + "]"
+}
+
+// dnsName extracts the name to resolve from a dns:// URL.
+func dnsName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid dns name: %w", err)
+	}
+	if u.Host == "" || u.Port() != "" {
+		return "", fmt.Errorf("invalid dns name %q: expected dns://name", rawURL)
+	}
+	return u.Host, nil
+}
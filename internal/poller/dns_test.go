@@ -0,0 +1,334 @@
+package poller
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DNS wire constants used by the test server.
+const (
+	dnsTypeA     = 1
+	dnsTypeCNAME = 5
+	dnsTypeTXT   = 16
+	dnsTypeAAAA  = 28
+	dnsTypeSRV   = 33
+
+	dnsRcodeServFail = 2
+	dnsRcodeNXDomain = 3
+)
+
+// testRR is a resource record served by the test DNS server.
+type testRR struct {
+	rrType uint16
+	data   []byte
+}
+
+// testZone maps lower-case names (without trailing dot) to their records.
+// Names listed in servfail answer with SERVFAIL; unknown names get NXDOMAIN.
+type testZone struct {
+	records  map[string][]testRR
+	servfail map[string]bool
+}
+
+// startDNSServer starts a minimal UDP DNS server answering from zone. It
+// understands exactly enough of the protocol for Go's resolver.
+func startDNSServer(t *testing.T, zone testZone) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := zone.answer(buf[:n]); resp != nil {
+				_, _ = pc.WriteTo(resp, addr)
+			}
+		}
+	}()
+
+	return pc.LocalAddr().String()
+}
+
+// answer builds the response to a single-question query.
+func (z testZone) answer(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	// walk the question name
+	var labels []string
+	off := 12
+	for off < len(query) && query[off] != 0 {
+		l := int(query[off])
+		if off+1+l > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[off+1:off+1+l]))
+		off += 1 + l
+	}
+	off++ // root label
+	if off+4 > len(query) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(query[off:])
+	questionEnd := off + 4
+	name := strings.ToLower(strings.Join(labels, "."))
+
+	var rcode uint16
+	var answers []testRR
+	switch records, ok := z.records[name]; {
+	case z.servfail[name]:
+		rcode = dnsRcodeServFail
+	case !ok:
+		rcode = dnsRcodeNXDomain
+	default:
+		for _, rr := range records {
+			if rr.rrType == qtype || rr.rrType == dnsTypeCNAME {
+				answers = append(answers, rr)
+			}
+		}
+	}
+
+	resp := make([]byte, 12, 512)
+	copy(resp, query[:2])                                      // ID
+	binary.BigEndian.PutUint16(resp[2:], 0x8580|rcode)         // QR, AA, RD, RA
+	binary.BigEndian.PutUint16(resp[4:], 1)                    // QDCOUNT
+	binary.BigEndian.PutUint16(resp[6:], uint16(len(answers))) // ANCOUNT
+	resp = append(resp, query[12:questionEnd]...)
+
+	for _, rr := range answers {
+		resp = append(resp, 0xC0, 12) // pointer to question name
+		resp = binary.BigEndian.AppendUint16(resp, rr.rrType)
+		resp = binary.BigEndian.AppendUint16(resp, 1) // class IN
+		resp = binary.BigEndian.AppendUint32(resp, 60)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(rr.data)))
+		resp = append(resp, rr.data...)
+	}
+	return resp
+}
+
+// encodeName encodes a domain name in DNS wire format.
+func encodeName(name string) []byte {
+	var b []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+func rrA(ip string) testRR {
+	return testRR{rrType: dnsTypeA, data: net.ParseIP(ip).To4()}
+}
+
+func rrAAAA(ip string) testRR {
+	return testRR{rrType: dnsTypeAAAA, data: net.ParseIP(ip).To16()}
+}
+
+func rrCNAME(target string) testRR {
+	return testRR{rrType: dnsTypeCNAME, data: encodeName(target)}
+}
+
+func rrTXT(text string) testRR {
+	return testRR{rrType: dnsTypeTXT, data: append([]byte{byte(len(text))}, text...)}
+}
+
+func rrSRV(priority, weight, port uint16, target string) testRR {
+	data := binary.BigEndian.AppendUint16(nil, priority)
+	data = binary.BigEndian.AppendUint16(data, weight)
+	data = binary.BigEndian.AppendUint16(data, port)
+	return testRR{rrType: dnsTypeSRV, data: append(data, encodeName(target)...)}
+}
+
+func testDNSZone() testZone {
+	return testZone{
+		records: map[string][]testRR{
+			"api.pulseboard.test":       {rrA("10.0.0.1"), rrA("10.0.0.2"), rrAAAA("fd00::1")},
+			"www.pulseboard.test":       {rrCNAME("lb.pulseboard.test.")},
+			"pulseboard.test":           {rrTXT("v=spf1 -all")},
+			"_sip._tcp.pulseboard.test": {rrSRV(10, 5, 5060, "sip.pulseboard.test.")},
+		},
+		servfail: map[string]bool{"broken.pulseboard.test": true},
+	}
+}
+
+func TestProbeDNS_RecordTypes(t *testing.T) {
+	server := startDNSServer(t, testDNSZone())
+
+	tests := []struct {
+		name       string
+		host       string
+		recordType string
+		want       string
+	}{
+		{"default A", "api.pulseboard.test.", "", "10.0.0.1\n10.0.0.2"},
+		{"A", "api.pulseboard.test.", RecordA, "10.0.0.1\n10.0.0.2"},
+		{"AAAA", "api.pulseboard.test.", RecordAAAA, "fd00::1"},
+		{"CNAME", "www.pulseboard.test.", RecordCNAME, "lb.pulseboard.test"},
+		{"TXT", "pulseboard.test.", RecordTXT, "v=spf1 -all"},
+		{"SRV", "_sip._tcp.pulseboard.test.", RecordSRV, "10 5 5060 sip.pulseboard.test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DNSOptions{Server: server, RecordType: tt.recordType}
+			resp := ProbeDNS(context.Background(), tt.host, opts, 2*time.Second)
+			if resp.Error != nil {
+				t.Fatalf("Error = %v, want nil", resp.Error)
+			}
+			if string(resp.Body) != tt.want {
+				t.Errorf("Body = %q, want %q", resp.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeDNS_Failures(t *testing.T) {
+	server := startDNSServer(t, testDNSZone())
+
+	tests := []struct {
+		name    string
+		host    string
+		wantErr string
+	}{
+		{"nxdomain", "missing.pulseboard.test.", "NXDOMAIN"},
+		{"servfail", "broken.pulseboard.test.", "SERVFAIL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := ProbeDNS(context.Background(), tt.host, DNSOptions{Server: server}, 2*time.Second)
+			if resp.Error == nil {
+				t.Fatal("Error = nil, want lookup error")
+			}
+			if !strings.HasPrefix(resp.Error.Error(), tt.wantErr) {
+				t.Errorf("Error = %q, want prefix %q", resp.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnswersMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+		ok   bool
+	}{
+		{"same order", []string{"10.0.0.1", "10.0.0.2"}, []string{"10.0.0.1", "10.0.0.2"}, true},
+		{"different order", []string{"10.0.0.2", "10.0.0.1"}, []string{"10.0.0.1", "10.0.0.2"}, true},
+		{"case and trailing dot", []string{"lb.pulseboard.test"}, []string{"LB.Pulseboard.Test."}, true},
+		{"ipv6 forms", []string{"fd00::1"}, []string{"fd00:0:0:0:0:0:0:1"}, true},
+		{"missing answer", []string{"10.0.0.1"}, []string{"10.0.0.1", "10.0.0.2"}, false},
+		{"extra answer", []string{"10.0.0.1", "10.0.0.3"}, []string{"10.0.0.1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := answersMatch(tt.got, tt.want); got != tt.ok {
+				t.Errorf("answersMatch(%v, %v) = %v, want %v", tt.got, tt.want, got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSplitAnswers(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"", "[]"},
+		{"10.0.0.1", `["10.0.0.1"]`},
+		{"10.0.0.1\n10.0.0.2", `["10.0.0.1" "10.0.0.2"]`},
+	}
+
+	for _, tt := range tests {
+		if got := formatAnswers(splitAnswers([]byte(tt.body))); got != tt.want {
+			t.Errorf("splitAnswers(%q) = %s, want %s", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestScheduler_DNSEndpoint(t *testing.T) {
+	server := startDNSServer(t, testDNSZone())
+
+	endpoints := []EndpointInfo{
+		{Name: "Match", URL: "dns://api.pulseboard.test.", Type: TypeDNS, Timeout: 2 * time.Second,
+			DNS: DNSOptions{Server: server, Expect: []string{"10.0.0.2", "10.0.0.1"}}},
+		{Name: "Mismatch", URL: "dns://api.pulseboard.test.", Type: TypeDNS, Timeout: 2 * time.Second,
+			DNS: DNSOptions{Server: server, Expect: []string{"10.0.0.9"}}},
+		{Name: "Any", URL: "dns://www.pulseboard.test.", Type: TypeDNS, Timeout: 2 * time.Second,
+			DNS: DNSOptions{Server: server, RecordType: RecordCNAME}},
+		{Name: "Missing", URL: "dns://missing.pulseboard.test.", Type: TypeDNS, Timeout: 2 * time.Second,
+			DNS: DNSOptions{Server: server}},
+	}
+
+	scheduler := NewScheduler(endpoints, time.Hour, 4, testLogger())
+	scheduler.Start(context.Background())
+
+	results := make(map[string]StatusResult)
+	for len(results) < len(endpoints) {
+		select {
+		case r := <-scheduler.Results():
+			results[r.EndpointName] = r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for results")
+		}
+	}
+	scheduler.Stop()
+
+	want := map[string]string{
+		"Match":    "up",
+		"Mismatch": "degraded",
+		"Any":      "up",
+		"Missing":  "down",
+	}
+	for name, status := range want {
+		if got := results[name]; got.Status != status {
+			t.Errorf("%s: Status = %q, want %q (error: %v)", name, got.Status, status, got.Error)
+		}
+	}
+	if err := results["Mismatch"].Error; err == nil || !strings.Contains(err.Error(), "do not match expected") {
+		t.Errorf("Mismatch: Error = %v, want mismatch message", err)
+	}
+	if got := string(results["Any"].RawResponse); got != "lb.pulseboard.test" {
+		t.Errorf("Any: RawResponse = %q, want CNAME target", got)
+	}
+}
+
+func TestDNSName(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "dns://api.example.com", want: "api.example.com"},
+		{url: "dns://_sip._tcp.example.com", want: "_sip._tcp.example.com"},
+		{url: "dns://api.example.com:53", wantErr: true},
+		{url: "dns://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := dnsName(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("dnsName(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("dnsName(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
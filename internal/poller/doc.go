@@ -1,13 +1,14 @@
 // Package poller provides concurrent HTTP polling functionality for PulseBoard.
 //
 // This package is internal to PulseBoard and handles the periodic polling of
-// HTTP endpoints. It implements a worker pool pattern for concurrent requests
-// with configurable concurrency limits.
+// HTTP endpoints. Endpoints are kept in a priority queue ordered by next due
+// time and dispatched to a persistent worker pool with a configurable
+// concurrency limit.
 //
 // The main components are:
 //
 //   - [Client]: HTTP client wrapper with timeout and size limits
-//   - [Scheduler]: Manages periodic polling of endpoints with worker pool
+//   - [Scheduler]: Schedules endpoints by due time and polls them with a worker pool
 //   - [StatusResult]: Result of polling a single endpoint
 //   - [EndpointInfo]: Configuration for an endpoint to poll
 //
@@ -0,0 +1,32 @@
+package poller
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// graphQLResponse is the part of a GraphQL response we inspect.
+type graphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLError returns an error describing the errors array of a GraphQL
+// response body, or nil if the array is absent or empty. Bodies that are not
+// JSON are left to the endpoint's extractor and return nil.
+func graphQLError(body []byte) error {
+	var resp graphQLResponse
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Errors) == 0 {
+		return nil
+	}
+
+	msg := resp.Errors[0].Message
+	if msg == "" {
+		msg = "unspecified error"
+	}
+	if n := len(resp.Errors) - 1; n > 0 {
+		return fmt.Errorf("graphql error: %s (and %d more)", msg, n)
+	}
+	return fmt.Errorf("graphql error: %s", msg)
+}
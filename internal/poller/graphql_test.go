@@ -0,0 +1,88 @@
+package poller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGraphQLError(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"data only", `{"data":{"health":"ok"}}`, ""},
+		{"empty errors", `{"data":{},"errors":[]}`, ""},
+		{"not json", `<html>`, ""},
+		{"one error", `{"errors":[{"message":"unauthorized"}]}`, "graphql error: unauthorized"},
+		{"several errors", `{"errors":[{"message":"a"},{"message":"b"},{"message":"c"}]}`, "graphql error: a (and 2 more)"},
+		{"no message", `{"errors":[{}]}`, "graphql error: unspecified error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := graphQLError([]byte(tt.body))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("graphQLError() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("graphQLError() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduler_GraphQLEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/broken" {
+			_, _ = w.Write([]byte(`{"data":null,"errors":[{"message":"resolver failed"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"__typename":"Query"}}`))
+	}))
+	defer server.Close()
+
+	query := []byte(`{"query":"{ __typename }"}`)
+	endpoints := []EndpointInfo{
+		{Name: "Healthy", URL: server.URL + "/ok", Method: http.MethodPost, Body: query, GraphQL: true, Timeout: time.Second},
+		{Name: "Broken", URL: server.URL + "/broken", Method: http.MethodPost, Body: query, GraphQL: true, Timeout: time.Second},
+		{Name: "Plain", URL: server.URL + "/broken", Method: http.MethodPost, Body: query, Timeout: time.Second},
+	}
+
+	scheduler := NewScheduler(endpoints, time.Hour, 3, testLogger())
+	scheduler.Start(context.Background())
+
+	results := make(map[string]StatusResult)
+	for len(results) < len(endpoints) {
+		select {
+		case r := <-scheduler.Results():
+			results[r.EndpointName] = r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for results")
+		}
+	}
+	scheduler.Stop()
+
+	if got := results["Healthy"]; got.Status != "up" {
+		t.Errorf("Healthy: Status = %q, want up (error: %v)", got.Status, got.Error)
+	}
+	broken := results["Broken"]
+	if broken.Status != "down" {
+		t.Errorf("Broken: Status = %q, want down", broken.Status)
+	}
+	if broken.Error == nil || !strings.Contains(broken.Error.Error(), "resolver failed") {
+		t.Errorf("Broken: Error = %v, want graphql error message", broken.Error)
+	}
+	// without the GraphQL flag, a 200 response is up regardless of the body
+	if got := results["Plain"]; got.Status != "up" {
+		t.Errorf("Plain: Status = %q, want up", got.Status)
+	}
+}
@@ -0,0 +1,389 @@
+package poller
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// grpcHealthPath is the method path of grpc.health.v1.Health/Check.
+const grpcHealthPath = "/grpc.health.v1.Health/Check"
+
+// maxGRPCResponseSize caps the health check response read. A
+// HealthCheckResponse is a handful of bytes.
+const maxGRPCResponseSize = 64 << 10
+
+// Serving statuses returned by the gRPC health checking protocol.
+const (
+	GRPCUnknown        = "UNKNOWN"
+	GRPCServing        = "SERVING"
+	GRPCNotServing     = "NOT_SERVING"
+	GRPCServiceUnknown = "SERVICE_UNKNOWN"
+)
+
+// grpcServingStatuses maps HealthCheckResponse.ServingStatus enum values to
+// their names.
+var grpcServingStatuses = map[uint64]string{
+	0: GRPCUnknown,
+	1: GRPCServing,
+	2: GRPCNotServing,
+	3: GRPCServiceUnknown,
+}
+
+// gRPC status codes reporting that no answer was received.
+const (
+	grpcCodeDeadlineExceeded = "4"
+	grpcCodeUnavailable      = "14"
+)
+
+// grpcCodeNames names the gRPC status codes a health check commonly fails with.
+var grpcCodeNames = map[string]string{
+	"1":  "CANCELLED",
+	"4":  "DEADLINE_EXCEEDED",
+	"5":  "NOT_FOUND",
+	"7":  "PERMISSION_DENIED",
+	"12": "UNIMPLEMENTED",
+	"13": "INTERNAL",
+	"14": "UNAVAILABLE",
+	"16": "UNAUTHENTICATED",
+}
+
+// GRPCOptions configures a gRPC health check.
+type GRPCOptions struct {
+	// Service is the service name sent in the HealthCheckRequest. Empty asks
+	// about the server as a whole.
+	Service string
+
+	// Insecure uses plaintext HTTP/2 instead of TLS.
+	Insecure bool
+
+	// SkipVerify disables verification of the server's certificate.
+	SkipVerify bool
+
+	// RootCAs overrides the system roots used to verify the server.
+	// nil uses the host's root CA set.
+	RootCAs *x509.CertPool
+
+	// Metadata is sent as request headers with the call.
+	Metadata map[string]string
+}
+
+// grpcTransports shares HTTP/2 transports between gRPC health checks, so
+// polls reuse a connection instead of dialling and handshaking each time.
+// Checks with the same TLS settings share a transport, which keeps one
+// connection per server address. The zero value is ready to use.
+type grpcTransports struct {
+	mu         sync.Mutex
+	transports map[grpcTransportKey]*http2.Transport
+}
+
+// grpcTransportKey identifies the settings a transport is built from.
+type grpcTransportKey struct {
+	insecure   bool
+	skipVerify bool
+	rootCAs    *x509.CertPool
+}
+
+// get returns the transport for checks made with opts.
+func (g *grpcTransports) get(opts GRPCOptions) *http2.Transport {
+	key := grpcTransportKey{insecure: opts.Insecure, skipVerify: opts.SkipVerify, rootCAs: opts.RootCAs}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if t, ok := g.transports[key]; ok {
+		return t
+	}
+	if g.transports == nil {
+		g.transports = make(map[grpcTransportKey]*http2.Transport)
+	}
+	t := newGRPCTransport(opts)
+	g.transports[key] = t
+	return t
+}
+
+// closeIdleConnections closes the idle connections of every transport.
+func (g *grpcTransports) closeIdleConnections() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, t := range g.transports {
+		t.CloseIdleConnections()
+	}
+}
+
+// newGRPCTransport returns an HTTP/2 transport for the TLS settings of opts.
+func newGRPCTransport(opts GRPCOptions) *http2.Transport {
+	transport := &http2.Transport{
+		IdleConnTimeout: defaultIdleConnTimeout,
+		// ping quiet connections, so a dead one is dropped before it fails a poll
+		ReadIdleTimeout: defaultIdleConnTimeout / 2,
+	}
+	if opts.Insecure {
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	} else {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:            opts.RootCAs,
+			InsecureSkipVerify: opts.SkipVerify, //nolint:gosec // opt-in
+		}
+	}
+	return transport
+}
+
+// ProbeGRPC calls grpc.health.v1.Health/Check on the server at address.
+//
+// The call is made over HTTP/2 directly, without a gRPC client library. On
+// success the serving status name ([GRPCServing], [GRPCNotServing],
+// [GRPCUnknown] or [GRPCServiceUnknown]) is returned in the Body of the
+// [Response]. Transport failures and non-OK gRPC status codes are reported
+// in the Error field. Latency covers the whole call, including connection
+// setup.
+//
+// ProbeGRPC connects afresh on every call; a [Scheduler] keeps its
+// connections open between polls.
+func ProbeGRPC(ctx context.Context, address string, opts GRPCOptions, timeout time.Duration) Response {
+	transport := newGRPCTransport(opts)
+	defer transport.CloseIdleConnections()
+	return probeGRPC(ctx, transport, address, opts, timeout)
+}
+
+// probeGRPC makes the health check of [ProbeGRPC] over transport.
+func probeGRPC(ctx context.Context, transport *http2.Transport, address string, opts GRPCOptions, timeout time.Duration) Response {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	scheme := "https"
+	if opts.Insecure {
+		scheme = "http"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		scheme+"://"+address+grpcHealthPath, bytes.NewReader(encodeHealthCheckRequest(opts.Service)))
+	if err != nil {
+		return Response{Error: fmt.Errorf("failed to create request: %w", err)}
+	}
+	for k, v := range opts.Metadata {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	req.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", timeout.Milliseconds()))
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return Response{
+			Latency: time.Since(start),
+			Error:   &TransportError{Err: fmt.Errorf("rpc failed: %w", err)},
+		}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGRPCResponseSize))
+	latency := time.Since(start)
+	if err != nil {
+		return Response{
+			Latency: latency,
+			Error:   &TransportError{Err: fmt.Errorf("failed to read response: %w", err)},
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Response{
+			Latency:    latency,
+			StatusCode: resp.StatusCode,
+			Error:      fmt.Errorf("unexpected HTTP status %d", resp.StatusCode),
+		}
+	}
+
+	// trailers-only responses carry the status in the headers
+	code, msg := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if code == "" {
+		code, msg = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if code != "0" {
+		return Response{
+			Latency: latency,
+			Error:   grpcStatusError(code, msg),
+		}
+	}
+
+	status, err := decodeHealthCheckResponse(body)
+	if err != nil {
+		return Response{
+			Latency: latency,
+			Error:   fmt.Errorf("invalid health check response: %w", err),
+		}
+	}
+
+	return Response{
+		Body:    []byte(status),
+		Latency: latency,
+	}
+}
+
+// grpcStatusError describes a non-OK gRPC status.
+func grpcStatusError(code, msg string) error {
+	if code == "" {
+		return errors.New("rpc failed: response has no grpc-status")
+	}
+	name := code
+	if n, ok := grpcCodeNames[code]; ok {
+		name = n
+	}
+	var err error
+	if msg != "" {
+		if unescaped, uerr := url.PathUnescape(msg); uerr == nil {
+			msg = unescaped
+		}
+		err = fmt.Errorf("rpc failed: %s: %s", name, msg)
+	} else {
+		err = fmt.Errorf("rpc failed: %s", name)
+	}
+	// the server could not be reached or did not answer in time
+	if code == grpcCodeUnavailable || code == grpcCodeDeadlineExceeded {
+		return &TransportError{Err: err}
+	}
+	return err
+}
+
+// encodeHealthCheckRequest builds the length-prefixed gRPC message for a
+// HealthCheckRequest{service}.
+func encodeHealthCheckRequest(service string) []byte {
+	var msg []byte
+	if service != "" {
+		msg = append(msg, 0x0a) // field 1, wire type 2
+		msg = binary.AppendUvarint(msg, uint64(len(service)))
+		msg = append(msg, service...)
+	}
+
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	return append(frame, msg...)
+}
+
+// decodeHealthCheckResponse extracts the serving status name from a
+// length-prefixed HealthCheckResponse.
+func decodeHealthCheckResponse(frame []byte) (string, error) {
+	if len(frame) < 5 {
+		return "", errors.New("truncated message")
+	}
+	if frame[0] != 0 {
+		return "", errors.New("compressed messages are not supported")
+	}
+	n := binary.BigEndian.Uint32(frame[1:5])
+	if uint32(len(frame)-5) < n {
+		return "", errors.New("truncated message")
+	}
+	msg := frame[5 : 5+n]
+
+	var status uint64 // an absent field means UNKNOWN
+	for len(msg) > 0 {
+		tag, l := binary.Uvarint(msg)
+		if l <= 0 {
+			return "", errors.New("malformed field tag")
+		}
+		msg = msg[l:]
+
+		field, wireType := tag>>3, tag&7
+		switch wireType {
+		case 0: // varint
+			v, l := binary.Uvarint(msg)
+			if l <= 0 {
+				return "", errors.New("malformed varint")
+			}
+			msg = msg[l:]
+			if field == 1 {
+				status = v
+			}
+		case 1: // 64-bit
+			if len(msg) < 8 {
+				return "", errors.New("truncated field")
+			}
+			msg = msg[8:]
+		case 2: // length-delimited
+			size, l := binary.Uvarint(msg)
+			if l <= 0 || uint64(len(msg)-l) < size {
+				return "", errors.New("truncated field")
+			}
+			msg = msg[l+int(size):]
+		case 5: // 32-bit
+			if len(msg) < 4 {
+				return "", errors.New("truncated field")
+			}
+			msg = msg[4:]
+		default:
+			return "", fmt.Errorf("unsupported wire type %d", wireType)
+		}
+	}
+
+	name, ok := grpcServingStatuses[status]
+	if !ok {
+		return "", fmt.Errorf("unknown serving status %d", status)
+	}
+	return name, nil
+}
+
+// pollGRPC performs a gRPC health check for ep and converts it into a
+// StatusResult.
+//
+// SERVING maps to up, NOT_SERVING and SERVICE_UNKNOWN to down, and UNKNOWN
+// to unknown. Failed calls are down.
+func (s *Scheduler) pollGRPC(ctx context.Context, ep EndpointInfo) StatusResult {
+	result := StatusResult{
+		EndpointName: ep.Name,
+		URL:          ep.URL,
+		Labels:       ep.Labels,
+	}
+
+	u, err := url.Parse(ep.URL)
+	if err != nil || u.Hostname() == "" || u.Port() == "" {
+		result.Status = "down"
+		result.Error = fmt.Errorf("invalid grpc address %q: expected grpc://host:port", ep.URL)
+		result.CheckedAt = time.Now()
+		return result
+	}
+
+	opts := ep.GRPC
+	opts.Metadata = ep.Headers
+
+	resp := probeGRPC(ctx, s.grpc.get(opts), u.Host, opts, ep.Timeout)
+	result.Latency = resp.Latency
+	result.CheckedAt = time.Now()
+	result.RawResponse = resp.Body
+	result.StatusCode = resp.StatusCode
+	result.Error = resp.Error
+
+	if resp.Error != nil {
+		result.Status = "down"
+		return result
+	}
+
+	switch servingStatus := string(resp.Body); servingStatus {
+	case GRPCServing:
+		result.Status = "up"
+	case GRPCNotServing:
+		result.Status = "down"
+		result.Error = errors.New("health check reported NOT_SERVING")
+	case GRPCServiceUnknown:
+		result.Status = "down"
+		result.Error = fmt.Errorf("health check reported SERVICE_UNKNOWN for %q", opts.Service)
+	default:
+		result.Status = "unknown"
+	}
+	return result
+}
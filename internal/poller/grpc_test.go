@@ -0,0 +1,283 @@
+package poller
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// grpcHealthServer is a stand-in for a gRPC server implementing
+// grpc.health.v1.Health/Check. Services map to ServingStatus enum values;
+// unknown services get a NOT_FOUND status, as grpc-go's implementation does.
+type grpcHealthServer struct {
+	services map[string]uint64
+
+	mu       sync.Mutex
+	metadata http.Header
+}
+
+func (h *grpcHealthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.metadata = r.Header.Clone()
+	h.mu.Unlock()
+
+	if r.URL.Path != grpcHealthPath || r.Header.Get("Content-Type") != "application/grpc" {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "12")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	frame, _ := io.ReadAll(r.Body)
+	service := ""
+	if len(frame) > 7 {
+		// field 1 tag, single-byte length, then the name
+		service = string(frame[7:])
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	status, ok := h.services[service]
+	if !ok {
+		w.Header().Set("Grpc-Status", "5")
+		w.Header().Set("Grpc-Message", "unknown%20service")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	msg := binary.AppendUvarint([]byte{0x08}, status) // field 1, varint
+	resp := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(resp[1:], uint32(len(msg)))
+	resp = append(resp, msg...)
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(resp)
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", "0")
+}
+
+func (h *grpcHealthServer) lastMetadata() http.Header {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.metadata
+}
+
+func testHealthServer() *grpcHealthServer {
+	return &grpcHealthServer{services: map[string]uint64{
+		"":           1, // SERVING
+		"orders":     1,
+		"payments":   2, // NOT_SERVING
+		"warming":    0, // UNKNOWN
+		"retired.v1": 3,
+	}}
+}
+
+// startPlaintextGRPCServer serves h over cleartext HTTP/2.
+func startPlaintextGRPCServer(t *testing.T, h http.Handler) string {
+	t.Helper()
+	server := httptest.NewServer(h2c.NewHandler(h, &http2.Server{}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestProbeGRPC_ServingStatuses(t *testing.T) {
+	addr := startPlaintextGRPCServer(t, testHealthServer())
+
+	tests := []struct {
+		service string
+		want    string
+	}{
+		{"", GRPCServing},
+		{"orders", GRPCServing},
+		{"payments", GRPCNotServing},
+		{"warming", GRPCUnknown},
+		{"retired.v1", GRPCServiceUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			opts := GRPCOptions{Service: tt.service, Insecure: true}
+			resp := ProbeGRPC(context.Background(), addr, opts, time.Second)
+			if resp.Error != nil {
+				t.Fatalf("Error = %v, want nil", resp.Error)
+			}
+			if string(resp.Body) != tt.want {
+				t.Errorf("Body = %q, want %q", resp.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeGRPC_NonOKStatus(t *testing.T) {
+	addr := startPlaintextGRPCServer(t, testHealthServer())
+
+	opts := GRPCOptions{Service: "missing", Insecure: true}
+	resp := ProbeGRPC(context.Background(), addr, opts, time.Second)
+	if resp.Error == nil {
+		t.Fatal("Error = nil, want NOT_FOUND error")
+	}
+	if !strings.Contains(resp.Error.Error(), "NOT_FOUND: unknown service") {
+		t.Errorf("Error = %q, want NOT_FOUND with message", resp.Error)
+	}
+}
+
+func TestProbeGRPC_SendsMetadata(t *testing.T) {
+	h := testHealthServer()
+	addr := startPlaintextGRPCServer(t, h)
+
+	opts := GRPCOptions{
+		Insecure: true,
+		Metadata: map[string]string{"Authorization": "Bearer secret", "x-tenant": "acme"},
+	}
+	if resp := ProbeGRPC(context.Background(), addr, opts, time.Second); resp.Error != nil {
+		t.Fatalf("Error = %v, want nil", resp.Error)
+	}
+
+	md := h.lastMetadata()
+	if got := md.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("authorization = %q, want %q", got, "Bearer secret")
+	}
+	if got := md.Get("X-Tenant"); got != "acme" {
+		t.Errorf("x-tenant = %q, want %q", got, "acme")
+	}
+	if got := md.Get("Grpc-Timeout"); got != "1000m" {
+		t.Errorf("grpc-timeout = %q, want %q", got, "1000m")
+	}
+}
+
+func TestProbeGRPC_TLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(testHealthServer())
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "https://")
+
+	pool := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	resp := ProbeGRPC(context.Background(), addr, GRPCOptions{RootCAs: pool}, time.Second)
+	if resp.Error != nil {
+		t.Fatalf("verified: Error = %v, want nil", resp.Error)
+	}
+	if string(resp.Body) != GRPCServing {
+		t.Errorf("verified: Body = %q, want SERVING", resp.Body)
+	}
+
+	resp = ProbeGRPC(context.Background(), addr, GRPCOptions{}, time.Second)
+	if resp.Error == nil {
+		t.Error("untrusted: Error = nil, want certificate error")
+	}
+
+	resp = ProbeGRPC(context.Background(), addr, GRPCOptions{SkipVerify: true}, time.Second)
+	if resp.Error != nil {
+		t.Errorf("skip verify: Error = %v, want nil", resp.Error)
+	}
+}
+
+func TestDecodeHealthCheckResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   []byte
+		want    string
+		wantErr bool
+	}{
+		{"serving", []byte{0, 0, 0, 0, 2, 0x08, 1}, GRPCServing, false},
+		{"empty message is unknown", []byte{0, 0, 0, 0, 0}, GRPCUnknown, false},
+		{"skips unknown fields", []byte{0, 0, 0, 0, 6, 0x12, 2, 'h', 'i', 0x08, 2}, GRPCNotServing, false},
+		{"truncated", []byte{0, 0, 0, 0, 4, 0x08}, "", true},
+		{"compressed", []byte{1, 0, 0, 0, 2, 0x08, 1}, "", true},
+		{"out of range", []byte{0, 0, 0, 0, 2, 0x08, 9}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeHealthCheckResponse(tt.frame)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_GRPCEndpoint(t *testing.T) {
+	addr := startPlaintextGRPCServer(t, testHealthServer())
+
+	endpoints := []EndpointInfo{
+		{Name: "Orders", URL: "grpc://" + addr, Type: TypeGRPC, Timeout: time.Second,
+			GRPC: GRPCOptions{Service: "orders", Insecure: true}},
+		{Name: "Payments", URL: "grpc://" + addr, Type: TypeGRPC, Timeout: time.Second,
+			GRPC: GRPCOptions{Service: "payments", Insecure: true}},
+		{Name: "Warming", URL: "grpc://" + addr, Type: TypeGRPC, Timeout: time.Second,
+			GRPC: GRPCOptions{Service: "warming", Insecure: true}},
+		{Name: "Missing", URL: "grpc://" + addr, Type: TypeGRPC, Timeout: time.Second,
+			GRPC: GRPCOptions{Service: "missing", Insecure: true}},
+	}
+
+	scheduler := NewScheduler(endpoints, time.Hour, 4, testLogger())
+	scheduler.Start(context.Background())
+
+	results := make(map[string]StatusResult)
+	for len(results) < len(endpoints) {
+		select {
+		case r := <-scheduler.Results():
+			results[r.EndpointName] = r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for results")
+		}
+	}
+	scheduler.Stop()
+
+	want := map[string]string{
+		"Orders":   "up",
+		"Payments": "down",
+		"Warming":  "unknown",
+		"Missing":  "down",
+	}
+	for name, status := range want {
+		if got := results[name]; got.Status != status {
+			t.Errorf("%s: Status = %q, want %q (error: %v)", name, got.Status, status, got.Error)
+		}
+	}
+	if err := results["Payments"].Error; err == nil || !strings.Contains(err.Error(), "NOT_SERVING") {
+		t.Errorf("Payments: Error = %v, want NOT_SERVING", err)
+	}
+}
+
+func TestScheduler_GRPCReusesConnections(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(h2c.NewHandler(testHealthServer(), &http2.Server{}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	scheduler := NewScheduler(nil, time.Hour, 1, testLogger())
+	defer scheduler.Stop()
+
+	// endpoints with the same TLS settings share a connection too
+	for _, service := range []string{"orders", "orders", "payments", "warming"} {
+		ep := EndpointInfo{Name: service, URL: "grpc://" + addr, Type: TypeGRPC, Timeout: time.Second,
+			GRPC: GRPCOptions{Service: service, Insecure: true}}
+		if r := scheduler.pollGRPC(context.Background(), ep); IsTransportError(r.Error) {
+			t.Fatalf("%s: pollGRPC() error = %v", service, r.Error)
+		}
+	}
+	if got := conns.Load(); got != 1 {
+		t.Errorf("connections opened = %d, want 1", got)
+	}
+}
@@ -0,0 +1,47 @@
+package poller
+
+import (
+	"fmt"
+	"time"
+)
+
+// LatencyThresholds marks slow results as degraded or down.
+type LatencyThresholds struct {
+	// Degraded is the latency above which a result is at least degraded.
+	// Zero disables the check.
+	Degraded time.Duration
+
+	// Down is the latency above which a result is down. Zero disables the
+	// check.
+	Down time.Duration
+}
+
+// applyLatencyThresholds worsens r's status if its latency exceeds the
+// thresholds, recording the reason in r.Error. Thresholds never improve a
+// status.
+func applyLatencyThresholds(r *StatusResult, t LatencyThresholds) {
+	var status string
+	var limit time.Duration
+	switch {
+	case t.Down > 0 && r.Latency > t.Down:
+		status, limit = "down", t.Down
+	case t.Degraded > 0 && r.Latency > t.Degraded:
+		status, limit = "degraded", t.Degraded
+	default:
+		return
+	}
+
+	if statusSeverity(status) > statusSeverity(r.Status) {
+		r.Status = status
+		r.Error = fmt.Errorf("latency %s > %s", formatLatency(r.Latency), formatLatency(limit))
+	}
+}
+
+// formatLatency renders d compactly: to the nearest 100ms from one second up,
+// and to the nearest millisecond below that.
+func formatLatency(d time.Duration) string {
+	if d >= time.Second {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
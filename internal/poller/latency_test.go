@@ -0,0 +1,75 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestApplyLatencyThresholds(t *testing.T) {
+	thresholds := LatencyThresholds{Degraded: 2 * time.Second, Down: 10 * time.Second}
+
+	tests := []struct {
+		name       string
+		status     string
+		latency    time.Duration
+		thresholds LatencyThresholds
+		want       string
+		wantErr    string
+	}{
+		{"fast stays up", "up", 500 * time.Millisecond, thresholds, "up", ""},
+		{"slow is degraded", "up", 8100 * time.Millisecond, thresholds, "degraded", "latency 8.1s > 2s"},
+		{"very slow is down", "up", 12 * time.Second, thresholds, "down", "latency 12s > 10s"},
+		{"sub-second limit", "up", 750 * time.Millisecond, LatencyThresholds{Degraded: 300 * time.Millisecond}, "degraded", "latency 750ms > 300ms"},
+		{"never improves down", "down", 3 * time.Second, thresholds, "down", "connection refused"},
+		{"degraded stays degraded", "degraded", 3 * time.Second, thresholds, "degraded", "connection refused"},
+		{"down only", "up", 5 * time.Second, LatencyThresholds{Down: 4 * time.Second}, "down", "latency 5s > 4s"},
+		{"disabled", "up", time.Minute, LatencyThresholds{}, "up", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := StatusResult{Status: tt.status, Latency: tt.latency}
+			if tt.status != "up" {
+				r.Error = errors.New("connection refused")
+			}
+
+			applyLatencyThresholds(&r, tt.thresholds)
+			if r.Status != tt.want {
+				t.Errorf("Status = %q, want %q", r.Status, tt.want)
+			}
+			gotErr := ""
+			if r.Error != nil {
+				gotErr = r.Error.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Error = %q, want %q", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPollEndpoint_LatencyThresholds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s := NewScheduler(nil, time.Hour, 1, testLogger())
+	ep := EndpointInfo{
+		Name: "API", URL: server.URL, Timeout: time.Second,
+		Latency: LatencyThresholds{Degraded: 10 * time.Millisecond},
+	}
+
+	result := s.pollEndpoint(context.Background(), ep)
+	if result.Status != "degraded" {
+		t.Errorf("Status = %q, want degraded", result.Status)
+	}
+	if result.Error == nil {
+		t.Error("Error = nil, want latency reason")
+	}
+}
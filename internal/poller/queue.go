@@ -0,0 +1,87 @@
+package poller
+
+import (
+	"container/heap"
+	"time"
+)
+
+// scheduledEndpoint tracks the polling state of a single endpoint.
+//
+// Entries are owned by the [Scheduler] and must only be accessed while
+// holding the scheduler's mutex.
+type scheduledEndpoint struct {
+	info EndpointInfo
+
+	// nextDue is when the endpoint should next be polled.
+	// The zero value means "poll immediately".
+	nextDue time.Time
+
+	// inFlight is true while a worker is polling this endpoint.
+	// Due ticks that arrive while a poll is in flight are skipped.
+	inFlight bool
+
+	// removed is set when the endpoint is unscheduled; a poll in flight for
+	// a removed entry does not emit its result.
+	removed bool
+
+	// index is the entry's position in the dueQueue, maintained by heap.Interface.
+	index int
+}
+
+// dueQueue is a min-heap of endpoints ordered by next due time.
+//
+// Popping the earliest entry is O(log n), so the dispatcher only does work
+// proportional to the number of endpoints that are actually due, regardless
+// of how many endpoints are configured.
+type dueQueue []*scheduledEndpoint
+
+// Len implements heap.Interface.
+func (q dueQueue) Len() int { return len(q) }
+
+// Less implements heap.Interface.
+func (q dueQueue) Less(i, j int) bool { return q[i].nextDue.Before(q[j].nextDue) }
+
+// Swap implements heap.Interface.
+func (q dueQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+// Push implements heap.Interface. Use heap.Push rather than calling directly.
+func (q *dueQueue) Push(x any) {
+	e := x.(*scheduledEndpoint)
+	e.index = len(*q)
+	*q = append(*q, e)
+}
+
+// Pop implements heap.Interface. Use heap.Pop rather than calling directly.
+func (q *dueQueue) Pop() any {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil // avoid retaining the entry
+	e.index = -1
+	*q = old[:n-1]
+	return e
+}
+
+// peek returns the entry with the earliest due time, or nil if empty.
+func (q dueQueue) peek() *scheduledEndpoint {
+	if len(q) == 0 {
+		return nil
+	}
+	return q[0]
+}
+
+// popDue removes and returns all entries due at or before now.
+func (q *dueQueue) popDue(now time.Time) []*scheduledEndpoint {
+	var due []*scheduledEndpoint
+	for {
+		next := q.peek()
+		if next == nil || next.nextDue.After(now) {
+			return due
+		}
+		due = append(due, heap.Pop(q).(*scheduledEndpoint))
+	}
+}
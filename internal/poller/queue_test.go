@@ -0,0 +1,61 @@
+package poller
+
+import (
+	"container/heap"
+	"testing"
+	"time"
+)
+
+func TestDueQueue_PopDueOrdersByTime(t *testing.T) {
+	base := time.Now()
+	q := dueQueue{}
+	for _, offset := range []int{30, 10, 20, 40} {
+		heap.Push(&q, &scheduledEndpoint{
+			nextDue: base.Add(time.Duration(offset) * time.Millisecond),
+		})
+	}
+
+	due := q.popDue(base.Add(25 * time.Millisecond))
+	if len(due) != 2 {
+		t.Fatalf("popDue() returned %d entries, want 2", len(due))
+	}
+	if !due[0].nextDue.Before(due[1].nextDue) {
+		t.Errorf("popDue() entries out of order: %v then %v", due[0].nextDue, due[1].nextDue)
+	}
+	for _, e := range due {
+		if e.index != -1 {
+			t.Errorf("popped entry index = %d, want -1", e.index)
+		}
+	}
+
+	if q.Len() != 2 {
+		t.Errorf("Len() = %d after popDue, want 2", q.Len())
+	}
+	if next := q.peek(); !next.nextDue.Equal(base.Add(30 * time.Millisecond)) {
+		t.Errorf("peek() due at %v, want +30ms", next.nextDue.Sub(base))
+	}
+}
+
+func TestDueQueue_ZeroTimeIsImmediatelyDue(t *testing.T) {
+	q := dueQueue{}
+	heap.Push(&q, &scheduledEndpoint{nextDue: time.Now().Add(time.Hour)})
+	heap.Push(&q, &scheduledEndpoint{})
+
+	due := q.popDue(time.Now())
+	if len(due) != 1 {
+		t.Fatalf("popDue() returned %d entries, want 1", len(due))
+	}
+	if !due[0].nextDue.IsZero() {
+		t.Errorf("popDue() returned entry due at %v, want zero time", due[0].nextDue)
+	}
+}
+
+func TestDueQueue_PeekEmpty(t *testing.T) {
+	q := dueQueue{}
+	if q.peek() != nil {
+		t.Error("peek() on empty queue should return nil")
+	}
+	if due := q.popDue(time.Now()); len(due) != 0 {
+		t.Errorf("popDue() on empty queue returned %d entries", len(due))
+	}
+}
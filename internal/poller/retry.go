@@ -0,0 +1,124 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetryWait caps how long a poll waits before a retry. If the backoff or
+// a server's Retry-After asks for longer, the poll gives up retrying instead.
+const maxRetryWait = 30 * time.Second
+
+// RetryOptions configures retries within a single poll.
+type RetryOptions struct {
+	// Retries is the number of extra attempts made after a failed attempt.
+	// Zero disables retries.
+	Retries int
+
+	// Backoff is the wait before the first retry. It doubles for each
+	// further retry.
+	Backoff time.Duration
+
+	// ShareTimeout makes all attempts, and the waits between them, share the
+	// endpoint's Timeout. Otherwise each attempt gets the full Timeout.
+	ShareTimeout bool
+}
+
+// pollWithRetries polls ep, retrying failed attempts as configured by
+// ep.Retry. An attempt is retried if it failed with a transport error or
+// timeout, or with a 5xx or 429 response. A Retry-After header on 429 and 503
+// responses is honoured when it asks for a longer wait than the backoff.
+func (s *Scheduler) pollWithRetries(ctx context.Context, ep EndpointInfo) StatusResult {
+	var deadline time.Time
+	if ep.Retry.ShareTimeout {
+		deadline = time.Now().Add(ep.Timeout)
+	}
+
+	var attemptErrors []error
+	for attempt := 1; ; attempt++ {
+		attemptEp := ep
+		if !deadline.IsZero() {
+			attemptEp.Timeout = time.Until(deadline)
+		}
+
+		result := s.probe(ctx, attemptEp)
+		result.Attempts = attempt
+		result.AttemptErrors = attemptErrors
+
+		if attempt > ep.Retry.Retries || !shouldRetry(result) {
+			return result
+		}
+
+		wait := retryBackoff(ep.Retry.Backoff, attempt)
+		if result.retryAfter > wait {
+			wait = result.retryAfter
+		}
+		if wait > maxRetryWait {
+			return result
+		}
+		if !deadline.IsZero() && time.Until(deadline) <= wait {
+			return result
+		}
+
+		attemptErrors = append(attemptErrors, attemptError(result))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return result
+		}
+	}
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying: it got no
+// answer at all, reported by a [TransportError], or a 5xx or 429 response.
+// Definite answers, such as unexpected DNS records, a NOT_SERVING health
+// check or an expiring certificate, are not retried.
+func shouldRetry(r StatusResult) bool {
+	if r.Status == "up" {
+		return false
+	}
+	return r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500 ||
+		IsTransportError(r.Error)
+}
+
+// retryBackoff returns the wait before retry number attempt, doubling base
+// for each retry after the first.
+func retryBackoff(base time.Duration, attempt int) time.Duration {
+	wait := base
+	for i := 1; i < attempt && wait < maxRetryWait; i++ {
+		wait *= 2
+	}
+	return wait
+}
+
+// attemptError describes why an attempt failed.
+func attemptError(r StatusResult) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	return fmt.Errorf("HTTP %d", r.StatusCode)
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of
+// seconds or as an HTTP date. It returns 0 if the header is absent or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
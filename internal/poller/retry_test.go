@@ -0,0 +1,238 @@
+package poller
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer answers with failStatus for the first failures requests and
+// 200 after that, counting requests.
+func flakyServer(t *testing.T, failures int32, failStatus int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(failStatus)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestPollEndpoint_RetriesUntilSuccess(t *testing.T) {
+	server, calls := flakyServer(t, 2, http.StatusBadGateway, nil)
+
+	s := NewScheduler(nil, time.Hour, 1, testLogger())
+	ep := EndpointInfo{
+		Name: "API", URL: server.URL, Timeout: time.Second,
+		Retry: RetryOptions{Retries: 3, Backoff: 10 * time.Millisecond},
+	}
+
+	result := s.pollEndpoint(context.Background(), ep)
+	if result.Status != "up" {
+		t.Errorf("Status = %q, want up", result.Status)
+	}
+	if result.Attempts != 3 || calls.Load() != 3 {
+		t.Errorf("Attempts = %d, calls = %d, want 3", result.Attempts, calls.Load())
+	}
+	if len(result.AttemptErrors) != 2 || result.AttemptErrors[0].Error() != "HTTP 502" {
+		t.Errorf("AttemptErrors = %v, want two HTTP 502 errors", result.AttemptErrors)
+	}
+}
+
+func TestPollEndpoint_GivesUpAfterRetries(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusInternalServerError, nil)
+
+	s := NewScheduler(nil, time.Hour, 1, testLogger())
+	ep := EndpointInfo{
+		Name: "API", URL: server.URL, Timeout: time.Second,
+		Retry: RetryOptions{Retries: 2},
+	}
+
+	result := s.pollEndpoint(context.Background(), ep)
+	if result.Status != "down" {
+		t.Errorf("Status = %q, want down", result.Status)
+	}
+	if result.Attempts != 3 || calls.Load() != 3 {
+		t.Errorf("Attempts = %d, calls = %d, want 3", result.Attempts, calls.Load())
+	}
+	if len(result.AttemptErrors) != 2 {
+		t.Errorf("AttemptErrors = %v, want 2 entries", result.AttemptErrors)
+	}
+}
+
+func TestPollEndpoint_DoesNotRetryClientErrors(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusNotFound, nil)
+
+	s := NewScheduler(nil, time.Hour, 1, testLogger())
+	ep := EndpointInfo{
+		Name: "API", URL: server.URL, Timeout: time.Second,
+		Retry: RetryOptions{Retries: 3},
+	}
+
+	result := s.pollEndpoint(context.Background(), ep)
+	if result.Attempts != 1 || calls.Load() != 1 {
+		t.Errorf("Attempts = %d, calls = %d, want 1", result.Attempts, calls.Load())
+	}
+}
+
+func TestPollEndpoint_RetriesTransportErrors(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	s := NewScheduler(nil, time.Hour, 1, testLogger())
+	ep := EndpointInfo{
+		Name: "DB", URL: "tcp://" + addr, Type: TypeTCP, Timeout: time.Second,
+		Retry: RetryOptions{Retries: 2, Backoff: time.Millisecond},
+	}
+
+	result := s.pollEndpoint(context.Background(), ep)
+	if result.Status != "down" || result.Attempts != 3 {
+		t.Errorf("Status = %q, Attempts = %d, want down after 3 attempts", result.Status, result.Attempts)
+	}
+	if len(result.AttemptErrors) != 2 || !strings.Contains(result.AttemptErrors[0].Error(), "refused") {
+		t.Errorf("AttemptErrors = %v, want connection refused errors", result.AttemptErrors)
+	}
+	if !IsTransportError(result.Error) {
+		t.Errorf("Error = %v, want a transport error", result.Error)
+	}
+}
+
+func TestPollEndpoint_DoesNotRetryAnswers(t *testing.T) {
+	var calls atomic.Int32
+	banner := startTCPServer(t, func(conn net.Conn) {
+		calls.Add(1)
+		_, _ = conn.Write([]byte("220 wrong banner\r\n"))
+	})
+	dnsServer := startDNSServer(t, testDNSZone())
+	grpcAddr := startPlaintextGRPCServer(t, testHealthServer())
+	expiring, roots := newTestCert(t, time.Now().Add(5*24*time.Hour))
+
+	tests := []struct {
+		name string
+		ep   EndpointInfo
+	}{
+		{"tcp expect mismatch", EndpointInfo{URL: "tcp://" + banner, Type: TypeTCP,
+			TCP: TCPOptions{Expect: []byte("SSH-")}}},
+		{"dns answer mismatch", EndpointInfo{URL: "dns://api.pulseboard.test.", Type: TypeDNS,
+			DNS: DNSOptions{Server: dnsServer, Expect: []string{"10.0.0.9"}}}},
+		{"dns nxdomain", EndpointInfo{URL: "dns://missing.pulseboard.test.", Type: TypeDNS,
+			DNS: DNSOptions{Server: dnsServer}}},
+		{"grpc not serving", EndpointInfo{URL: "grpc://" + grpcAddr, Type: TypeGRPC,
+			GRPC: GRPCOptions{Service: "payments", Insecure: true}}},
+		{"grpc service unknown", EndpointInfo{URL: "grpc://" + grpcAddr, Type: TypeGRPC,
+			GRPC: GRPCOptions{Service: "retired.v1", Insecure: true}}},
+		{"certificate expiring", EndpointInfo{URL: "tls://" + startTLSServer(t, expiring), Type: TypeTLS,
+			TLS: TLSOptions{WarnWithin: 21 * 24 * time.Hour, RootCAs: roots}}},
+	}
+
+	s := NewScheduler(nil, time.Hour, 1, testLogger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := tt.ep
+			ep.Name, ep.Timeout = tt.name, 2*time.Second
+			ep.Retry = RetryOptions{Retries: 2, Backoff: time.Millisecond}
+
+			result := s.pollEndpoint(context.Background(), ep)
+			if result.Status == "up" || result.Error == nil {
+				t.Fatalf("Status = %q, Error = %v, want a failed check", result.Status, result.Error)
+			}
+			if result.Attempts != 1 {
+				t.Errorf("Attempts = %d, want 1 for a definite answer (%v)", result.Attempts, result.Error)
+			}
+			if IsTransportError(result.Error) {
+				t.Errorf("Error = %v, want no transport error", result.Error)
+			}
+		})
+	}
+	if calls.Load() != 1 {
+		t.Errorf("tcp connections = %d, want 1", calls.Load())
+	}
+}
+
+func TestPollEndpoint_HonoursRetryAfter(t *testing.T) {
+	server, _ := flakyServer(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"1"}})
+
+	s := NewScheduler(nil, time.Hour, 1, testLogger())
+	ep := EndpointInfo{
+		Name: "API", URL: server.URL, Timeout: time.Second,
+		Retry: RetryOptions{Retries: 1, Backoff: time.Millisecond},
+	}
+
+	start := time.Now()
+	result := s.pollEndpoint(context.Background(), ep)
+	if result.Status != "up" || result.Attempts != 2 {
+		t.Errorf("Status = %q, Attempts = %d, want up after 2 attempts", result.Status, result.Attempts)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", elapsed)
+	}
+}
+
+func TestPollEndpoint_SharedTimeoutBudget(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusInternalServerError, nil)
+
+	s := NewScheduler(nil, time.Hour, 1, testLogger())
+	ep := EndpointInfo{
+		Name: "API", URL: server.URL, Timeout: 300 * time.Millisecond,
+		Retry: RetryOptions{Retries: 5, Backoff: 100 * time.Millisecond, ShareTimeout: true},
+	}
+
+	// waits of 100ms then 200ms leave no budget for a third retry
+	start := time.Now()
+	result := s.pollEndpoint(context.Background(), ep)
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("poll took %s, want within the 300ms budget", elapsed)
+	}
+	if result.Attempts != 2 || calls.Load() != 2 {
+		t.Errorf("Attempts = %d, calls = %d, want 2", result.Attempts, calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-1", 0},
+		{"soon", 0},
+		{"Thu, 01 Jan 2026 12:00:30 GMT", 30 * time.Second},
+		{"Thu, 01 Jan 2026 11:00:00 GMT", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	base := 100 * time.Millisecond
+	for attempt, want := range map[int]time.Duration{1: base, 2: 2 * base, 3: 4 * base} {
+		if got := retryBackoff(base, attempt); got != want {
+			t.Errorf("retryBackoff(%s, %d) = %s, want %s", base, attempt, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package poller
 
 import (
+	"container/heap"
 	"context"
 	"fmt"
 	"log/slog"
@@ -23,8 +24,16 @@ type StatusResult struct {
 	URL string
 
 	// Status is the determined health status as a string (e.g., "up", "down").
+	// After [Damper.Apply] it is the published status.
 	Status string
 
+	// RawStatus is the status the poll itself produced, set by [Damper.Apply].
+	// Empty until the result has been through a Damper.
+	RawStatus string
+
+	// Flapping is set by [Damper.Apply] while the endpoint is flapping.
+	Flapping bool
+
 	// Labels contains the key-value metadata associated with the endpoint.
 	Labels map[string]string
 
@@ -42,6 +51,21 @@ type StatusResult struct {
 
 	// StatusCode is the HTTP status code returned by the endpoint.
 	StatusCode int
+
+	// Cert describes the server's leaf certificate when a certificate check
+	// was performed. nil otherwise.
+	Cert *CertInfo
+
+	// Attempts is the number of attempts made during the poll, including
+	// retries.
+	Attempts int
+
+	// AttemptErrors holds the error of each earlier attempt that was retried,
+	// in order. The final attempt's error is in Error.
+	AttemptErrors []error
+
+	// retryAfter is the wait requested by a Retry-After response header.
+	retryAfter time.Duration
 }
 
 // StatusExtractor is a function that determines status from an HTTP response.
@@ -50,6 +74,26 @@ type StatusResult struct {
 // the pulseboard.Status type, avoiding circular dependencies.
 type StatusExtractor func(body []byte, statusCode int) string
 
+// Endpoint types understood by the scheduler. An empty type is treated as
+// [TypeHTTP].
+const (
+	// TypeHTTP polls the URL with an HTTP request via [Client.Fetch].
+	TypeHTTP = "http"
+
+	// TypeTCP opens a TCP connection to the tcp://host:port URL via [ProbeTCP].
+	TypeTCP = "tcp"
+
+	// TypeTLS checks the certificate served at the tls://host:port URL via [ProbeTLS].
+	TypeTLS = "tls"
+
+	// TypeDNS resolves the name in the dns://name URL via [ProbeDNS].
+	TypeDNS = "dns"
+
+	// TypeGRPC calls the gRPC health checking protocol at the grpc://host:port
+	// URL via [ProbeGRPC].
+	TypeGRPC = "grpc"
+)
+
 // EndpointInfo contains the configuration needed to poll a single endpoint.
 //
 // This is the poller-internal representation of an endpoint, decoupled from
@@ -64,7 +108,8 @@ type EndpointInfo struct {
 	// Labels contains key-value metadata for the endpoint.
 	Labels map[string]string
 
-	// Headers contains custom HTTP headers to send with requests.
+	// Headers contains custom HTTP headers to send with requests. For gRPC
+	// endpoints they are sent as call metadata.
 	Headers map[string]string
 
 	// Timeout is the per-request timeout duration.
@@ -77,27 +122,73 @@ type EndpointInfo struct {
 	// Method is the HTTP method (GET, HEAD, POST). Empty defaults to GET.
 	Method string
 
+	// Body is sent as the request body. nil sends no body.
+	Body []byte
+
+	// GraphQL marks the endpoint as a GraphQL query: a response with a
+	// non-empty errors array is reported down.
+	GraphQL bool
+
 	// Interval is the custom polling interval for this endpoint.
 	// If 0, the scheduler's global interval is used.
 	Interval time.Duration
+
+	// Type selects how the endpoint is probed ([TypeHTTP], [TypeTCP], [TypeTLS],
+	// [TypeDNS], [TypeGRPC]).
+	// Empty defaults to HTTP.
+	Type string
+
+	// TCP configures the optional send/expect exchange for TCP endpoints.
+	TCP TCPOptions
+
+	// TLS configures certificate checks for TLS endpoints, and for HTTPS
+	// endpoints when TLS.WarnWithin is non-zero.
+	TLS TLSOptions
+
+	// DNS configures the resolver, record type and expected answers for DNS
+	// endpoints.
+	DNS DNSOptions
+
+	// GRPC configures the service name and transport for gRPC endpoints.
+	GRPC GRPCOptions
+
+	// FailureThreshold is the number of consecutive results needed before a
+	// [Damper] publishes a change away from up. Values below 1 mean 1.
+	FailureThreshold int
+
+	// SuccessThreshold is the number of consecutive up results needed before
+	// a [Damper] publishes a change to up. Values below 1 mean 1.
+	SuccessThreshold int
+
+	// Flap configures flap detection in a [Damper].
+	Flap FlapOptions
+
+	// Retry configures retries of failed attempts within a single poll.
+	Retry RetryOptions
+
+	// Latency marks slow results as degraded or down.
+	Latency LatencyThresholds
 }
 
 // Scheduler manages periodic polling of multiple endpoints.
 //
-// Scheduler implements a worker pool pattern, polling configured endpoints
-// at their respective intervals with configurable concurrency. Results are
-// emitted to a channel that can be consumed by the caller.
+// Scheduler keeps endpoints in a priority queue ordered by next due time and
+// dispatches due endpoints to a persistent pool of workers. Each endpoint is
+// scheduled independently, so a slow endpoint only occupies a single worker
+// and never delays the polling of other endpoints. Results are emitted to a
+// channel that can be consumed by the caller.
 //
-// The scheduler polls all endpoints immediately on start, then uses a
-// tick-and-check pattern where it ticks at the GCD of all endpoint intervals
-// and polls only endpoints that are due.
+// All endpoints are due immediately on start. After that, each endpoint is
+// rescheduled at its own interval measured from when it was due. If a poll is
+// still in flight when the endpoint next becomes due, that tick is skipped
+// rather than queueing a second concurrent poll of the same endpoint.
 //
 // All lifecycle methods (Start, Stop) are safe for concurrent use.
 type Scheduler struct {
-	endpoints      []EndpointInfo
 	interval       time.Duration // global default interval
 	maxConcurrency int
 	client         *Client
+	grpc           grpcTransports
 	results        chan StatusResult
 	logger         *slog.Logger
 	ctx            context.Context
@@ -109,29 +200,48 @@ type Scheduler struct {
 	stopped   bool
 	closeOnce sync.Once
 
-	// per-endpoint timing for tick-and-check pattern
-	lastPolledAt map[string]time.Time
-	baseInterval time.Duration
+	// queue orders endpoints by next due time; guarded by mu
+	queue dueQueue
+
+	// wake interrupts the dispatcher's sleep when the queue changes
+	wake chan struct{}
+
+	// pending counts due polls taken from the queue but not yet handed to a
+	// worker; inFlight counts polls being run. Both guarded by mu.
+	pending  int
+	inFlight int
+}
+
+// pollJob is a unit of work handed from the dispatcher to a worker.
+type pollJob struct {
+	entry *scheduledEndpoint
+	info  EndpointInfo
 }
 
 // NewScheduler creates a new polling [Scheduler].
 //
 // Parameters:
 //   - endpoints: List of endpoints to poll
-//   - interval: Time between polling cycles
-//   - maxConcurrency: Maximum number of concurrent HTTP requests
+//   - interval: Default time between polls for endpoints without their own interval
+//   - maxConcurrency: Number of workers, i.e. the maximum number of concurrent requests
 //   - logger: Logger for scheduler events (panic recovery, etc.)
 //
 // The scheduler must be started with [Scheduler.Start] and stopped with
 // [Scheduler.Stop]. Results are available via [Scheduler.Results].
 func NewScheduler(endpoints []EndpointInfo, interval time.Duration, maxConcurrency int, logger *slog.Logger) *Scheduler {
+	queue := make(dueQueue, 0, len(endpoints))
+	for _, ep := range endpoints {
+		heap.Push(&queue, &scheduledEndpoint{info: ep})
+	}
+
 	return &Scheduler{
-		endpoints:      endpoints,
 		interval:       interval,
 		maxConcurrency: maxConcurrency,
 		client:         NewClient(),
 		results:        make(chan StatusResult, len(endpoints)),
 		logger:         logger,
+		queue:          queue,
+		wake:           make(chan struct{}, 1),
 	}
 }
 
@@ -143,49 +253,12 @@ func (s *Scheduler) Results() <-chan StatusResult {
 	return s.results
 }
 
-// calculateBaseInterval determines the tick interval for the scheduler.
-// Uses the GCD of all endpoint intervals to ensure timely polling.
-func (s *Scheduler) calculateBaseInterval() time.Duration {
-	if len(s.endpoints) == 0 {
-		return s.interval
-	}
-
-	intervals := make([]time.Duration, 0, len(s.endpoints))
-	for _, ep := range s.endpoints {
-		if ep.Interval > 0 {
-			intervals = append(intervals, ep.Interval)
-		} else {
-			intervals = append(intervals, s.interval)
-		}
-	}
-
-	result := intervals[0]
-	for _, d := range intervals[1:] {
-		result = gcdDuration(result, d)
-	}
-
-	// floor at 1 second to prevent CPU thrashing
-	if result < time.Second {
-		result = time.Second
-	}
-
-	return result
-}
-
-// gcdDuration calculates the greatest common divisor of two durations.
-func gcdDuration(a, b time.Duration) time.Duration {
-	for b != 0 {
-		a, b = b, a%b
-	}
-	return a
-}
-
-// Start begins the polling loop in a background goroutine.
+// Start begins the polling loop in background goroutines.
 //
 // Start is non-blocking and returns immediately. The scheduler will:
-//  1. Poll all endpoints immediately
-//  2. Tick at the GCD of all endpoint intervals
-//  3. Poll only endpoints that are due on each tick
+//  1. Start maxConcurrency workers
+//  2. Dispatch every endpoint immediately
+//  3. Sleep until the next endpoint is due, then dispatch only due endpoints
 //  4. Continue until [Scheduler.Stop] is called or the context is cancelled
 //
 // If ctx is nil, context.Background() is used as the parent context.
@@ -198,8 +271,6 @@ func (s *Scheduler) Start(ctx context.Context) {
 		return
 	}
 	s.started = true
-	s.lastPolledAt = make(map[string]time.Time, len(s.endpoints))
-	s.baseInterval = s.calculateBaseInterval()
 
 	if ctx == nil {
 		ctx = context.Background()
@@ -213,26 +284,29 @@ func (s *Scheduler) Start(ctx context.Context) {
 		defer s.wg.Done()
 		defer s.closeOnce.Do(func() { close(s.results) })
 
-		s.pollDueEndpoints(pollCtx, true)
-
-		ticker := time.NewTicker(s.baseInterval)
-		defer ticker.Stop()
+		jobs := make(chan pollJob)
 
-		for {
-			select {
-			case <-pollCtx.Done():
-				return
-			case <-ticker.C:
-				s.pollDueEndpoints(pollCtx, false)
-			}
+		var workers sync.WaitGroup
+		for i := 0; i < s.maxConcurrency; i++ {
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				s.work(pollCtx, jobs)
+			}()
 		}
+
+		s.dispatch(pollCtx, jobs)
+
+		// dispatcher only returns on cancellation; let workers drain and exit
+		close(jobs)
+		workers.Wait()
 	}()
 }
 
 // Stop halts the scheduler and waits for all goroutines to complete.
 //
 // Stop cancels the scheduler's context and blocks until:
-//   - The polling loop exits
+//   - The dispatcher exits
 //   - All in-flight requests complete
 //   - The results channel is closed
 //
@@ -254,86 +328,241 @@ func (s *Scheduler) Stop() {
 	if s.client != nil {
 		s.client.Close()
 	}
+	s.grpc.closeIdleConnections()
 
 	// ensure channel is closed even if Start() was never called
 	s.closeOnce.Do(func() { close(s.results) })
 }
 
-// pollDueEndpoints polls only endpoints that are due based on their intervals.
-// If immediate is true, polls all endpoints regardless of timing.
-//
-// TIMING SEMANTIC: lastPolledAt is updated when a poll STARTS, not when it
-// completes. This prevents concurrent polls of the same endpoint but means
-// effective interval = configured interval + poll duration for slow endpoints.
-func (s *Scheduler) pollDueEndpoints(ctx context.Context, immediate bool) {
-	now := time.Now()
-	dueEndpoints := make([]EndpointInfo, 0, len(s.endpoints))
+// Add schedules a new endpoint, polling it immediately if the scheduler is
+// running. Returns an error if an endpoint with the same name is scheduled.
+func (s *Scheduler) Add(ep EndpointInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	if s.find(ep.Name) != nil {
+		return fmt.Errorf("endpoint %q is already scheduled", ep.Name)
+	}
+	heap.Push(&s.queue, &scheduledEndpoint{info: ep})
+	s.notify()
+	return nil
+}
+
+// Remove unschedules the named endpoint and reports whether it was found.
+// The result of a poll already in flight for the endpoint is discarded.
+func (s *Scheduler) Remove(name string) bool {
 	s.mu.Lock()
-	for _, ep := range s.endpoints {
-		if immediate {
-			dueEndpoints = append(dueEndpoints, ep)
-			s.lastPolledAt[ep.Name] = now
+	defer s.mu.Unlock()
+
+	e := s.find(name)
+	if e == nil {
+		return false
+	}
+	heap.Remove(&s.queue, e.index)
+	e.removed = true
+	return true
+}
+
+// Update replaces the configuration of the endpoint with the same name and
+// polls it immediately. Returns an error if no such endpoint is scheduled.
+// The result of a poll already in flight with the old configuration is
+// discarded.
+func (s *Scheduler) Update(ep EndpointInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.find(ep.Name)
+	if e == nil {
+		return fmt.Errorf("endpoint %q is not scheduled", ep.Name)
+	}
+	heap.Remove(&s.queue, e.index)
+	e.removed = true
+	heap.Push(&s.queue, &scheduledEndpoint{info: ep})
+	s.notify()
+	return nil
+}
+
+// QueueDepth returns the number of due polls waiting for a free worker.
+func (s *Scheduler) QueueDepth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// InFlight returns the number of polls currently running.
+func (s *Scheduler) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inFlight
+}
+
+// find returns the queue entry for the named endpoint, or nil.
+// Must be called with mu held.
+func (s *Scheduler) find(name string) *scheduledEndpoint {
+	for _, e := range s.queue {
+		if e.info.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// notify wakes the dispatcher so it re-reads the queue. It never blocks.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch hands due endpoints to workers until ctx is cancelled.
+//
+// The dispatcher sleeps until the earliest due time in the queue, so CPU use
+// scales with the amount of due work rather than the number of endpoints.
+// Sending to jobs blocks while every worker is busy, which applies
+// backpressure without dropping due endpoints.
+func (s *Scheduler) dispatch(ctx context.Context, jobs chan<- pollJob) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		due, wait := s.takeDue(time.Now())
+
+		for _, job := range due {
+			select {
+			case jobs <- job:
+				s.mu.Lock()
+				s.pending--
+				s.inFlight++
+				s.mu.Unlock()
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		// more work may have become due while we were blocked on workers
+		if len(due) > 0 {
 			continue
 		}
 
-		interval := ep.Interval
-		if interval == 0 {
-			interval = s.interval // use global default
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
 		}
+		timer.Reset(wait)
 
-		lastPolled, exists := s.lastPolledAt[ep.Name]
-		if !exists || now.Sub(lastPolled) >= interval {
-			dueEndpoints = append(dueEndpoints, ep)
-			s.lastPolledAt[ep.Name] = now
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.wake:
 		}
 	}
-	s.mu.Unlock()
+}
 
-	if len(dueEndpoints) == 0 {
-		return
+// takeDue removes all due endpoints from the queue, reschedules them at their
+// next interval, and returns jobs for those not already in flight. It also
+// returns how long the dispatcher may sleep before the next endpoint is due.
+//
+// TIMING SEMANTIC: the next due time is measured from when the endpoint was
+// due, not from when its poll completes. A poll that overruns its interval
+// causes the following tick to be skipped, never a concurrent poll of the
+// same endpoint.
+func (s *Scheduler) takeDue(now time.Time) ([]pollJob, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.queue.popDue(now)
+	jobs := make([]pollJob, 0, len(entries))
+	for _, e := range entries {
+		if !e.inFlight {
+			e.inFlight = true
+			jobs = append(jobs, pollJob{entry: e, info: e.info})
+		}
+
+		interval := e.info.Interval
+		if interval <= 0 {
+			interval = s.interval // use global default
+		}
+		next := e.nextDue.Add(interval)
+		if !next.After(now) {
+			// fell behind (first poll, or workers saturated); don't burst to catch up
+			next = now.Add(interval)
+		}
+		e.nextDue = next
+		heap.Push(&s.queue, e)
 	}
 
-	s.pollEndpoints(ctx, dueEndpoints)
-}
+	s.pending += len(jobs)
 
-// pollEndpoints polls a subset of endpoints concurrently, respecting maxConcurrency.
-func (s *Scheduler) pollEndpoints(ctx context.Context, endpoints []EndpointInfo) {
-	jobs := make(chan EndpointInfo, len(endpoints))
-
-	var wg sync.WaitGroup
-	for i := 0; i < s.maxConcurrency; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for ep := range jobs {
-				result := s.pollEndpoint(ctx, ep)
-				select {
-				case s.results <- result:
-				case <-ctx.Done():
-					return
-				}
-			}
-		}()
+	wait := time.Hour
+	if next := s.queue.peek(); next != nil {
+		wait = next.nextDue.Sub(now)
 	}
+	return jobs, wait
+}
+
+// work polls endpoints received from jobs and emits their results.
+func (s *Scheduler) work(ctx context.Context, jobs <-chan pollJob) {
+	for job := range jobs {
+		result := s.pollEndpoint(ctx, job.info)
+
+		s.mu.Lock()
+		job.entry.inFlight = false
+		s.inFlight--
+		removed := job.entry.removed
+		s.mu.Unlock()
+
+		// the endpoint was removed or reconfigured while being polled
+		if removed {
+			continue
+		}
 
-	for _, ep := range endpoints {
 		select {
-		case jobs <- ep:
+		case s.results <- result:
 		case <-ctx.Done():
-			close(jobs)
-			wg.Wait()
 			return
 		}
 	}
-	close(jobs)
-
-	wg.Wait()
 }
 
-// pollEndpoint polls a single endpoint and returns the result.
+// pollEndpoint polls a single endpoint, retrying as configured by ep.Retry
+// and applying ep.Latency thresholds, and returns the result.
 func (s *Scheduler) pollEndpoint(ctx context.Context, ep EndpointInfo) StatusResult {
-	resp := s.client.Fetch(ctx, ep.Method, ep.URL, ep.Headers, ep.Timeout)
+	var result StatusResult
+	if ep.Retry.Retries > 0 {
+		result = s.pollWithRetries(ctx, ep)
+	} else {
+		result = s.probe(ctx, ep)
+		result.Attempts = 1
+	}
+
+	// latency thresholds apply after extraction and can only make it worse
+	applyLatencyThresholds(&result, ep.Latency)
+	return result
+}
+
+// probe makes a single attempt at checking ep.
+func (s *Scheduler) probe(ctx context.Context, ep EndpointInfo) StatusResult {
+	switch ep.Type {
+	case TypeTCP:
+		return s.pollTCP(ctx, ep)
+	case TypeTLS:
+		return s.pollTLS(ctx, ep)
+	case TypeDNS:
+		return s.pollDNS(ctx, ep)
+	case TypeGRPC:
+		return s.pollGRPC(ctx, ep)
+	default:
+		return s.pollHTTP(ctx, ep)
+	}
+}
+
+// pollHTTP polls an HTTP endpoint and applies its extractor to the response.
+func (s *Scheduler) pollHTTP(ctx context.Context, ep EndpointInfo) StatusResult {
+	resp := s.client.Fetch(ctx, ep.Method, ep.URL, ep.Headers, ep.Body, ep.Timeout)
 
 	result := StatusResult{
 		EndpointName: ep.Name,
@@ -344,6 +573,7 @@ func (s *Scheduler) pollEndpoint(ctx context.Context, ep EndpointInfo) StatusRes
 		RawResponse:  resp.Body,
 		StatusCode:   resp.StatusCode,
 		Error:        resp.Error,
+		retryAfter:   resp.RetryAfter,
 	}
 
 	if resp.Error != nil {
@@ -359,6 +589,24 @@ func (s *Scheduler) pollEndpoint(ctx context.Context, ep EndpointInfo) StatusRes
 		result.Status = httpStatusToStatus(resp.StatusCode)
 	}
 
+	// GraphQL servers report failures in the body, often with a 200 status
+	if ep.GraphQL && resp.Error == nil {
+		if err := graphQLError(resp.Body); err != nil {
+			result.Status = "down"
+			result.Error = err
+		}
+	}
+
+	// optional certificate expiry check can only make the status worse
+	if ep.TLS.WarnWithin > 0 && resp.Cert != nil {
+		result.Cert = resp.Cert
+		status, err := certStatus(resp.Cert, ep.TLS.WarnWithin, result.CheckedAt)
+		if statusSeverity(status) > statusSeverity(result.Status) {
+			result.Status = status
+			result.Error = err
+		}
+	}
+
 	return result
 }
 
@@ -385,6 +633,21 @@ func (s *Scheduler) safeExtract(extractor StatusExtractor, body []byte, statusCo
 	return extractor(body, statusCode), nil
 }
 
+// statusSeverity ranks statuses from healthiest to worst, so that checks
+// layered on top of an extractor can only make the status worse.
+func statusSeverity(status string) int {
+	switch status {
+	case "up":
+		return 0
+	case "unknown":
+		return 1
+	case "degraded":
+		return 2
+	default:
+		return 3
+	}
+}
+
 // httpStatusToStatus maps HTTP status codes to status strings.
 func httpStatusToStatus(code int) string {
 	switch {
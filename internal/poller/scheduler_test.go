@@ -360,81 +360,6 @@ func TestScheduler_ExtractorNilPanicRecovery(t *testing.T) {
 	}
 }
 
-// TestScheduler_GCDCalculation verifies that the base tick interval is
-// calculated correctly as the GCD of all endpoint intervals.
-func TestScheduler_GCDCalculation(t *testing.T) {
-	tests := []struct {
-		name           string
-		intervals      []time.Duration
-		globalInterval time.Duration
-		expectedBase   time.Duration
-	}{
-		{
-			name:           "all same interval",
-			intervals:      []time.Duration{10 * time.Second, 10 * time.Second},
-			globalInterval: 10 * time.Second,
-			expectedBase:   10 * time.Second,
-		},
-		{
-			name:           "5s and 10s gives GCD of 5s",
-			intervals:      []time.Duration{5 * time.Second, 10 * time.Second},
-			globalInterval: 30 * time.Second,
-			expectedBase:   5 * time.Second,
-		},
-		{
-			name:           "with zero (default) uses global",
-			intervals:      []time.Duration{6 * time.Second, 0}, // 0 = use global
-			globalInterval: 9 * time.Second,
-			expectedBase:   3 * time.Second, // GCD(6, 9) = 3
-		},
-		{
-			name:           "all use default",
-			intervals:      []time.Duration{0, 0, 0},
-			globalInterval: 15 * time.Second,
-			expectedBase:   15 * time.Second,
-		},
-		{
-			name:           "co-prime intervals",
-			intervals:      []time.Duration{7 * time.Second, 11 * time.Second},
-			globalInterval: 30 * time.Second,
-			expectedBase:   1 * time.Second, // GCD(7, 11) = 1
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			endpoints := make([]EndpointInfo, len(tt.intervals))
-			for i, interval := range tt.intervals {
-				endpoints[i] = EndpointInfo{
-					Name:     fmt.Sprintf("ep%d", i),
-					URL:      "http://example.com",
-					Timeout:  time.Second,
-					Interval: interval,
-				}
-			}
-
-			scheduler := NewScheduler(endpoints, tt.globalInterval, 1, testLogger())
-			base := scheduler.calculateBaseInterval()
-
-			if base != tt.expectedBase {
-				t.Errorf("calculateBaseInterval() = %v, want %v", base, tt.expectedBase)
-			}
-		})
-	}
-}
-
-// TestScheduler_GCDCalculation_EmptyEndpoints verifies that an empty endpoint
-// list returns the global interval as the base.
-func TestScheduler_GCDCalculation_EmptyEndpoints(t *testing.T) {
-	globalInterval := 20 * time.Second
-	scheduler := NewScheduler([]EndpointInfo{}, globalInterval, 1, testLogger())
-	base := scheduler.calculateBaseInterval()
-
-	if base != globalInterval {
-		t.Errorf("calculateBaseInterval() = %v, want %v (global)", base, globalInterval)
-	}
-}
-
 // TestScheduler_DefaultIntervalUsedWhenNotSpecified verifies that endpoints
 // without a custom interval use the global polling interval.
 func TestScheduler_DefaultIntervalUsedWhenNotSpecified(t *testing.T) {
@@ -443,7 +368,6 @@ func TestScheduler_DefaultIntervalUsedWhenNotSpecified(t *testing.T) {
 	}))
 	defer server.Close()
 
-	// use intervals >= 1 second (the GCD floor) for realistic testing
 	endpoints := []EndpointInfo{
 		{Name: "Custom", URL: server.URL, Timeout: time.Second, Interval: 1 * time.Second},
 		{Name: "Default", URL: server.URL, Timeout: time.Second, Interval: 0}, // should use global (3s)
@@ -487,7 +411,6 @@ func TestScheduler_MixedIntervals(t *testing.T) {
 	}))
 	defer server.Close()
 
-	// use intervals >= 1 second (the GCD floor) for realistic testing
 	endpoints := []EndpointInfo{
 		{Name: "Fast", URL: server.URL, Timeout: time.Second, Interval: 1 * time.Second},
 		{Name: "Slow", URL: server.URL, Timeout: time.Second, Interval: 3 * time.Second},
@@ -553,3 +476,258 @@ func TestScheduler_ImmediatePollOnStart(t *testing.T) {
 
 	scheduler.Stop()
 }
+
+// TestScheduler_SlowEndpointDoesNotDelayOthers verifies that an endpoint
+// whose request hangs does not hold back polling of other endpoints.
+func TestScheduler_SlowEndpointDoesNotDelayOthers(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fast.Close()
+
+	endpoints := []EndpointInfo{
+		{Name: "Slow", URL: slow.URL, Timeout: 5 * time.Second, Interval: 50 * time.Millisecond},
+		{Name: "Fast", URL: fast.URL, Timeout: time.Second, Interval: 50 * time.Millisecond},
+	}
+
+	scheduler := NewScheduler(endpoints, time.Hour, 2, testLogger())
+	scheduler.Start(context.Background())
+
+	counts := make(map[string]int)
+	timeout := time.After(600 * time.Millisecond)
+
+collecting:
+	for {
+		select {
+		case result := <-scheduler.Results():
+			counts[result.EndpointName]++
+		case <-timeout:
+			break collecting
+		}
+	}
+
+	scheduler.Stop()
+
+	// Fast should keep ticking every 50ms while Slow is stuck on its first poll
+	if counts["Fast"] < 5 {
+		t.Errorf("Fast polled %d times while Slow was in flight, want at least 5", counts["Fast"])
+	}
+	if counts["Slow"] > 0 {
+		t.Errorf("Slow produced %d results, want 0 (request should still be in flight)", counts["Slow"])
+	}
+}
+
+// TestScheduler_NoOverlappingPolls verifies that an endpoint is never polled
+// concurrently with itself, even when a poll overruns its interval.
+func TestScheduler_NoOverlappingPolls(t *testing.T) {
+	var mu sync.Mutex
+	var active, maxActive, total int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		total++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+
+		time.Sleep(100 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	endpoints := []EndpointInfo{
+		{Name: "Overrun", URL: server.URL, Timeout: time.Second, Interval: 10 * time.Millisecond},
+	}
+
+	// plenty of workers, so only in-flight tracking can prevent overlap
+	scheduler := NewScheduler(endpoints, time.Hour, 4, testLogger())
+	scheduler.Start(context.Background())
+
+	go func() {
+		for range scheduler.Results() {
+		}
+	}()
+
+	time.Sleep(450 * time.Millisecond)
+	scheduler.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxActive != 1 {
+		t.Errorf("max concurrent polls = %d, want 1", maxActive)
+	}
+	if total < 3 {
+		t.Errorf("total polls = %d, want at least 3", total)
+	}
+}
+
+// TestScheduler_ManyEndpoints verifies that every endpoint in a large set is
+// polled promptly on start.
+func TestScheduler_ManyEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	const n = 500
+	endpoints := make([]EndpointInfo, n)
+	for i := range endpoints {
+		endpoints[i] = EndpointInfo{Name: fmt.Sprintf("ep%d", i), URL: server.URL, Timeout: time.Second}
+	}
+
+	scheduler := NewScheduler(endpoints, time.Hour, 10, testLogger())
+	scheduler.Start(context.Background())
+
+	seen := make(map[string]bool, n)
+	timeout := time.After(10 * time.Second)
+	for len(seen) < n {
+		select {
+		case result := <-scheduler.Results():
+			seen[result.EndpointName] = true
+		case <-timeout:
+			t.Fatalf("received results for %d of %d endpoints", len(seen), n)
+		}
+	}
+
+	scheduler.Stop()
+}
+
+// TestScheduler_AddWhileRunning verifies that an endpoint added to a running
+// scheduler is polled immediately rather than after the dispatcher's sleep.
+func TestScheduler_AddWhileRunning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scheduler := NewScheduler(nil, time.Hour, 1, testLogger())
+	scheduler.Start(context.Background())
+	defer scheduler.Stop()
+
+	if err := scheduler.Add(EndpointInfo{Name: "Added", URL: server.URL, Timeout: time.Second}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := scheduler.Add(EndpointInfo{Name: "Added", URL: server.URL}); err == nil {
+		t.Error("Add() with duplicate name should fail")
+	}
+
+	select {
+	case result := <-scheduler.Results():
+		if result.EndpointName != "Added" {
+			t.Errorf("EndpointName = %q, want %q", result.EndpointName, "Added")
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Error("timeout waiting for added endpoint to be polled")
+	}
+}
+
+// TestScheduler_RemoveDropsInFlightResult verifies that removing an endpoint
+// while it is being polled discards the result and stops further polls.
+func TestScheduler_RemoveDropsInFlightResult(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	endpoints := []EndpointInfo{
+		{Name: "Gone", URL: server.URL, Timeout: 5 * time.Second, Interval: 20 * time.Millisecond},
+	}
+	scheduler := NewScheduler(endpoints, time.Hour, 1, testLogger())
+	scheduler.Start(context.Background())
+	defer scheduler.Stop()
+
+	<-started
+	if !scheduler.Remove("Gone") {
+		t.Fatal("Remove() = false, want true")
+	}
+	if scheduler.Remove("Gone") {
+		t.Error("second Remove() = true, want false")
+	}
+	release <- struct{}{}
+
+	select {
+	case result := <-scheduler.Results():
+		t.Errorf("got result for %q after removal", result.EndpointName)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestScheduler_Update verifies that an updated endpoint is polled
+// immediately with its new configuration.
+func TestScheduler_Update(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer down.Close()
+
+	endpoints := []EndpointInfo{{Name: "API", URL: up.URL, Timeout: time.Second}}
+	scheduler := NewScheduler(endpoints, time.Hour, 1, testLogger())
+	scheduler.Start(context.Background())
+	defer scheduler.Stop()
+
+	if result := <-scheduler.Results(); result.Status != "up" {
+		t.Fatalf("first Status = %q, want up", result.Status)
+	}
+
+	if err := scheduler.Update(EndpointInfo{Name: "API", URL: down.URL, Timeout: time.Second}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := scheduler.Update(EndpointInfo{Name: "Missing"}); err == nil {
+		t.Error("Update() of unknown endpoint should fail")
+	}
+
+	select {
+	case result := <-scheduler.Results():
+		if result.URL != down.URL || result.Status != "down" {
+			t.Errorf("URL = %q, Status = %q, want %q, down", result.URL, result.Status, down.URL)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Error("timeout waiting for updated endpoint to be polled")
+	}
+}
+
+func TestScheduler_QueueDepth(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	endpoints := []EndpointInfo{
+		{Name: "A", URL: slow.URL, Timeout: 5 * time.Second},
+		{Name: "B", URL: slow.URL, Timeout: 5 * time.Second},
+		{Name: "C", URL: slow.URL, Timeout: 5 * time.Second},
+	}
+
+	// one worker: one poll runs while the other two wait for it
+	scheduler := NewScheduler(endpoints, time.Hour, 1, testLogger())
+	scheduler.Start(context.Background())
+	defer scheduler.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for scheduler.InFlight() != 1 || scheduler.QueueDepth() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("InFlight() = %d, QueueDepth() = %d, want 1 and 2", scheduler.InFlight(), scheduler.QueueDepth())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
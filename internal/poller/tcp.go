@@ -0,0 +1,158 @@
+package poller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+)
+
+// maxTCPResponseSize caps how much is read while waiting for an expected
+// response. Protocol banners are small; 4KB is plenty.
+const maxTCPResponseSize = 4 << 10
+
+// TCPOptions configures the optional byte exchange for a TCP probe.
+type TCPOptions struct {
+	// Send is written to the connection once established. May be empty.
+	Send []byte
+
+	// Expect must appear in the data read back from the connection for the
+	// probe to succeed. If empty, a successful connect is sufficient.
+	Expect []byte
+}
+
+// ProbeTCP opens a TCP connection to address and optionally performs a
+// send/expect exchange.
+//
+// The returned [Response] carries the connect latency (not including the
+// exchange) and, if an expectation was set, the bytes read in Body.
+// StatusCode is always zero. As with [Client.Fetch], errors are captured in
+// the Error field rather than returned separately.
+func ProbeTCP(ctx context.Context, address string, opts TCPOptions, timeout time.Duration) Response {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	latency := time.Since(start)
+	if err != nil {
+		return Response{
+			Latency: latency,
+			Error:   &TransportError{Err: fmt.Errorf("connect failed: %w", err)},
+		}
+	}
+	defer func() { _ = conn.Close() }()
+
+	if len(opts.Send) == 0 && len(opts.Expect) == 0 {
+		return Response{Latency: latency}
+	}
+
+	// bound the exchange by the same timeout as the connect
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if len(opts.Send) > 0 {
+		if _, err := conn.Write(opts.Send); err != nil {
+			return Response{
+				Latency: latency,
+				Error:   &TransportError{Err: fmt.Errorf("send failed: %w", err)},
+			}
+		}
+	}
+
+	if len(opts.Expect) == 0 {
+		return Response{Latency: latency}
+	}
+
+	body, err := readUntil(conn, opts.Expect, maxTCPResponseSize)
+	if !bytes.Contains(body, opts.Expect) {
+		msg := fmt.Sprintf("response does not contain %q", opts.Expect)
+		if err != nil && !errors.Is(err, io.EOF) {
+			// the read timed out or the connection broke before an answer
+			return Response{
+				Body:    body,
+				Latency: latency,
+				Error:   &TransportError{Err: fmt.Errorf("%s: %w", msg, err)},
+			}
+		}
+		return Response{
+			Body:    body,
+			Latency: latency,
+			Error:   errors.New(msg),
+		}
+	}
+
+	return Response{
+		Body:    body,
+		Latency: latency,
+	}
+}
+
+// readUntil reads from r until the accumulated data contains want, limit
+// bytes have been read, or a read fails. It returns everything read so far
+// along with the read error, if any.
+func readUntil(r io.Reader, want []byte, limit int) ([]byte, error) {
+	buf := make([]byte, 0, 512)
+	chunk := make([]byte, 512)
+
+	for len(buf) < limit {
+		n, err := r.Read(chunk[:min(len(chunk), limit-len(buf))])
+		buf = append(buf, chunk[:n]...)
+		if bytes.Contains(buf, want) {
+			return buf, nil
+		}
+		if err != nil {
+			return buf, err
+		}
+	}
+	return buf, nil
+}
+
+// pollTCP performs a TCP probe for ep and converts it into a StatusResult.
+// The endpoint is up if the connection succeeds and any expectation is met.
+func (s *Scheduler) pollTCP(ctx context.Context, ep EndpointInfo) StatusResult {
+	result := StatusResult{
+		EndpointName: ep.Name,
+		URL:          ep.URL,
+		Labels:       ep.Labels,
+	}
+
+	address, err := tcpAddress(ep.URL)
+	if err != nil {
+		result.Status = "down"
+		result.Error = err
+		result.CheckedAt = time.Now()
+		return result
+	}
+
+	resp := ProbeTCP(ctx, address, ep.TCP, ep.Timeout)
+	result.Latency = resp.Latency
+	result.CheckedAt = time.Now()
+	result.RawResponse = resp.Body
+	result.Error = resp.Error
+
+	if resp.Error != nil {
+		result.Status = "down"
+	} else {
+		result.Status = "up"
+	}
+	return result
+}
+
+// tcpAddress extracts host:port from a tcp:// URL.
+func tcpAddress(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid tcp address: %w", err)
+	}
+	if u.Host == "" || u.Port() == "" {
+		return "", fmt.Errorf("invalid tcp address %q: expected tcp://host:port", rawURL)
+	}
+	return u.Host, nil
+}
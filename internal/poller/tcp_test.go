@@ -0,0 +1,208 @@
+package poller
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startTCPServer starts a local TCP listener that runs handle for each
+// accepted connection. The listener is closed when the test ends.
+func startTCPServer(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				handle(conn)
+			}()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestProbeTCP_ConnectOnly(t *testing.T) {
+	addr := startTCPServer(t, func(net.Conn) {})
+
+	resp := ProbeTCP(context.Background(), addr, TCPOptions{}, time.Second)
+	if resp.Error != nil {
+		t.Fatalf("Error = %v, want nil", resp.Error)
+	}
+	if resp.Latency <= 0 {
+		t.Errorf("Latency = %v, want > 0", resp.Latency)
+	}
+	if resp.StatusCode != 0 {
+		t.Errorf("StatusCode = %d, want 0", resp.StatusCode)
+	}
+}
+
+func TestProbeTCP_ConnectRefused(t *testing.T) {
+	// grab a free port, then close it so nothing is listening
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	resp := ProbeTCP(context.Background(), addr, TCPOptions{}, time.Second)
+	if resp.Error == nil {
+		t.Fatal("Error = nil, want connect error")
+	}
+	if !strings.Contains(resp.Error.Error(), "connect failed") {
+		t.Errorf("Error = %q, want to contain 'connect failed'", resp.Error)
+	}
+}
+
+func TestProbeTCP_ExpectBanner(t *testing.T) {
+	addr := startTCPServer(t, func(c net.Conn) {
+		_, _ = c.Write([]byte("220 mail.example.com ESMTP ready\r\n"))
+	})
+
+	resp := ProbeTCP(context.Background(), addr, TCPOptions{Expect: []byte("220")}, time.Second)
+	if resp.Error != nil {
+		t.Fatalf("Error = %v, want nil", resp.Error)
+	}
+	if !strings.HasPrefix(string(resp.Body), "220 ") {
+		t.Errorf("Body = %q, want banner", resp.Body)
+	}
+}
+
+func TestProbeTCP_ExpectMismatch(t *testing.T) {
+	addr := startTCPServer(t, func(c net.Conn) {
+		_, _ = c.Write([]byte("554 no service\r\n"))
+	})
+
+	resp := ProbeTCP(context.Background(), addr, TCPOptions{Expect: []byte("220")}, time.Second)
+	if resp.Error == nil {
+		t.Fatal("Error = nil, want expectation error")
+	}
+	if !strings.Contains(resp.Error.Error(), `does not contain "220"`) {
+		t.Errorf("Error = %q, want expectation message", resp.Error)
+	}
+	if string(resp.Body) != "554 no service\r\n" {
+		t.Errorf("Body = %q, want server response", resp.Body)
+	}
+}
+
+func TestProbeTCP_ExpectTimeout(t *testing.T) {
+	// server accepts but never writes
+	addr := startTCPServer(t, func(c net.Conn) {
+		_, _ = bufio.NewReader(c).ReadString('\n')
+	})
+
+	start := time.Now()
+	resp := ProbeTCP(context.Background(), addr, TCPOptions{Expect: []byte("+PONG")}, 200*time.Millisecond)
+	if resp.Error == nil {
+		t.Fatal("Error = nil, want timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ProbeTCP took %v, want bounded by timeout", elapsed)
+	}
+}
+
+func TestProbeTCP_SendExpect(t *testing.T) {
+	addr := startTCPServer(t, func(c net.Conn) {
+		line, err := bufio.NewReader(c).ReadString('\n')
+		if err != nil {
+			return
+		}
+		if line == "PING\r\n" {
+			_, _ = c.Write([]byte("+PONG\r\n"))
+		}
+	})
+
+	opts := TCPOptions{Send: []byte("PING\r\n"), Expect: []byte("+PONG")}
+	resp := ProbeTCP(context.Background(), addr, opts, time.Second)
+	if resp.Error != nil {
+		t.Fatalf("Error = %v, want nil", resp.Error)
+	}
+}
+
+func TestScheduler_TCPEndpoint(t *testing.T) {
+	addr := startTCPServer(t, func(c net.Conn) {
+		_, _ = c.Write([]byte("SSH-2.0-OpenSSH\r\n"))
+	})
+
+	endpoints := []EndpointInfo{
+		{
+			Name:    "SSH",
+			URL:     "tcp://" + addr,
+			Type:    TypeTCP,
+			TCP:     TCPOptions{Expect: []byte("SSH-2.0")},
+			Timeout: time.Second,
+			Labels:  map[string]string{"proto": "ssh"},
+		},
+		{
+			Name:    "Wrong Banner",
+			URL:     "tcp://" + addr,
+			Type:    TypeTCP,
+			TCP:     TCPOptions{Expect: []byte("220")},
+			Timeout: time.Second,
+		},
+	}
+
+	scheduler := NewScheduler(endpoints, time.Hour, 2, testLogger())
+	scheduler.Start(context.Background())
+
+	results := make(map[string]StatusResult)
+	for len(results) < 2 {
+		select {
+		case r := <-scheduler.Results():
+			results[r.EndpointName] = r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for results")
+		}
+	}
+	scheduler.Stop()
+
+	if got := results["SSH"]; got.Status != "up" || got.Error != nil {
+		t.Errorf("SSH = (%q, %v), want (up, nil)", got.Status, got.Error)
+	}
+	if got := results["SSH"].Labels["proto"]; got != "ssh" {
+		t.Errorf("SSH labels[proto] = %q, want ssh", got)
+	}
+	if got := results["Wrong Banner"]; got.Status != "down" || got.Error == nil {
+		t.Errorf("Wrong Banner = (%q, %v), want (down, error)", got.Status, got.Error)
+	}
+}
+
+func TestTCPAddress(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "tcp://db.internal:5432", want: "db.internal:5432"},
+		{url: "tcp://[::1]:6379", want: "[::1]:6379"},
+		{url: "tcp://db.internal", wantErr: true},
+		{url: "tcp://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := tcpAddress(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tcpAddress(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("tcpAddress(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
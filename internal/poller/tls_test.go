@@ -0,0 +1,285 @@
+package poller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCert creates a CA and a leaf certificate for localhost signed by it.
+// It returns the leaf as a tls.Certificate and a pool containing the CA.
+func newTestCert(t *testing.T, notAfter time.Time) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA cert: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("failed to parse CA cert: %v", err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate leaf key: %v", err)
+	}
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, caCert, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create leaf cert: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	return tls.Certificate{Certificate: [][]byte{leafDER}, PrivateKey: leafKey}, pool
+}
+
+// startTLSServer starts a TLS listener on localhost serving cert.
+func startTLSServer(t *testing.T, cert tls.Certificate) string {
+	t.Helper()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				// complete the handshake, then hang up
+				_ = conn.(*tls.Conn).Handshake()
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestProbeTLS_ValidCertificate(t *testing.T) {
+	notAfter := time.Now().Add(90 * 24 * time.Hour).Truncate(time.Second)
+	cert, roots := newTestCert(t, notAfter)
+	addr := startTLSServer(t, cert)
+
+	resp := ProbeTLS(context.Background(), addr, TLSOptions{RootCAs: roots}, time.Second)
+	if resp.Error != nil {
+		t.Fatalf("Error = %v, want nil", resp.Error)
+	}
+	if resp.Cert == nil {
+		t.Fatal("Cert = nil, want certificate info")
+	}
+	if !resp.Cert.NotAfter.Equal(notAfter) {
+		t.Errorf("Cert.NotAfter = %v, want %v", resp.Cert.NotAfter, notAfter)
+	}
+	if !strings.Contains(resp.Cert.Issuer, "Test CA") {
+		t.Errorf("Cert.Issuer = %q, want to contain 'Test CA'", resp.Cert.Issuer)
+	}
+	if len(resp.Cert.DNSNames) != 1 || resp.Cert.DNSNames[0] != "localhost" {
+		t.Errorf("Cert.DNSNames = %v, want [localhost]", resp.Cert.DNSNames)
+	}
+}
+
+func TestProbeTLS_UntrustedChainKeepsCertInfo(t *testing.T) {
+	cert, _ := newTestCert(t, time.Now().Add(90*24*time.Hour))
+	addr := startTLSServer(t, cert)
+
+	// system roots don't trust the test CA
+	resp := ProbeTLS(context.Background(), addr, TLSOptions{}, time.Second)
+	if resp.Error == nil {
+		t.Fatal("Error = nil, want verification error")
+	}
+	if !strings.Contains(resp.Error.Error(), "verification failed") {
+		t.Errorf("Error = %q, want to contain 'verification failed'", resp.Error)
+	}
+	if resp.Cert == nil {
+		t.Error("Cert = nil, want certificate info even when verification fails")
+	}
+}
+
+func TestProbeTLS_NotListening(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	resp := ProbeTLS(context.Background(), addr, TLSOptions{}, time.Second)
+	if resp.Error == nil {
+		t.Fatal("Error = nil, want handshake error")
+	}
+	if resp.Cert != nil {
+		t.Errorf("Cert = %+v, want nil", resp.Cert)
+	}
+}
+
+func TestCertStatus(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	warn := 21 * 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		notAfter   time.Time
+		wantStatus string
+		wantErr    string
+	}{
+		{"valid", now.Add(60 * 24 * time.Hour), "up", ""},
+		{"inside warning window", now.Add(10 * 24 * time.Hour), "degraded", "expires in 10d"},
+		{"hours left", now.Add(5 * time.Hour), "degraded", "expires in 5h"},
+		{"expired", now.Add(-time.Hour), "down", "expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := certStatus(&CertInfo{NotAfter: tt.notAfter}, warn, now)
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduler_TLSEndpoint(t *testing.T) {
+	valid, roots := newTestCert(t, time.Now().Add(90*24*time.Hour))
+	expiring, expiringRoots := newTestCert(t, time.Now().Add(5*24*time.Hour))
+	expired, expiredRoots := newTestCert(t, time.Now().Add(-time.Hour))
+
+	endpoints := []EndpointInfo{
+		{Name: "Valid", URL: "tls://" + startTLSServer(t, valid), Type: TypeTLS, Timeout: time.Second,
+			TLS: TLSOptions{WarnWithin: 21 * 24 * time.Hour, RootCAs: roots}},
+		{Name: "Expiring", URL: "tls://" + startTLSServer(t, expiring), Type: TypeTLS, Timeout: time.Second,
+			TLS: TLSOptions{WarnWithin: 21 * 24 * time.Hour, RootCAs: expiringRoots}},
+		{Name: "Expired", URL: "tls://" + startTLSServer(t, expired), Type: TypeTLS, Timeout: time.Second,
+			TLS: TLSOptions{WarnWithin: 21 * 24 * time.Hour, RootCAs: expiredRoots}},
+		{Name: "Untrusted", URL: "tls://" + startTLSServer(t, valid), Type: TypeTLS, Timeout: time.Second,
+			TLS: TLSOptions{WarnWithin: 21 * 24 * time.Hour}},
+	}
+
+	scheduler := NewScheduler(endpoints, time.Hour, 4, testLogger())
+	scheduler.Start(context.Background())
+
+	results := make(map[string]StatusResult)
+	for len(results) < len(endpoints) {
+		select {
+		case r := <-scheduler.Results():
+			results[r.EndpointName] = r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for results")
+		}
+	}
+	scheduler.Stop()
+
+	want := map[string]string{
+		"Valid":     "up",
+		"Expiring":  "degraded",
+		"Expired":   "down",
+		"Untrusted": "down",
+	}
+	for name, status := range want {
+		got := results[name]
+		if got.Status != status {
+			t.Errorf("%s: Status = %q, want %q (error: %v)", name, got.Status, status, got.Error)
+		}
+		if got.Cert == nil {
+			t.Errorf("%s: Cert = nil, want certificate info", name)
+		}
+	}
+	if err := results["Expired"].Error; err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expired: Error = %v, want expiry message", err)
+	}
+}
+
+func TestScheduler_HTTPSCertExpiryCheck(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// httptest certificates are valid for decades; a century-long window
+	// guarantees the check fires
+	endpoints := []EndpointInfo{
+		{Name: "Checked", URL: server.URL, Timeout: time.Second,
+			TLS: TLSOptions{WarnWithin: 100 * 365 * 24 * time.Hour}},
+		{Name: "Unchecked", URL: server.URL, Timeout: time.Second},
+	}
+
+	scheduler := NewScheduler(endpoints, time.Hour, 2, testLogger())
+	scheduler.client = &Client{httpClient: server.Client()}
+	scheduler.Start(context.Background())
+
+	results := make(map[string]StatusResult)
+	for len(results) < len(endpoints) {
+		select {
+		case r := <-scheduler.Results():
+			results[r.EndpointName] = r
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for results")
+		}
+	}
+	scheduler.Stop()
+
+	checked := results["Checked"]
+	if checked.Status != "degraded" {
+		t.Errorf("Checked: Status = %q, want degraded", checked.Status)
+	}
+	if checked.Cert == nil {
+		t.Error("Checked: Cert = nil, want certificate info")
+	}
+
+	unchecked := results["Unchecked"]
+	if unchecked.Status != "up" {
+		t.Errorf("Unchecked: Status = %q, want up", unchecked.Status)
+	}
+	if unchecked.Cert != nil {
+		t.Errorf("Unchecked: Cert = %+v, want nil when check disabled", unchecked.Cert)
+	}
+}
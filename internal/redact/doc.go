@@ -0,0 +1,16 @@
+// Package redact masks credentials in URLs, error messages and log lines.
+//
+// This package is internal to PulseBoard. A [Redactor] masks:
+//
+//   - the password, or a lone username, in the userinfo of URLs
+//   - the values of well-known sensitive query parameters, such as api_key
+//     or token
+//   - any of a set of secret values, wherever they appear
+//
+// URLs are found anywhere in a string, so the URLs quoted in Go's HTTP
+// client errors are masked too. The zero value masks only the first two.
+//
+// Users of the pulseboard library should not need to interact with this
+// package directly. PulseBoard redacts results before they are stored,
+// logged or passed to callbacks.
+package redact
@@ -0,0 +1,161 @@
+package redact
+
+import (
+	"cmp"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Mask replaces redacted values, as in [url.URL.Redacted].
+const Mask = "xxxxx"
+
+// MinSecretLength is the length below which secret values are ignored, so
+// short values such as a port number do not mask unrelated text.
+const MinSecretLength = 4
+
+// urlPattern matches URLs with a scheme within text.
+var urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// sensitiveParams are query parameters whose values are always masked.
+var sensitiveParams = map[string]bool{
+	"key": true, "sig": true, "auth": true, "pass": true, "pwd": true,
+	"code": true, "jwt": true, "session": true,
+}
+
+// sensitiveFragments mark query parameters as sensitive when their names
+// contain one of them.
+var sensitiveFragments = []string{
+	"token", "secret", "passw", "signature", "credential", "apikey",
+	"api_key", "api-key", "access_key", "private",
+}
+
+// Redactor masks credentials and secret values. It is safe for concurrent
+// use; the zero value masks inline credentials only.
+type Redactor struct {
+	secrets []string // longest first, so overlapping values are fully masked
+}
+
+// New returns a Redactor that also masks each of secrets. Values shorter
+// than [MinSecretLength] are ignored.
+func New(secrets []string) *Redactor {
+	r := &Redactor{}
+	for _, s := range secrets {
+		if len(s) >= MinSecretLength && !slices.Contains(r.secrets, s) {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+	slices.SortFunc(r.secrets, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	return r
+}
+
+// With returns a Redactor that masks the secrets of r and secrets.
+func (r *Redactor) With(secrets ...string) *Redactor {
+	if r == nil {
+		return New(secrets)
+	}
+	return New(append(slices.Clone(r.secrets), secrets...))
+}
+
+// String masks the credentials in URLs within s and every secret value.
+func (r *Redactor) String(s string) string {
+	s = urlPattern.ReplaceAllStringFunc(s, URL)
+	if r == nil {
+		return s
+	}
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+	return s
+}
+
+// Bytes masks b as [Redactor.String] does, such as a response body that
+// echoes a request's credentials. Returns b itself if nothing is masked.
+func (r *Redactor) Bytes(b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	masked := r.String(string(b))
+	if masked == string(b) {
+		return b
+	}
+	return []byte(masked)
+}
+
+// Error returns err with its message masked by [Redactor.String]. The
+// result wraps err, so [errors.Is] and [errors.As] still see through it.
+// Returns err itself if nothing is masked.
+func (r *Redactor) Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	masked := r.String(msg)
+	if masked == msg {
+		return err
+	}
+	return &redactedError{msg: masked, err: err}
+}
+
+// redactedError reports a masked message for an error.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// URL masks the userinfo and sensitive query parameters of rawURL. Values
+// that do not parse as URLs are returned unchanged.
+func URL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.User == nil && u.RawQuery == "") {
+		return rawURL
+	}
+
+	changed := false
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), Mask)
+		} else {
+			u.User = url.User(Mask)
+		}
+		changed = true
+	}
+
+	params := strings.Split(u.RawQuery, "&")
+	for i, p := range params {
+		name, _, ok := strings.Cut(p, "=")
+		if !ok {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(name); err == nil && IsSensitiveParam(unescaped) {
+			params[i] = name + "=" + Mask
+			changed = true
+		}
+	}
+	if !changed {
+		return rawURL
+	}
+	u.RawQuery = strings.Join(params, "&")
+	return u.String()
+}
+
+// IsSensitiveParam reports whether the values of the query parameter or
+// header called name are masked.
+func IsSensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+	if sensitiveParams[name] || name == "authorization" || name == "cookie" {
+		return true
+	}
+	for _, f := range sensitiveFragments {
+		if strings.Contains(name, f) {
+			return true
+		}
+	}
+	return false
+}
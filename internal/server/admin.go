@@ -0,0 +1,189 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// maxSpecBytes caps the size of an endpoint management request body.
+const maxSpecBytes = 1 << 20
+
+// Errors an [EndpointManager] wraps to select the HTTP response status.
+// Any other error is reported as 400 Bad Request.
+var (
+	// ErrNotFound means the named endpoint does not exist (404).
+	ErrNotFound = errors.New("endpoint not found")
+
+	// ErrConflict means an endpoint with the same name already exists (409).
+	ErrConflict = errors.New("endpoint already exists")
+)
+
+// EndpointSpec is the JSON body accepted by the endpoint management API.
+//
+// It accepts the same endpoint settings as the YAML config, under the same
+// names. URL holds the address, host or URL appropriate for Type. Durations
+// are strings such as "5s", "1m" or "14d". Settings that do not apply to
+// Type are rejected.
+type EndpointSpec struct {
+	Name      string            `json:"name"`
+	URL       string            `json:"url"`
+	Type      string            `json:"type,omitempty"`
+	Method    string            `json:"method,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Timeout   string            `json:"timeout,omitempty"`
+	Interval  string            `json:"interval,omitempty"`
+	Extractor *ExtractorSpec    `json:"extractor,omitempty"`
+
+	// http only
+	Body    string       `json:"body,omitempty"`
+	GraphQL *GraphQLSpec `json:"graphql,omitempty"`
+
+	// tcp only
+	Send   string `json:"send,omitempty"`
+	Expect string `json:"expect,omitempty"`
+
+	// tls, or https to check the certificate alongside the health check
+	CertExpiryWarning string `json:"cert_expiry_warning,omitempty"`
+
+	// dns only
+	RecordType      string   `json:"record_type,omitempty"`
+	Resolver        string   `json:"resolver,omitempty"`
+	ExpectedAnswers []string `json:"expected_answers,omitempty"`
+
+	// grpc only
+	Service       string `json:"service,omitempty"`
+	Insecure      bool   `json:"insecure,omitempty"`
+	TLSSkipVerify bool   `json:"tls_skip_verify,omitempty"`
+
+	FailureThreshold   int                    `json:"failure_threshold,omitempty"`
+	SuccessThreshold   int                    `json:"success_threshold,omitempty"`
+	FlapDetection      *FlapDetectionSpec     `json:"flap_detection,omitempty"`
+	Retries            int                    `json:"retries,omitempty"`
+	RetryBackoff       string                 `json:"retry_backoff,omitempty"`
+	RetrySharedTimeout bool                   `json:"retry_shared_timeout,omitempty"`
+	LatencyThresholds  *LatencyThresholdsSpec `json:"latency_thresholds,omitempty"`
+}
+
+// ExtractorSpec selects a status extractor for an [EndpointSpec].
+type ExtractorSpec struct {
+	// Type is one of "default", "http", "json" or "contains".
+	Type string `json:"type"`
+
+	// Path is the dot-separated field path for the json extractor.
+	Path string `json:"path,omitempty"`
+
+	// Text is the substring for the contains extractor.
+	Text string `json:"text,omitempty"`
+}
+
+// GraphQLSpec is a GraphQL query sent as an [EndpointSpec]'s request body.
+type GraphQLSpec struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// FlapDetectionSpec enables flap detection for an [EndpointSpec].
+type FlapDetectionSpec struct {
+	Transitions int    `json:"transitions"`
+	Window      string `json:"window"`
+}
+
+// LatencyThresholdsSpec sets the latencies above which an [EndpointSpec]
+// is degraded or down. Either may be omitted.
+type LatencyThresholdsSpec struct {
+	Degraded string `json:"degraded,omitempty"`
+	Down     string `json:"down,omitempty"`
+}
+
+// EndpointManager changes the set of polled endpoints at runtime.
+//
+// Implementations must be safe for concurrent use.
+type EndpointManager interface {
+	// AddEndpoint starts polling a new endpoint.
+	AddEndpoint(spec EndpointSpec) error
+
+	// UpdateEndpoint replaces the endpoint with the same name.
+	UpdateEndpoint(spec EndpointSpec) error
+
+	// RemoveEndpoint stops polling the named endpoint and drops its status.
+	RemoveEndpoint(name string) error
+}
+
+// EnableEndpointAPI exposes mgr at /api/endpoints, guarded by a bearer token:
+//
+//   - POST /api/endpoints: add an endpoint described by an [EndpointSpec]
+//   - PUT /api/endpoints: replace an endpoint described by an [EndpointSpec]
+//   - DELETE /api/endpoints?name=...: remove an endpoint
+//
+// Requests must carry "Authorization: Bearer <token>". The API is not
+// served when token is empty. Must be called before [Server.Start].
+func (s *Server) EnableEndpointAPI(mgr EndpointManager, token string) {
+	if token == "" {
+		return
+	}
+	s.endpoints = mgr
+	s.adminToken = token
+}
+
+// handleEndpoints serves the endpoint management API.
+func (s *Server) handleEndpoints(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="pulseboard"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var err error
+	status := http.StatusNoContent
+	switch r.Method {
+	case http.MethodPost, http.MethodPut:
+		var spec EndpointSpec
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSpecBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&spec); err != nil {
+			http.Error(w, "invalid endpoint spec: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodPost {
+			err = s.endpoints.AddEndpoint(spec)
+			status = http.StatusCreated
+		} else {
+			err = s.endpoints.UpdateEndpoint(spec)
+		}
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name query parameter is required", http.StatusBadRequest)
+			return
+		}
+		err = s.endpoints.RemoveEndpoint(name)
+	default:
+		w.Header().Set("Allow", "POST, PUT, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		w.WriteHeader(status)
+	}
+}
+
+// authorized reports whether r carries the configured bearer token.
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
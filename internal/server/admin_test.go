@@ -0,0 +1,109 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeManager records calls and applies them to a set of names.
+type fakeManager struct {
+	mu    sync.Mutex
+	names map[string]EndpointSpec
+}
+
+func (f *fakeManager) AddEndpoint(spec EndpointSpec) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if spec.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	if _, ok := f.names[spec.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrConflict, spec.Name)
+	}
+	f.names[spec.Name] = spec
+	return nil
+}
+
+func (f *fakeManager) UpdateEndpoint(spec EndpointSpec) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.names[spec.Name]; !ok {
+		return fmt.Errorf("%w: %q", ErrNotFound, spec.Name)
+	}
+	f.names[spec.Name] = spec
+	return nil
+}
+
+func (f *fakeManager) RemoveEndpoint(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.names[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	delete(f.names, name)
+	return nil
+}
+
+func TestHandleEndpoints(t *testing.T) {
+	mgr := &fakeManager{names: map[string]EndpointSpec{"Existing": {Name: "Existing"}}}
+	srv := NewServer(newMockStore(), 0, nil, "", testLogger())
+	srv.EnableEndpointAPI(mgr, "s3cret")
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		auth   string
+		body   string
+		want   int
+	}{
+		{"missing token", http.MethodPost, "/api/endpoints", "", `{"name":"New","url":"https://example.com"}`, http.StatusUnauthorized},
+		{"wrong token", http.MethodPost, "/api/endpoints", "Bearer nope", `{"name":"New","url":"https://example.com"}`, http.StatusUnauthorized},
+		{"add", http.MethodPost, "/api/endpoints", "Bearer s3cret", `{"name":"New","url":"https://example.com"}`, http.StatusCreated},
+		{"add duplicate", http.MethodPost, "/api/endpoints", "Bearer s3cret", `{"name":"New","url":"https://example.com"}`, http.StatusConflict},
+		{"add invalid", http.MethodPost, "/api/endpoints", "Bearer s3cret", `{"name":"Bad"}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/api/endpoints", "Bearer s3cret", `{"name":"X","url":"https://example.com","colour":"red"}`, http.StatusBadRequest},
+		{"malformed json", http.MethodPost, "/api/endpoints", "Bearer s3cret", `{`, http.StatusBadRequest},
+		{"update", http.MethodPut, "/api/endpoints", "Bearer s3cret", `{"name":"Existing","url":"https://example.org"}`, http.StatusNoContent},
+		{"update unknown", http.MethodPut, "/api/endpoints", "Bearer s3cret", `{"name":"Nope","url":"https://example.org"}`, http.StatusNotFound},
+		{"delete", http.MethodDelete, "/api/endpoints?name=Existing", "Bearer s3cret", "", http.StatusNoContent},
+		{"delete unknown", http.MethodDelete, "/api/endpoints?name=Existing", "Bearer s3cret", "", http.StatusNotFound},
+		{"delete without name", http.MethodDelete, "/api/endpoints", "Bearer s3cret", "", http.StatusBadRequest},
+		{"get not allowed", http.MethodGet, "/api/endpoints", "Bearer s3cret", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+
+			srv.handleEndpoints(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body: %s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	if _, ok := mgr.names["New"]; !ok {
+		t.Error("New was not added")
+	}
+	if _, ok := mgr.names["Existing"]; ok {
+		t.Error("Existing was not removed")
+	}
+}
+
+func TestEnableEndpointAPI_EmptyTokenDisables(t *testing.T) {
+	srv := NewServer(newMockStore(), 0, nil, "", testLogger())
+	srv.EnableEndpointAPI(&fakeManager{}, "")
+	if srv.endpoints != nil {
+		t.Error("endpoint API should stay disabled without a token")
+	}
+}
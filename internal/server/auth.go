@@ -0,0 +1,341 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// sessionCookie carries a signed session, so browsers authenticate the
+	// SSE stream, which cannot send an Authorization header.
+	sessionCookie = "pulseboard_session"
+
+	// sessionTTL is how long a session cookie stays valid.
+	sessionTTL = 12 * time.Hour
+
+	// tokenParam is the query parameter that may carry an API token.
+	tokenParam = "access_token"
+
+	// maxVerifiedCredentials bounds the cache of checked basic-auth
+	// credentials before it is cleared.
+	maxVerifiedCredentials = 1000
+)
+
+// Auth configures authentication for every route. A request is allowed if
+// it carries any of:
+//
+//   - "Authorization: Basic ..." matching one of Users
+//   - "Authorization: Bearer <token>" with one of Tokens or the admin token
+//   - an access_token query parameter with one of those tokens
+//   - a client certificate verified against the client CAs of [TLS]
+//   - a session cookie, set after any of the above succeeds
+//
+// The zero value disables authentication.
+type Auth struct {
+	// Users maps usernames to bcrypt password hashes, as found in an
+	// htpasswd file.
+	Users map[string]string
+
+	// Tokens are accepted as bearer or query-parameter API tokens.
+	Tokens []string
+
+	// Public lists route paths served without authentication, such as the
+	// health check for probes. An entry ending in "/" matches every path
+	// beneath it.
+	Public []string
+}
+
+// enabled reports whether a has any credentials configured.
+func (a Auth) enabled() bool {
+	return len(a.Users) > 0 || len(a.Tokens) > 0
+}
+
+// ValidateAuth reports whether the users' hashes are bcrypt hashes and
+// the tokens and public paths are well formed.
+func ValidateAuth(a Auth) error {
+	for user, hash := range a.Users {
+		if user == "" || strings.Contains(user, ":") {
+			return fmt.Errorf("invalid auth username %q", user)
+		}
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return fmt.Errorf("auth user %q: password must be a bcrypt hash: %w", user, err)
+		}
+	}
+	for i, token := range a.Tokens {
+		if token == "" {
+			return fmt.Errorf("auth token %d is empty", i)
+		}
+	}
+	for _, path := range a.Public {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("auth public path must start with /, got %q", path)
+		}
+	}
+	return nil
+}
+
+// EnableAuth requires every request, except those for auth.Public paths,
+// to authenticate. The admin token set by [Server.EnableEndpointAPI] is
+// accepted as an API token. Must be called before [Server.Handler].
+func (s *Server) EnableAuth(auth Auth) {
+	s.authConfig = auth
+}
+
+// authenticator checks request credentials and issues session cookies.
+type authenticator struct {
+	users      map[string]string
+	tokens     []string
+	public     []string
+	adminToken string
+	basePath   string
+	key        []byte // signs session cookies; sessions end on restart
+	secure     bool   // mark cookies Secure even without TLS to this server
+	oidc       *oidcProvider
+	logger     *slog.Logger
+
+	// verified caches basic-auth credentials that matched, keyed by their
+	// SHA-256, because bcrypt is deliberately slow
+	mu       sync.Mutex
+	verified map[[sha256.Size]byte]string
+}
+
+// newAuthenticator creates an authenticator for the server's auth and OIDC
+// settings with a random session key, or returns nil if neither is set.
+func (s *Server) newAuthenticator() *authenticator {
+	if !s.authConfig.enabled() && s.oidcConfig == nil {
+		return nil
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate session key: %v", err))
+	}
+	a := &authenticator{
+		users:      s.authConfig.Users,
+		tokens:     s.authConfig.Tokens,
+		public:     s.authConfig.Public,
+		adminToken: s.adminToken,
+		basePath:   s.basePath,
+		key:        key,
+		logger:     s.logger,
+		verified:   make(map[[sha256.Size]byte]string),
+	}
+	if s.oidcConfig != nil {
+		a.oidc = newOIDCProvider(*s.oidcConfig, s.logger)
+		a.secure = strings.HasPrefix(s.oidcConfig.RedirectURL, "https://")
+	}
+	return a
+}
+
+// isPublic reports whether path is served without authentication.
+func (a *authenticator) isPublic(path string) bool {
+	if a.oidc != nil && strings.HasPrefix(path, "/auth/") {
+		return true
+	}
+	for _, p := range a.public {
+		if path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// middleware rejects unauthenticated requests with 401 Unauthorized, or
+// with OIDC enabled, sends browsers to log in.
+func (a *authenticator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.isPublic(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if subject, ok := a.session(r); ok {
+			sid, isOIDC := strings.CutPrefix(subject, oidcSubjectPrefix)
+			if !isOIDC {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if a.oidc != nil {
+				err := a.oidc.check(r.Context(), sid)
+				if err == nil {
+					a.serveOIDC(w, r, next, sid)
+					return
+				}
+				a.logger.Info("oidc session ended", "error", err)
+			}
+		}
+
+		user, ok := a.credentials(r)
+		if !ok {
+			a.challenge(w, r)
+			return
+		}
+
+		a.setSession(w, r, user)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// challenge responds to an unauthenticated request. With OIDC enabled,
+// browsers navigating to a page are redirected to log in; other requests
+// get 401 Unauthorized.
+func (a *authenticator) challenge(w http.ResponseWriter, r *http.Request) {
+	if a.oidc != nil && r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html") {
+		next := r.URL.Path
+		if r.URL.RawQuery != "" {
+			next += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, a.basePath+"/auth/login?next="+url.QueryEscape(next), http.StatusFound)
+		return
+	}
+	if len(a.users) > 0 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="PulseBoard", charset="UTF-8"`)
+	} else {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="PulseBoard"`)
+	}
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
+
+// credentials checks the client certificate, Authorization header and
+// access_token parameter, returning the user they identify.
+func (a *authenticator) credentials(r *http.Request) (string, bool) {
+	// chains are only verified when a client CA is configured
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return "cert:" + r.TLS.PeerCertificates[0].Subject.CommonName, true
+	}
+	if user, pass, ok := r.BasicAuth(); ok {
+		return user, a.checkPassword(user, pass)
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get(tokenParam)
+	}
+	if token == "" {
+		return "", false
+	}
+	if a.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) == 1 {
+		return "admin", true
+	}
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return "token", true
+		}
+	}
+	return "", false
+}
+
+// checkPassword reports whether pass is user's password.
+func (a *authenticator) checkPassword(user, pass string) bool {
+	hash, ok := a.users[user]
+	if !ok {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(user + ":" + pass))
+	a.mu.Lock()
+	cached, hit := a.verified[sum]
+	a.mu.Unlock()
+	if hit {
+		return cached == hash
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(pass)) != nil {
+		return false
+	}
+	a.mu.Lock()
+	if len(a.verified) >= maxVerifiedCredentials {
+		clear(a.verified)
+	}
+	a.verified[sum] = hash
+	a.mu.Unlock()
+	return true
+}
+
+// setSession issues a session cookie for subject, a username or an OIDC
+// session, scoped to the base path.
+func (a *authenticator) setSession(w http.ResponseWriter, r *http.Request, subject string) {
+	a.setCookie(w, r, sessionCookie, a.sign(subject, time.Now().Add(sessionTTL)), a.basePath+"/", sessionTTL)
+}
+
+// setCookie sets an HttpOnly cookie; a zero ttl deletes it.
+func (a *authenticator) setCookie(w http.ResponseWriter, r *http.Request, name, value, path string, ttl time.Duration) {
+	maxAge := int(ttl.Seconds())
+	if ttl == 0 {
+		maxAge = -1
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   a.secure || r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// session returns the subject of a valid session cookie.
+func (a *authenticator) session(r *http.Request) (string, bool) {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", false
+	}
+	user, err := a.verify(c.Value, time.Now())
+	if err != nil {
+		return "", false
+	}
+	return user, true
+}
+
+// sign encodes subject and expiry as "subject.expiry.mac".
+func (a *authenticator) sign(subject string, expiry time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(subject)) + "." + strconv.FormatInt(expiry.Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(a.mac(payload))
+}
+
+// verify checks a signed value and returns its subject.
+func (a *authenticator) verify(value string, now time.Time) (string, error) {
+	i := strings.LastIndexByte(value, '.')
+	if i < 0 {
+		return "", errors.New("malformed session")
+	}
+	payload, sig := value[:i], value[i+1:]
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, a.mac(payload)) {
+		return "", errors.New("invalid session signature")
+	}
+
+	encSubject, rawExpiry, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", errors.New("malformed session")
+	}
+	expiry, err := strconv.ParseInt(rawExpiry, 10, 64)
+	if err != nil || now.Unix() >= expiry {
+		return "", errors.New("session expired")
+	}
+	subject, err := base64.RawURLEncoding.DecodeString(encSubject)
+	if err != nil {
+		return "", errors.New("malformed session")
+	}
+	return string(subject), nil
+}
+
+// mac returns the HMAC-SHA256 of payload under the session key.
+func (a *authenticator) mac(payload string) []byte {
+	h := hmac.New(sha256.New, a.key)
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+func TestAuth(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := newMockStore()
+	ms.Update(store.StatusResult{Name: "API", Status: "up"})
+	srv := NewServer(ms, 0, nil, "", testLogger())
+	srv.EnableEndpointAPI(&fakeManager{}, "admin-token")
+	srv.SetBasePath("/status")
+	srv.EnableAuth(Auth{
+		Users:  map[string]string{"alice": string(hash)},
+		Tokens: []string{"api-token"},
+		Public: []string{DefaultHealthPath, "/badge/"},
+	})
+	handler, err := srv.Handler()
+	if err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		header   func(*http.Request)
+		wantCode int
+	}{
+		{"no credentials", "/status/api/status", nil, http.StatusUnauthorized},
+		{"basic auth", "/status/api/status", func(r *http.Request) { r.SetBasicAuth("alice", "s3cret") }, http.StatusOK},
+		{"wrong password", "/status/api/status", func(r *http.Request) { r.SetBasicAuth("alice", "wrong") }, http.StatusUnauthorized},
+		{"unknown user", "/status/api/status", func(r *http.Request) { r.SetBasicAuth("bob", "s3cret") }, http.StatusUnauthorized},
+		{"bearer token", "/status/api/status", func(r *http.Request) { r.Header.Set("Authorization", "Bearer api-token") }, http.StatusOK},
+		{"admin token", "/status/api/status", func(r *http.Request) { r.Header.Set("Authorization", "Bearer admin-token") }, http.StatusOK},
+		{"wrong token", "/status/api/status", func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, http.StatusUnauthorized},
+		{"query token", "/status/api/status?access_token=api-token", nil, http.StatusOK},
+		{"public health", "/status/api/health", nil, http.StatusOK},
+		{"public badge", "/status/badge/API.svg", nil, http.StatusOK},
+		{"group badge not public", "/status/badge", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != nil {
+				tt.header(req)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusUnauthorized && !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Basic ") {
+				t.Errorf("WWW-Authenticate = %q, want Basic challenge", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestAuth_SessionCookie(t *testing.T) {
+	ms := newMockStore()
+	srv := NewServer(ms, 0, nil, "", testLogger())
+	srv.SetBasePath("/status")
+	srv.EnableAuth(Auth{Tokens: []string{"api-token"}})
+	handler, err := srv.Handler()
+	if err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	// opening the dashboard with a query token sets a session cookie, which
+	// then authenticates the SSE stream
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status/api/status?access_token=api-token", nil))
+	var session *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == sessionCookie {
+			session = c
+		}
+	}
+	if session == nil {
+		t.Fatal("no session cookie set")
+	}
+	if session.Path != "/status/" || !session.HttpOnly {
+		t.Errorf("cookie = %+v, want HttpOnly scoped to /status/", session)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/status/api/status", nil)
+	req.AddCookie(session)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("with session cookie: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/status/api/status", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookie, Value: session.Value + "x"})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("with tampered cookie: status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if got := rec.Header().Get("WWW-Authenticate"); !strings.HasPrefix(got, "Bearer ") {
+		t.Errorf("WWW-Authenticate = %q, want Bearer challenge without users", got)
+	}
+}
+
+func TestAuthenticator_Verify(t *testing.T) {
+	srv := NewServer(newMockStore(), 0, nil, "", testLogger())
+	srv.EnableAuth(Auth{Tokens: []string{"t"}})
+	a := srv.newAuthenticator()
+	now := time.Now()
+	value := a.sign("alice", now.Add(time.Hour))
+
+	if user, err := a.verify(value, now); err != nil || user != "alice" {
+		t.Errorf("verify() = %q, %v, want alice", user, err)
+	}
+	if _, err := a.verify(value, now.Add(2*time.Hour)); err == nil {
+		t.Error("verify() of expired session should fail")
+	}
+	if _, err := srv.newAuthenticator().verify(value, now); err == nil {
+		t.Error("verify() with another key should fail")
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("pw"), bcrypt.MinCost)
+
+	tests := []struct {
+		name    string
+		auth    Auth
+		wantErr bool
+	}{
+		{"valid", Auth{Users: map[string]string{"alice": string(hash)}, Tokens: []string{"t"}, Public: []string{"/api/health"}}, false},
+		{"plain password", Auth{Users: map[string]string{"alice": "pw"}}, true},
+		{"colon in username", Auth{Users: map[string]string{"a:b": string(hash)}}, true},
+		{"empty token", Auth{Tokens: []string{""}}, true},
+		{"relative public path", Auth{Tokens: []string{"t"}, Public: []string{"api/health"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateAuth(tt.auth); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+// badgeColors maps statuses to the badge's right-hand colour.
+var badgeColors = map[string]string{
+	"up":       "#4c1",
+	"degraded": "#dfb317",
+	"down":     "#e05d44",
+	"unknown":  "#9f9f9f",
+}
+
+// badgeSeverity orders statuses from best to worst when rolling up a group.
+var badgeSeverity = map[string]int{
+	"up":       0,
+	"degraded": 1,
+	"unknown":  2,
+	"down":     3,
+}
+
+// badgeTemplate renders a flat shields-style badge. Text fields must be
+// escaped before rendering.
+var badgeTemplate = template.Must(template.New("badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">` +
+	`<title>{{.Label}}: {{.Message}}</title>` +
+	`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` +
+	`<clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>` +
+	`<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="20" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/><rect width="{{.Width}}" height="20" fill="url(#s)"/></g>` +
+	`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+	`<text x="{{.LabelX}}" y="15" fill="#010101" fill-opacity=".3">{{.Label}}</text><text x="{{.LabelX}}" y="14">{{.Label}}</text>` +
+	`<text x="{{.MessageX}}" y="15" fill="#010101" fill-opacity=".3">{{.Message}}</text><text x="{{.MessageX}}" y="14">{{.Message}}</text>` +
+	`</g></svg>`))
+
+// badge is the data a badge is rendered from.
+type badge struct {
+	Label, Message, Color string
+
+	Width, LabelWidth, MessageWidth int
+	LabelX, MessageX                string
+}
+
+// SetBadgeMaxAge sets how long clients and proxies may cache badges,
+// normally the polling interval. Zero disables caching.
+func (s *Server) SetBadgeMaxAge(d time.Duration) {
+	s.badgeMaxAge = d
+}
+
+// handleBadge serves /badge/{name}.svg, the status of one endpoint.
+//
+// The optional show parameter adds "uptime" (over the window parameter,
+// defaulting to the first configured uptime window) or "latency" (the
+// median over the first latency window) to the message. The optional
+// title parameter replaces the endpoint name on the left.
+func (s *Server) handleBadge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/badge/"), ".svg")
+	if !ok || name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	title := query.Get("title")
+	if title == "" {
+		title = name
+	}
+
+	var status *store.StatusResult
+	for _, st := range s.store.GetAll() {
+		if st.Name == name {
+			status = &st
+			break
+		}
+	}
+	if status == nil {
+		s.writeBadge(w, http.StatusNotFound, title, "not found", badgeColors["unknown"])
+		return
+	}
+
+	message := status.Status
+	switch show := query.Get("show"); show {
+	case "":
+	case "uptime":
+		window, err := s.badgeWindow(query.Get("window"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		message += " · " + formatBadgeUptime(s.badgeUptime([]string{name}, window))
+	case "latency":
+		message += " · " + formatBadgeLatency(s.badgeLatency([]string{name}))
+	default:
+		http.Error(w, fmt.Sprintf("show must be uptime or latency, got %q", show), http.StatusBadRequest)
+		return
+	}
+
+	s.writeBadge(w, http.StatusOK, title, message, badgeColor(status.Status))
+}
+
+// handleGroupBadge serves /badge, the rollup of the endpoints selected by
+// repeated label parameters such as "env:prod", or of every endpoint when
+// there are none. The badge takes the colour of the worst member's status
+// and reports how many members are up.
+//
+// The show and title parameters work as for [Server.handleBadge]. Uptime is
+// averaged over the members; latency is the slowest member's median.
+func (s *Server) handleGroupBadge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	sel, err := parseLabelSelector(query["label"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	title := query.Get("title")
+	if title == "" {
+		title = strings.Join(query["label"], ", ")
+	}
+	if title == "" {
+		title = "status"
+	}
+
+	var names []string
+	worst, up := "up", 0
+	for _, st := range s.store.GetAll() {
+		if !sel.matches(st.Labels) {
+			continue
+		}
+		names = append(names, st.Name)
+		if st.Status == "up" {
+			up++
+		}
+		if badgeSeverity[st.Status] > badgeSeverity[worst] {
+			worst = st.Status
+		}
+	}
+	if len(names) == 0 {
+		s.writeBadge(w, http.StatusOK, title, "no endpoints", badgeColors["unknown"])
+		return
+	}
+
+	message := fmt.Sprintf("%d/%d up", up, len(names))
+	if up == len(names) {
+		message = "all up"
+	}
+	switch show := query.Get("show"); show {
+	case "":
+	case "uptime":
+		window, err := s.badgeWindow(query.Get("window"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		message += " · " + formatBadgeUptime(s.badgeUptime(names, window))
+	case "latency":
+		message += " · " + formatBadgeLatency(s.badgeLatency(names))
+	default:
+		http.Error(w, fmt.Sprintf("show must be uptime or latency, got %q", show), http.StatusBadRequest)
+		return
+	}
+
+	s.writeBadge(w, http.StatusOK, title, message, badgeColor(worst))
+}
+
+// badgeWindow parses a window parameter, defaulting to the first
+// configured uptime window.
+func (s *Server) badgeWindow(raw string) (time.Duration, error) {
+	if raw == "" {
+		return s.uptimeWindows[0], nil
+	}
+	return store.ParseWindow(raw)
+}
+
+// badgeUptime returns the mean uptime percentage of names over window, or
+// nil when none of them has data.
+func (s *Server) badgeUptime(names []string, window time.Duration) *float64 {
+	var sum float64
+	var n int
+	for _, name := range names {
+		uptime := s.store.Uptime(name, []time.Duration{window})
+		if len(uptime) == 0 || uptime[0].Percent == nil {
+			continue
+		}
+		sum += *uptime[0].Percent
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	mean := sum / float64(n)
+	return &mean
+}
+
+// badgeLatency returns the highest median latency of names over the first
+// latency window, or -1 when none of them has data.
+func (s *Server) badgeLatency(names []string) int64 {
+	slowest := int64(-1)
+	for _, name := range names {
+		latency := s.store.Latency(name)
+		if len(latency) == 0 || latency[0].Count == 0 {
+			continue
+		}
+		slowest = max(slowest, latency[0].P50)
+	}
+	return slowest
+}
+
+// formatBadgeUptime formats an uptime percentage, rounding down so that
+// anything short of 100% never reads as 100%.
+func formatBadgeUptime(percent *float64) string {
+	if percent == nil {
+		return "n/a"
+	}
+	return strconv.FormatFloat(math.Floor(*percent*100)/100, 'f', -1, 64) + "%"
+}
+
+// formatBadgeLatency formats a latency in milliseconds.
+func formatBadgeLatency(ms int64) string {
+	if ms < 0 {
+		return "n/a"
+	}
+	return strconv.FormatInt(ms, 10) + "ms"
+}
+
+// badgeColor returns the colour for status, grey for anything unrecognised.
+func badgeColor(status string) string {
+	if c, ok := badgeColors[status]; ok {
+		return c
+	}
+	return badgeColors["unknown"]
+}
+
+// writeBadge renders a badge and writes it with caching headers that
+// follow the badge max age.
+func (s *Server) writeBadge(w http.ResponseWriter, code int, label, message, color string) {
+	b := badge{
+		Label:        html.EscapeString(label),
+		Message:      html.EscapeString(message),
+		Color:        color,
+		LabelWidth:   badgeTextWidth(label) + 10,
+		MessageWidth: badgeTextWidth(message) + 10,
+	}
+	b.Width = b.LabelWidth + b.MessageWidth
+	b.LabelX = strconv.FormatFloat(float64(b.LabelWidth)/2, 'f', 1, 64)
+	b.MessageX = strconv.FormatFloat(float64(b.LabelWidth)+float64(b.MessageWidth)/2, 'f', 1, 64)
+
+	var buf bytes.Buffer
+	if err := badgeTemplate.Execute(&buf, b); err != nil {
+		s.logger.Error("failed to render badge", "error", err)
+		http.Error(w, "failed to render badge", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	if s.badgeMaxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", max(1, int(s.badgeMaxAge.Seconds()))))
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.WriteHeader(code)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		s.logger.Error("failed to write badge response", "error", err)
+	}
+}
+
+// badgeTextWidth approximates the width in pixels of text set in 11px
+// Verdana, the badge font. Badges are sized without measuring the font, so
+// the estimate errs wide.
+func badgeTextWidth(text string) int {
+	var width float64
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("il.,:;'!|", r):
+			width += 3.1
+		case strings.ContainsRune("fjt()[] I/", r):
+			width += 4.3
+		case strings.ContainsRune("rJ-", r):
+			width += 4.9
+		case strings.ContainsRune("mw%MW", r):
+			width += 10.4
+		case r >= 'A' && r <= 'Z':
+			width += 7.6
+		case r < 0x80:
+			width += 7.0
+		default:
+			width += 9.0
+		}
+	}
+	return int(math.Ceil(width))
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+func TestHandleBadge(t *testing.T) {
+	now := time.Now()
+	errMsg := "timeout"
+	ms := store.NewMemoryStore()
+	ms.Update(store.StatusResult{Name: "API", Status: "up", ResponseTimeMs: 120, CheckedAt: now.Add(-time.Minute), Labels: map[string]string{"env": "prod"}})
+	ms.Update(store.StatusResult{Name: "API", Status: "up", ResponseTimeMs: 120, CheckedAt: now, Labels: map[string]string{"env": "prod"}})
+	ms.Update(store.StatusResult{Name: "DB", Status: "down", Error: &errMsg, NoResponse: true, CheckedAt: now, Labels: map[string]string{"env": "prod"}})
+	ms.Update(store.StatusResult{Name: "Staging <API>", Status: "degraded", CheckedAt: now, Labels: map[string]string{"env": "staging"}})
+
+	tests := []struct {
+		name        string
+		url         string
+		wantCode    int
+		wantColor   string
+		wantMessage string
+		wantLabel   string
+	}{
+		{"endpoint up", "/badge/API.svg", http.StatusOK, badgeColors["up"], "up", "API"},
+		{"escaped name", "/badge/Staging%20%3CAPI%3E.svg", http.StatusOK, badgeColors["degraded"], "degraded", "Staging <API>"},
+		{"custom title", "/badge/API.svg?title=Public%20API", http.StatusOK, badgeColors["up"], "up", "Public API"},
+		{"uptime", "/badge/API.svg?show=uptime&window=1h", http.StatusOK, badgeColors["up"], "up · 100%", "API"},
+		{"latency", "/badge/API.svg?show=latency", http.StatusOK, badgeColors["up"], "up · 120ms", "API"},
+		{"no latency data", "/badge/DB.svg?show=latency", http.StatusOK, badgeColors["down"], "down · n/a", "DB"},
+		{"unknown endpoint", "/badge/Missing.svg", http.StatusNotFound, badgeColors["unknown"], "not found", "Missing"},
+		{"group", "/badge?label=env:prod", http.StatusOK, badgeColors["down"], "1/2 up", "env:prod"},
+		{"group custom title", "/badge?label=env:staging&title=Staging", http.StatusOK, badgeColors["degraded"], "0/1 up", "Staging"},
+		{"group every endpoint", "/badge", http.StatusOK, badgeColors["down"], "1/3 up", "status"},
+		{"group no members", "/badge?label=env:dev", http.StatusOK, badgeColors["unknown"], "no endpoints", "env:dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(ms, 0, nil, "", testLogger())
+			srv.SetBadgeMaxAge(15 * time.Second)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if strings.HasPrefix(req.URL.Path, "/badge/") {
+				srv.handleBadge(rec, req)
+			} else {
+				srv.handleGroupBadge(rec, req)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "image/svg+xml" {
+				t.Errorf("Content-Type = %q, want image/svg+xml", ct)
+			}
+			if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=15" {
+				t.Errorf("Cache-Control = %q, want public, max-age=15", cc)
+			}
+
+			var svg struct {
+				Title string `xml:"title"`
+			}
+			if err := xml.Unmarshal(rec.Body.Bytes(), &svg); err != nil {
+				t.Fatalf("invalid SVG: %v\n%s", err, rec.Body.String())
+			}
+			if want := tt.wantLabel + ": " + tt.wantMessage; svg.Title != want {
+				t.Errorf("title = %q, want %q", svg.Title, want)
+			}
+			if !strings.Contains(rec.Body.String(), `fill="`+tt.wantColor+`"`) {
+				t.Errorf("badge missing colour %s", tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestHandleBadge_BadRequest(t *testing.T) {
+	ms := newMockStore()
+	ms.Update(store.StatusResult{Name: "API", Status: "up"})
+	srv := NewServer(ms, 0, nil, "", testLogger())
+
+	for _, url := range []string{
+		"/badge/API.svg?show=errors",
+		"/badge/API.svg?show=uptime&window=-1h",
+		"/badge?label=env",
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if strings.HasPrefix(req.URL.Path, "/badge/") {
+			srv.handleBadge(rec, req)
+		} else {
+			srv.handleGroupBadge(rec, req)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", url, rec.Code, http.StatusBadRequest)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	srv.handleBadge(rec, httptest.NewRequest(http.MethodGet, "/badge/API.png", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("non-svg badge: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.handleBadge(rec, httptest.NewRequest(http.MethodGet, "/badge/API.svg", nil))
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Cache-Control without max age = %q, want no-cache", cc)
+	}
+}
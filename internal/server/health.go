@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+// DefaultHealthPath is where the health rollup is served by default.
+const DefaultHealthPath = "/api/health"
+
+// builtinPaths are served by every Server, so the health rollup cannot use
+// them.
+var builtinPaths = []string{
+	"/", "/api/status", "/api/sse", "/api/history", "/api/uptime",
+	"/api/latency", "/api/incidents", "/api/endpoints", "/metrics", "/badge",
+	"/auth/login", "/auth/callback", "/auth/logout",
+}
+
+// HealthRule selects a group of endpoints that must be up for the health
+// rollup to pass.
+//
+// Members are the endpoints matching every pair in Labels, plus those named
+// in Endpoints. Named endpoints that have not reported yet count as failing.
+type HealthRule struct {
+	// Name identifies the rule in the response. Defaults to a description
+	// of the selection.
+	Name string
+
+	// Labels selects endpoints by label.
+	Labels map[string]string
+
+	// Endpoints selects endpoints by name.
+	Endpoints []string
+
+	// MinUp is how many members must be up. Zero means all of them.
+	MinUp int
+
+	// AllowDegraded counts degraded members as up.
+	AllowDegraded bool
+}
+
+// ValidateHealthPath reports whether path can serve the health rollup.
+func ValidateHealthPath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("health path must start with /, got %q", path)
+	}
+	if slices.Contains(builtinPaths, path) {
+		return fmt.Errorf("health path %q is already served", path)
+	}
+	return nil
+}
+
+// EnableHealth serves the health rollup at path, evaluating rules. With no
+// rules every endpoint must be up. An empty path keeps [DefaultHealthPath].
+func (s *Server) EnableHealth(path string, rules []HealthRule) {
+	if path != "" {
+		s.healthPath = path
+	}
+	s.healthRules = rules
+}
+
+// healthMember is an endpoint that fails a health rule.
+type healthMember struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// healthRuleResult is a rule's outcome in the health response.
+type healthRuleResult struct {
+	Name    string         `json:"name"`
+	Healthy bool           `json:"healthy"`
+	Up      int            `json:"up"`
+	Total   int            `json:"total"`
+	MinUp   int            `json:"min_up"`
+	Failing []healthMember `json:"failing"`
+}
+
+// healthResponse is the JSON body returned by the health rollup.
+type healthResponse struct {
+	Status string             `json:"status"`
+	Rules  []healthRuleResult `json:"rules"`
+}
+
+// handleHealth returns 200 when every health rule passes and 503 otherwise,
+// listing each rule's failing members.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rules := s.healthRules
+	if len(rules) == 0 {
+		rules = []HealthRule{{Name: "all endpoints"}}
+	}
+
+	statuses := s.store.GetAll()
+	resp := healthResponse{Status: "pass", Rules: make([]healthRuleResult, len(rules))}
+	for i, rule := range rules {
+		resp.Rules[i] = evaluateHealthRule(rule, statuses)
+		if !resp.Rules[i].Healthy {
+			resp.Status = "fail"
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	if resp.Status != "pass" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("failed to encode health response", "error", err)
+	}
+}
+
+// evaluateHealthRule checks rule against the current statuses. A rule that
+// selects no endpoints fails.
+func evaluateHealthRule(rule HealthRule, statuses []store.StatusResult) healthRuleResult {
+	members := make(map[string]string) // name -> status
+	selectAll := len(rule.Labels) == 0 && len(rule.Endpoints) == 0
+	for _, st := range statuses {
+		if selectAll || (len(rule.Labels) > 0 && labelSelector(rule.Labels).matches(st.Labels)) {
+			members[st.Name] = st.Status
+		}
+	}
+	for _, name := range rule.Endpoints {
+		if _, ok := members[name]; ok {
+			continue
+		}
+		members[name] = "unknown"
+		for _, st := range statuses {
+			if st.Name == name {
+				members[name] = st.Status
+				break
+			}
+		}
+	}
+
+	res := healthRuleResult{Name: rule.Name, Total: len(members), MinUp: rule.MinUp, Failing: []healthMember{}}
+	if res.Name == "" {
+		res.Name = describeHealthRule(rule)
+	}
+	if res.MinUp == 0 {
+		res.MinUp = len(members)
+	}
+	for name, status := range members {
+		if status == "up" || (rule.AllowDegraded && status == "degraded") {
+			res.Up++
+		} else {
+			res.Failing = append(res.Failing, healthMember{Name: name, Status: status})
+		}
+	}
+	sort.Slice(res.Failing, func(i, j int) bool { return res.Failing[i].Name < res.Failing[j].Name })
+
+	res.Healthy = res.Total > 0 && res.Up >= res.MinUp
+	return res
+}
+
+// describeHealthRule names a rule by its selection, e.g.
+// "critical=true, endpoints API,DB".
+func describeHealthRule(rule HealthRule) string {
+	var parts []string
+	keys := make([]string, 0, len(rule.Labels))
+	for k := range rule.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+"="+rule.Labels[k])
+	}
+	if len(rule.Endpoints) > 0 {
+		parts = append(parts, "endpoints "+strings.Join(rule.Endpoints, ","))
+	}
+	if len(parts) == 0 {
+		return "all endpoints"
+	}
+	return strings.Join(parts, ", ")
+}
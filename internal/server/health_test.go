@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+func TestHandleHealth(t *testing.T) {
+	ms := newMockStore()
+	ms.Update(store.StatusResult{Name: "API", Status: "up", Labels: map[string]string{"critical": "true"}})
+	ms.Update(store.StatusResult{Name: "DB", Status: "down", Labels: map[string]string{"critical": "true"}})
+	ms.Update(store.StatusResult{Name: "Replica 1", Status: "up", Labels: map[string]string{"service": "cache"}})
+	ms.Update(store.StatusResult{Name: "Replica 2", Status: "degraded", Labels: map[string]string{"service": "cache"}})
+	ms.Update(store.StatusResult{Name: "Replica 3", Status: "down", Labels: map[string]string{"service": "cache"}})
+
+	tests := []struct {
+		name        string
+		rules       []HealthRule
+		wantCode    int
+		wantFailing []string
+	}{
+		{"all endpoints", nil, http.StatusServiceUnavailable, []string{"DB", "Replica 2", "Replica 3"}},
+		{"critical all up", []HealthRule{{Labels: map[string]string{"critical": "true"}}}, http.StatusServiceUnavailable, []string{"DB"}},
+		{"1 of 3 replicas", []HealthRule{{Labels: map[string]string{"service": "cache"}, MinUp: 1}}, http.StatusOK, []string{"Replica 2", "Replica 3"}},
+		{"2 of 3 replicas", []HealthRule{{Labels: map[string]string{"service": "cache"}, MinUp: 2}}, http.StatusServiceUnavailable, []string{"Replica 2", "Replica 3"}},
+		{"2 of 3 allowing degraded", []HealthRule{{Labels: map[string]string{"service": "cache"}, MinUp: 2, AllowDegraded: true}}, http.StatusOK, []string{"Replica 3"}},
+		{"named endpoints", []HealthRule{{Endpoints: []string{"API", "Missing"}}}, http.StatusServiceUnavailable, []string{"Missing"}},
+		{"no members", []HealthRule{{Labels: map[string]string{"env": "none"}}}, http.StatusServiceUnavailable, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(ms, 0, nil, "", testLogger())
+			srv.EnableHealth("", tt.rules)
+
+			rec := httptest.NewRecorder()
+			srv.handleHealth(rec, httptest.NewRequest(http.MethodGet, DefaultHealthPath, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			var resp healthResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			var failing []string
+			for _, m := range resp.Rules[0].Failing {
+				failing = append(failing, m.Name)
+			}
+			if len(failing) != len(tt.wantFailing) {
+				t.Fatalf("failing = %v, want %v", failing, tt.wantFailing)
+			}
+			for i := range failing {
+				if failing[i] != tt.wantFailing[i] {
+					t.Errorf("failing = %v, want %v", failing, tt.wantFailing)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateHealthPath(t *testing.T) {
+	for path, wantErr := range map[string]bool{
+		"/api/health": false,
+		"/healthz":    false,
+		"healthz":     true,
+		"":            true,
+		"/":           true,
+		"/api/status": true,
+	} {
+		if err := ValidateHealthPath(path); (err != nil) != wantErr {
+			t.Errorf("ValidateHealthPath(%q) error = %v, wantErr %v", path, err, wantErr)
+		}
+	}
+}
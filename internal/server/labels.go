@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+)
+
+// labelSelector matches endpoints whose labels include every key-value pair.
+// An empty selector matches everything.
+type labelSelector map[string]string
+
+// parseLabelSelector parses label query parameters of the form "key:value"
+// or "key=value". Repeated parameters must all match.
+func parseLabelSelector(raw []string) (labelSelector, error) {
+	sel := make(labelSelector, len(raw))
+	for _, r := range raw {
+		key, value, ok := strings.Cut(r, ":")
+		if !ok {
+			key, value, ok = strings.Cut(r, "=")
+		}
+		if !ok || key == "" {
+			return nil, fmt.Errorf("label selector must be key:value, got %q", r)
+		}
+		sel[key] = value
+	}
+	return sel, nil
+}
+
+// matches reports whether labels satisfy the selector.
+func (sel labelSelector) matches(labels map[string]string) bool {
+	for k, v := range sel {
+		if got, ok := labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
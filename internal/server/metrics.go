@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bufio"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+// metricsContentType is the Prometheus text exposition format.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricStates are the states reported by pulseboard_endpoint_status.
+var metricStates = []string{"up", "degraded", "down", "unknown"}
+
+// PollStats reports the state of the poll queue for /metrics.
+type PollStats interface {
+	// QueueDepth returns the number of due polls waiting for a worker.
+	QueueDepth() int
+
+	// InFlight returns the number of polls currently running.
+	InFlight() int
+}
+
+// SetPollStats adds poll queue metrics to /metrics. A nil ps omits them.
+func (s *Server) SetPollStats(ps PollStats) {
+	s.pollStats = ps
+}
+
+// handleMetrics serves metrics in the Prometheus text format.
+//
+// Each endpoint's series carry an endpoint label plus its own labels, with
+// names sanitised to valid label names; labels that would clash with the
+// labels the metric itself uses are dropped.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	statuses := s.store.GetAll()
+	slices.SortFunc(statuses, func(a, b store.StatusResult) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	counters := s.store.Counters()
+
+	w.Header().Set("Content-Type", metricsContentType)
+	mw := &metricWriter{w: bufio.NewWriter(w)}
+
+	mw.family("pulseboard_endpoint_status", "gauge", "Whether the endpoint is in the given state (1) or not (0).")
+	for _, st := range statuses {
+		for _, state := range metricStates {
+			mw.sample("pulseboard_endpoint_status", endpointLabels(st, "state", state), boolValue(st.Status == state))
+		}
+	}
+
+	mw.family("pulseboard_endpoint_last_check_timestamp_seconds", "gauge", "Unix time of the endpoint's last check.")
+	for _, st := range statuses {
+		mw.sample("pulseboard_endpoint_last_check_timestamp_seconds", endpointLabels(st), float64(st.CheckedAt.UnixMilli())/1000)
+	}
+
+	mw.family("pulseboard_endpoint_response_time_seconds", "gauge", "Latency of the endpoint's last check.")
+	for _, st := range statuses {
+		mw.sample("pulseboard_endpoint_response_time_seconds", endpointLabels(st), float64(st.ResponseTimeMs)/1000)
+	}
+
+	mw.family("pulseboard_endpoint_checks_total", "counter", "Checks of the endpoint by published status.")
+	for _, st := range statuses {
+		c := counters[st.Name]
+		for _, status := range metricStates {
+			mw.sample("pulseboard_endpoint_checks_total", endpointLabels(st, "status", status), float64(c.Checks[status]))
+		}
+	}
+
+	mw.family("pulseboard_endpoint_latency_seconds", "histogram", "Latency of the endpoint's checks that got a response.")
+	for _, st := range statuses {
+		c, ok := counters[st.Name]
+		if !ok {
+			continue
+		}
+		var cumulative uint64
+		for i, n := range c.LatencyBuckets {
+			cumulative += n
+			le := math.Inf(1)
+			if i < len(store.LatencyBucketBounds) {
+				le = store.LatencyBucketBounds[i]
+			}
+			mw.sample("pulseboard_endpoint_latency_seconds_bucket", endpointLabels(st, "le", formatValue(le)), float64(cumulative))
+		}
+		mw.sample("pulseboard_endpoint_latency_seconds_sum", endpointLabels(st), c.LatencySum)
+		mw.sample("pulseboard_endpoint_latency_seconds_count", endpointLabels(st), float64(c.LatencyCount))
+	}
+
+	if s.pollStats != nil {
+		mw.family("pulseboard_poll_queue_depth", "gauge", "Due polls waiting for a free worker.")
+		mw.sample("pulseboard_poll_queue_depth", nil, float64(s.pollStats.QueueDepth()))
+		mw.family("pulseboard_polls_in_flight", "gauge", "Polls currently running.")
+		mw.sample("pulseboard_polls_in_flight", nil, float64(s.pollStats.InFlight()))
+	}
+
+	mw.family("pulseboard_sse_subscribers", "gauge", "Connected Server-Sent Events clients.")
+	mw.sample("pulseboard_sse_subscribers", nil, float64(s.sseClients.Load()))
+
+	mw.family("pulseboard_sse_dropped_messages_total", "counter", "Status updates dropped because an SSE client was too slow.")
+	mw.sample("pulseboard_sse_dropped_messages_total", nil, float64(s.store.DroppedUpdates()))
+
+	if err := mw.w.Flush(); err != nil {
+		s.logger.Debug("failed to write metrics", "error", err)
+	}
+}
+
+// label is a metric label name and value.
+type label struct {
+	name, value string
+}
+
+// endpointLabels returns the endpoint label, the endpoint's own labels
+// sorted by name, and then extra, given as name-value pairs. Endpoint labels
+// that clash with endpoint or an extra label are dropped.
+func endpointLabels(st store.StatusResult, extra ...string) []label {
+	reserved := map[string]bool{"endpoint": true}
+	for i := 0; i < len(extra); i += 2 {
+		reserved[extra[i]] = true
+	}
+
+	labels := []label{{"endpoint", st.Name}}
+	keys := make([]string, 0, len(st.Labels))
+	for k := range st.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name := sanitizeLabelName(k)
+		if reserved[name] {
+			continue
+		}
+		reserved[name] = true
+		labels = append(labels, label{name, st.Labels[k]})
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		labels = append(labels, label{extra[i], extra[i+1]})
+	}
+	return labels
+}
+
+// sanitizeLabelName maps name to a valid Prometheus label name by replacing
+// invalid characters with underscores. Leading double underscores, which
+// Prometheus reserves for internal use, are collapsed into one.
+func sanitizeLabelName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		valid := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')
+		if valid {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	sanitized := b.String()
+	if sanitized == "" || strings.HasPrefix(sanitized, "__") {
+		sanitized = "_" + strings.TrimLeft(sanitized, "_")
+	}
+	return sanitized
+}
+
+// metricWriter writes the Prometheus text exposition format.
+type metricWriter struct {
+	w *bufio.Writer
+}
+
+// family writes the HELP and TYPE lines of a metric family.
+func (mw *metricWriter) family(name, typ, help string) {
+	mw.w.WriteString("# HELP " + name + " " + help + "\n")
+	mw.w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// sample writes one sample line.
+func (mw *metricWriter) sample(name string, labels []label, value float64) {
+	mw.w.WriteString(name)
+	if len(labels) > 0 {
+		mw.w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				mw.w.WriteByte(',')
+			}
+			mw.w.WriteString(l.name)
+			mw.w.WriteString(`="`)
+			mw.w.WriteString(labelValueEscaper.Replace(l.value))
+			mw.w.WriteByte('"')
+		}
+		mw.w.WriteByte('}')
+	}
+	mw.w.WriteByte(' ')
+	mw.w.WriteString(formatValue(value))
+	mw.w.WriteByte('\n')
+}
+
+// labelValueEscaper escapes label values as the text format requires.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatValue formats a sample value or bucket bound.
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+// boolValue returns 1 for true and 0 for false.
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
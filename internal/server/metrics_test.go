@@ -0,0 +1,77 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+type fakePollStats struct{ queued, inFlight int }
+
+func (f fakePollStats) QueueDepth() int { return f.queued }
+func (f fakePollStats) InFlight() int   { return f.inFlight }
+
+func TestHandleMetrics(t *testing.T) {
+	ms := newMockStore()
+	ms.Update(store.StatusResult{
+		Name:           `Say "hi"`,
+		Status:         "degraded",
+		Labels:         map[string]string{"env": "prod", "team-name": "core", "state": "clash"},
+		ResponseTimeMs: 20000,
+		CheckedAt:      time.Unix(1767268800, 500e6),
+	})
+	srv := NewServer(ms, 0, nil, "", testLogger())
+	srv.SetPollStats(fakePollStats{queued: 3, inFlight: 2})
+
+	rec := httptest.NewRecorder()
+	srv.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != metricsContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, metricsContentType)
+	}
+
+	body := rec.Body.String()
+	const ep = `endpoint="Say \"hi\"",env="prod",`
+	for _, line := range []string{
+		"# TYPE pulseboard_endpoint_status gauge",
+		`pulseboard_endpoint_status{` + ep + `team_name="core",state="degraded"} 1`,
+		`pulseboard_endpoint_status{` + ep + `team_name="core",state="up"} 0`,
+		`pulseboard_endpoint_last_check_timestamp_seconds{` + ep + `state="clash",team_name="core"} 1767268800.5`,
+		`pulseboard_endpoint_response_time_seconds{` + ep + `state="clash",team_name="core"} 20`,
+		`pulseboard_endpoint_checks_total{` + ep + `state="clash",team_name="core",status="degraded"} 1`,
+		"# TYPE pulseboard_endpoint_latency_seconds histogram",
+		`pulseboard_endpoint_latency_seconds_bucket{` + ep + `state="clash",team_name="core",le="10"} 0`,
+		`pulseboard_endpoint_latency_seconds_bucket{` + ep + `state="clash",team_name="core",le="+Inf"} 1`,
+		`pulseboard_endpoint_latency_seconds_count{` + ep + `state="clash",team_name="core"} 1`,
+		"pulseboard_poll_queue_depth 3",
+		"pulseboard_polls_in_flight 2",
+		"pulseboard_sse_subscribers 0",
+		"pulseboard_sse_dropped_messages_total 7",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics missing line %q\n%s", line, body)
+		}
+	}
+}
+
+func TestSanitizeLabelName(t *testing.T) {
+	for in, want := range map[string]string{
+		"env":       "env",
+		"team-name": "team_name",
+		"9lives":    "_lives",
+		"a.b/c":     "a_b_c",
+		"":          "_",
+		"__name__":  "_name__",
+		"--meta":    "_meta",
+		"__":        "_",
+		"_private":  "_private",
+	} {
+		if got := sanitizeLabelName(in); got != want {
+			t.Errorf("sanitizeLabelName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
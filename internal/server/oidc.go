@@ -0,0 +1,806 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oidcSubjectPrefix marks session cookies that refer to an OIDC session
+	// held by the server, rather than naming a user directly.
+	oidcSubjectPrefix = "oidc:"
+
+	// flowCookie carries the state, nonce and PKCE verifier of a login in
+	// progress.
+	flowCookie = "pulseboard_oidc"
+
+	// flowTTL is how long a user has to complete a login at the provider.
+	flowTTL = 10 * time.Minute
+
+	// refreshSkew is how long before tokens expire they are refreshed.
+	refreshSkew = 30 * time.Second
+
+	// defaultMinRefreshWait stops a provider issuing very short-lived tokens
+	// from turning the SSE refresh loop into a busy loop.
+	defaultMinRefreshWait = 5 * time.Second
+
+	// defaultTokenLifetime applies when the provider states no expiry.
+	defaultTokenLifetime = time.Hour
+
+	// jwksMinRefresh limits how often unknown key IDs refetch the keys.
+	jwksMinRefresh = time.Minute
+
+	// clockLeeway tolerates clock skew with the provider.
+	clockLeeway = time.Minute
+
+	// oidcTimeout bounds requests to the provider.
+	oidcTimeout = 10 * time.Second
+
+	// maxOIDCResponseBytes caps responses read from the provider.
+	maxOIDCResponseBytes = 1 << 20
+
+	// DefaultGroupsClaim is the ID token claim listing a user's groups.
+	DefaultGroupsClaim = "groups"
+)
+
+// OIDC configures single sign-on with an OpenID Connect provider, such as
+// Google Workspace or Okta, using the authorization code flow with PKCE.
+//
+// Browsers without a session are redirected to the provider and back to
+// RedirectURL, which must be the server's /auth/callback route and be
+// registered with the provider. /auth/logout ends the session.
+type OIDC struct {
+	// Issuer is the provider's issuer URL, from which its endpoints are
+	// discovered.
+	Issuer string
+
+	// ClientID and ClientSecret identify PulseBoard to the provider.
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the absolute URL of /auth/callback.
+	RedirectURL string
+
+	// Scopes are requested in addition to "openid". Defaults to "email"
+	// and "profile"; add "offline_access" where the provider needs it to
+	// issue refresh tokens.
+	Scopes []string
+
+	// AllowedDomains, if set, admits only users whose verified email is in
+	// one of these domains.
+	AllowedDomains []string
+
+	// AllowedGroups, if set, admits only users whose GroupsClaim lists one
+	// of these groups. Both checks must pass when both are set.
+	AllowedGroups []string
+
+	// AllowAnyAccount admits every user the provider authenticates. It is
+	// required when neither AllowedDomains nor AllowedGroups is set.
+	AllowAnyAccount bool
+
+	// GroupsClaim names the ID token claim listing the user's groups.
+	// Defaults to [DefaultGroupsClaim].
+	GroupsClaim string
+}
+
+// ValidateOIDC reports whether the settings are complete.
+func ValidateOIDC(o OIDC) error {
+	issuer, err := url.Parse(o.Issuer)
+	if err != nil || (issuer.Scheme != "https" && issuer.Scheme != "http") || issuer.Host == "" {
+		return fmt.Errorf("oidc issuer must be an http or https URL, got %q", o.Issuer)
+	}
+	if o.ClientID == "" {
+		return errors.New("oidc client ID is required")
+	}
+	if len(o.AllowedDomains) == 0 && len(o.AllowedGroups) == 0 && !o.AllowAnyAccount {
+		return errors.New("oidc requires allowed domains or groups, or allowing any account explicitly")
+	}
+	redirect, err := url.Parse(o.RedirectURL)
+	if err != nil || !redirect.IsAbs() || !strings.HasSuffix(redirect.Path, "/auth/callback") {
+		return fmt.Errorf("oidc redirect URL must be the absolute URL of /auth/callback, got %q", o.RedirectURL)
+	}
+	return nil
+}
+
+// EnableOIDC requires browsers to log in with an OpenID Connect provider.
+// It may be combined with [Server.EnableAuth], whose credentials are
+// accepted too. Must be called before [Server.Handler].
+func (s *Server) EnableOIDC(o OIDC) {
+	s.oidcConfig = &o
+}
+
+// oidcMetadata is the subset of the provider's discovery document in use.
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// oidcTokens is a token endpoint response.
+type oidcTokens struct {
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// oidcSession is a logged-in user. Its tokens are refreshed as they expire,
+// so revoking the user at the provider ends the session.
+type oidcSession struct {
+	mu           sync.Mutex
+	user         string
+	refreshToken string
+	expiry       time.Time // of the tokens
+	created      time.Time
+}
+
+// oidcFlow is a login in progress, kept in a signed cookie.
+type oidcFlow struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Next     string `json:"next"`
+}
+
+// oidcProvider talks to an OpenID Connect provider and holds sessions.
+type oidcProvider struct {
+	cfg    OIDC
+	client *http.Client
+	logger *slog.Logger
+
+	// minRefreshWait is the shortest wait between refreshes of an SSE
+	// stream's session
+	minRefreshWait time.Duration
+
+	// metaMu guards the discovery document and signing keys, fetched lazily
+	// so PulseBoard starts while the provider is unreachable
+	metaMu      sync.Mutex
+	meta        *oidcMetadata
+	keys        map[string]crypto.PublicKey
+	keysFetched time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*oidcSession
+}
+
+// newOIDCProvider creates a provider client, applying defaults to cfg.
+func newOIDCProvider(cfg OIDC, logger *slog.Logger) *oidcProvider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"email", "profile"}
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = DefaultGroupsClaim
+	}
+	return &oidcProvider{
+		cfg:            cfg,
+		client:         &http.Client{Timeout: oidcTimeout},
+		logger:         logger,
+		minRefreshWait: defaultMinRefreshWait,
+		sessions:       make(map[string]*oidcSession),
+	}
+}
+
+// metadata returns the provider's discovery document, fetching it once.
+func (p *oidcProvider) metadata(ctx context.Context) (*oidcMetadata, error) {
+	p.metaMu.Lock()
+	defer p.metaMu.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	var meta oidcMetadata
+	issuer := strings.TrimSuffix(p.cfg.Issuer, "/")
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimSuffix(meta.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", meta.Issuer, p.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("oidc discovery: document is missing endpoints")
+	}
+	p.meta = &meta
+	return p.meta, nil
+}
+
+// getJSON decodes the JSON response to a GET request into v.
+func (p *oidcProvider) getJSON(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxOIDCResponseBytes)).Decode(v)
+}
+
+// jsonWebKey is a public key in a JSON Web Key Set.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// key returns the signing key with the given ID, refetching the key set
+// when the ID is unknown, as happens after the provider rotates keys.
+func (p *oidcProvider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.metaMu.Lock()
+	defer p.metaMu.Unlock()
+	if k := p.lookupKey(kid); k != nil {
+		return k, nil
+	}
+	if time.Since(p.keysFetched) < jwksMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	p.keysFetched = time.Now()
+	if err := p.getJSON(ctx, meta.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("oidc keys: %w", err)
+	}
+	p.keys = make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		k, err := parseJWK(jwk)
+		if err != nil {
+			p.logger.Debug("skipping oidc signing key", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		p.keys[jwk.Kid] = k
+	}
+	if k := p.lookupKey(kid); k != nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey returns the cached key with the given ID, or the only key if
+// the token names none. Must be called with metaMu held.
+func (p *oidcProvider) lookupKey(kid string) crypto.PublicKey {
+	if k, ok := p.keys[kid]; ok {
+		return k
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k
+		}
+	}
+	return nil
+}
+
+// parseJWK converts an RSA or P-256 JSON Web Key to a public key.
+func parseJWK(jwk jsonWebKey) (crypto.PublicKey, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(jwk.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// verifyIDToken checks an ID token's RS256 or ES256 signature, issuer,
+// audience, expiry and, if nonce is set, nonce, and returns its claims.
+func (p *oidcProvider) verifyIDToken(ctx context.Context, raw, nonce string) (map[string]any, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed ID token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed ID token header: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed ID token signature")
+	}
+
+	key, err := p.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if header.Alg != "RS256" || rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) != nil {
+			return nil, errors.New("invalid ID token signature")
+		}
+	case *ecdsa.PublicKey:
+		if header.Alg != "ES256" || len(sig) != 64 {
+			return nil, errors.New("invalid ID token signature")
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return nil, errors.New("invalid ID token signature")
+		}
+	default:
+		return nil, errors.New("unsupported signing key")
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed ID token claims: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != strings.TrimSuffix(p.cfg.Issuer, "/") {
+		return nil, fmt.Errorf("ID token issuer %q is not %q", iss, p.cfg.Issuer)
+	}
+	if !audienceContains(claims["aud"], p.cfg.ClientID) {
+		return nil, errors.New("ID token is not for this client")
+	}
+	exp, _ := claims["exp"].(float64)
+	if time.Now().Add(-clockLeeway).After(time.Unix(int64(exp), 0)) {
+		return nil, errors.New("ID token expired")
+	}
+	if nonce != "" {
+		if got, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(got), []byte(nonce)) != 1 {
+			return nil, errors.New("ID token nonce mismatch")
+		}
+	}
+	return claims, nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a JWT into v.
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// audienceContains reports whether an aud claim, a string or a list of
+// strings, includes clientID.
+func audienceContains(aud any, clientID string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == clientID
+	case []any:
+		for _, v := range a {
+			if v == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// authorize checks the domain and group restrictions against an ID token's
+// claims and returns the user's email, or subject if it has none.
+func (p *oidcProvider) authorize(claims map[string]any) (string, error) {
+	email, _ := claims["email"].(string)
+	if len(p.cfg.AllowedDomains) > 0 {
+		if verified, _ := claims["email_verified"].(bool); email == "" || !verified {
+			return "", errors.New("no verified email")
+		}
+		domain := email[strings.LastIndexByte(email, '@')+1:]
+		if !slices.ContainsFunc(p.cfg.AllowedDomains, func(d string) bool { return strings.EqualFold(d, domain) }) {
+			return "", fmt.Errorf("email domain %q is not allowed", domain)
+		}
+	}
+	if len(p.cfg.AllowedGroups) > 0 {
+		groups, _ := claims[p.cfg.GroupsClaim].([]any)
+		if !slices.ContainsFunc(groups, func(g any) bool {
+			name, _ := g.(string)
+			return slices.Contains(p.cfg.AllowedGroups, name)
+		}) {
+			return "", errors.New("not in an allowed group")
+		}
+	}
+	if email != "" {
+		return email, nil
+	}
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return "", errors.New("ID token has no subject")
+	}
+	return sub, nil
+}
+
+// exchange posts a grant to the token endpoint.
+func (p *oidcProvider) exchange(ctx context.Context, form url.Values) (*oidcTokens, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc token request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOIDCResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("oidc token request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &e)
+		return nil, fmt.Errorf("oidc token request: %s: %s %s", resp.Status, e.Error, e.Description)
+	}
+
+	var tokens oidcTokens
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("oidc token response: %w", err)
+	}
+	return &tokens, nil
+}
+
+// tokenExpiry returns when tokens expire: after expires_in if given, else
+// at the ID token's expiry.
+func tokenExpiry(tokens *oidcTokens, claims map[string]any) time.Time {
+	if tokens.ExpiresIn > 0 {
+		return time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second)
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		return time.Unix(int64(exp), 0)
+	}
+	return time.Now().Add(defaultTokenLifetime)
+}
+
+// newSession stores a session for user and returns its ID.
+func (p *oidcProvider) newSession(user string, tokens *oidcTokens, claims map[string]any) string {
+	sid := randomString()
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, sess := range p.sessions {
+		if now.Sub(sess.created) > sessionTTL {
+			delete(p.sessions, id)
+		}
+	}
+	p.sessions[sid] = &oidcSession{
+		user:         user,
+		refreshToken: tokens.RefreshToken,
+		expiry:       tokenExpiry(tokens, claims),
+		created:      now,
+	}
+	return sid
+}
+
+// session returns the session with the given ID, or nil.
+func (p *oidcProvider) session(sid string) *oidcSession {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sessions[sid]
+}
+
+// removeSession ends a session.
+func (p *oidcProvider) removeSession(sid string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.sessions, sid)
+}
+
+// check reports whether a session is still valid, refreshing its tokens if
+// they are about to expire. A session that cannot be refreshed is ended.
+func (p *oidcProvider) check(ctx context.Context, sid string) error {
+	sess := p.session(sid)
+	if sess == nil {
+		return errors.New("unknown session")
+	}
+	if time.Since(sess.created) > sessionTTL {
+		p.removeSession(sid)
+		return errors.New("session expired")
+	}
+	if err := p.refresh(ctx, sess); err != nil {
+		p.removeSession(sid)
+		return err
+	}
+	return nil
+}
+
+// refresh renews a session's tokens unless they are still fresh,
+// re-checking the domain and group restrictions against a new ID token.
+func (p *oidcProvider) refresh(ctx context.Context, sess *oidcSession) error {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if time.Until(sess.expiry) > refreshSkew {
+		return nil
+	}
+	if sess.refreshToken == "" {
+		return errors.New("tokens expired and no refresh token was issued")
+	}
+
+	tokens, err := p.exchange(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {sess.refreshToken},
+	})
+	if err != nil {
+		return err
+	}
+	var claims map[string]any
+	if tokens.IDToken != "" {
+		claims, err = p.verifyIDToken(ctx, tokens.IDToken, "")
+		if err != nil {
+			return err
+		}
+		if _, err := p.authorize(claims); err != nil {
+			return err
+		}
+	}
+	if tokens.RefreshToken != "" {
+		sess.refreshToken = tokens.RefreshToken
+	}
+	sess.expiry = tokenExpiry(tokens, claims)
+	p.logger.Debug("oidc tokens refreshed", "user", sess.user, "expiry", sess.expiry)
+	return nil
+}
+
+// watch keeps a long-lived request's session refreshed, calling cancel
+// once it can no longer be, until ctx is done.
+func (p *oidcProvider) watch(ctx context.Context, sid string, cancel context.CancelFunc) {
+	for {
+		sess := p.session(sid)
+		if sess == nil {
+			cancel()
+			return
+		}
+		sess.mu.Lock()
+		wait := max(time.Until(sess.expiry)-refreshSkew, p.minRefreshWait)
+		sess.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if err := p.check(ctx, sid); err != nil {
+			p.logger.Info("oidc session ended, closing stream", "error", err)
+			cancel()
+			return
+		}
+	}
+}
+
+// serveOIDC serves a request for an OIDC session, keeping the session
+// refreshed for as long as an SSE stream stays open.
+func (a *authenticator) serveOIDC(w http.ResponseWriter, r *http.Request, next http.Handler, sid string) {
+	if r.URL.Path == "/api/sse" {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go a.oidc.watch(ctx, sid, cancel)
+		r = r.WithContext(ctx)
+	}
+	next.ServeHTTP(w, r)
+}
+
+// handleLogin redirects to the provider to log in, remembering where to
+// return to from the next parameter.
+func (a *authenticator) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	meta, err := a.oidc.metadata(r.Context())
+	if err != nil {
+		a.logger.Error("oidc login failed", "error", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	flow := oidcFlow{
+		State:    randomString(),
+		Nonce:    randomString(),
+		Verifier: randomString(),
+		Next:     safeNext(r.URL.Query().Get("next")),
+	}
+	data, err := json.Marshal(flow)
+	if err != nil {
+		http.Error(w, "Login failed", http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(w, r, flowCookie, a.sign(string(data), time.Now().Add(flowTTL)), a.basePath+"/auth/", flowTTL)
+
+	challenge := sha256.Sum256([]byte(flow.Verifier))
+	u, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", a.oidc.cfg.ClientID)
+	q.Set("redirect_uri", a.oidc.cfg.RedirectURL)
+	q.Set("scope", strings.Join(append([]string{"openid"}, a.oidc.cfg.Scopes...), " "))
+	q.Set("state", flow.State)
+	q.Set("nonce", flow.Nonce)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// handleCallback completes a login: it exchanges the authorization code
+// for tokens, verifies the ID token, checks the user is allowed and starts
+// a session.
+func (a *authenticator) handleCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var flow oidcFlow
+	c, err := r.Cookie(flowCookie)
+	if err == nil {
+		var data string
+		if data, err = a.verify(c.Value, time.Now()); err == nil {
+			err = json.Unmarshal([]byte(data), &flow)
+		}
+	}
+	if err != nil {
+		http.Error(w, "Login expired; reload the dashboard to log in again", http.StatusBadRequest)
+		return
+	}
+	a.setCookie(w, r, flowCookie, "", a.basePath+"/auth/", 0)
+
+	query := r.URL.Query()
+	if e := query.Get("error"); e != "" {
+		a.logger.Warn("oidc login rejected by provider", "error", e, "description", query.Get("error_description"))
+		http.Error(w, "Login failed: "+e, http.StatusForbidden)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(flow.State)) != 1 {
+		http.Error(w, "Invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := a.oidc.exchange(r.Context(), url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {query.Get("code")},
+		"redirect_uri":  {a.oidc.cfg.RedirectURL},
+		"code_verifier": {flow.Verifier},
+	})
+	if err != nil {
+		a.logger.Error("oidc login failed", "error", err)
+		http.Error(w, "Login failed", http.StatusBadGateway)
+		return
+	}
+	if tokens.IDToken == "" {
+		a.logger.Error("oidc login failed", "error", "no ID token issued")
+		http.Error(w, "Login failed", http.StatusBadGateway)
+		return
+	}
+	claims, err := a.oidc.verifyIDToken(r.Context(), tokens.IDToken, flow.Nonce)
+	if err != nil {
+		a.logger.Warn("oidc login failed", "error", err)
+		http.Error(w, "Login failed", http.StatusUnauthorized)
+		return
+	}
+	user, err := a.oidc.authorize(claims)
+	if err != nil {
+		a.logger.Warn("oidc login denied", "email", claims["email"], "error", err)
+		http.Error(w, "You are not allowed to view this dashboard", http.StatusForbidden)
+		return
+	}
+
+	sid := a.oidc.newSession(user, tokens, claims)
+	a.setSession(w, r, oidcSubjectPrefix+sid)
+	a.logger.Info("oidc login", "user", user)
+	http.Redirect(w, r, a.basePath+flow.Next, http.StatusFound)
+}
+
+// handleLogout ends the session and, if the provider supports it, its
+// session at the provider too.
+func (a *authenticator) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if subject, ok := a.session(r); ok {
+		if sid, isOIDC := strings.CutPrefix(subject, oidcSubjectPrefix); isOIDC {
+			a.oidc.removeSession(sid)
+		}
+	}
+	a.setCookie(w, r, sessionCookie, "", a.basePath+"/", 0)
+
+	if meta, err := a.oidc.metadata(r.Context()); err == nil && meta.EndSessionEndpoint != "" {
+		if u, err := url.Parse(meta.EndSessionEndpoint); err == nil {
+			q := u.Query()
+			q.Set("client_id", a.oidc.cfg.ClientID)
+			u.RawQuery = q.Encode()
+			http.Redirect(w, r, u.String(), http.StatusFound)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html><title>Logged out</title><p>You have been logged out. <a href="%s/">Log in again</a></p>`, html.EscapeString(a.basePath))
+}
+
+// safeNext returns next if it is a path on this server, else "/", so the
+// login flow cannot redirect elsewhere.
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
+
+// randomString returns 32 random bytes, base64url encoded.
+func randomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate random value: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,400 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+// testIdP is a stand-in OpenID Connect provider. It logs in every request
+// to /authorize immediately as the configured user.
+type testIdP struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu        sync.Mutex
+	email     string
+	groups    []string
+	verified  any // email_verified claim; nil omits it
+	expiresIn int
+	codes     map[string]idpGrant // authorization code -> grant
+	refresh   map[string]bool     // refresh token -> valid
+	refreshed int
+}
+
+// idpGrant is what an authorization code was issued for.
+type idpGrant struct {
+	nonce, challenge, redirectURI string
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &testIdP{
+		t:         t,
+		key:       key,
+		email:     "alice@example.com",
+		verified:  true,
+		expiresIn: 3600,
+		codes:     make(map[string]idpGrant),
+		refresh:   make(map[string]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/authorize", idp.handleAuthorize)
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *testIdP) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != "pulseboard" || q.Get("code_challenge_method") != "S256" || !strings.Contains(q.Get("scope"), "openid") {
+		http.Error(w, "bad authorization request", http.StatusBadRequest)
+		return
+	}
+	code := randomString()
+	idp.mu.Lock()
+	idp.codes[code] = idpGrant{nonce: q.Get("nonce"), challenge: q.Get("code_challenge"), redirectURI: q.Get("redirect_uri")}
+	idp.mu.Unlock()
+	http.Redirect(w, r, q.Get("redirect_uri")+"?code="+code+"&state="+url.QueryEscape(q.Get("state")), http.StatusFound)
+}
+
+func (idp *testIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if id, secret, _ := r.BasicAuth(); id != "pulseboard" || secret != "shh" {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	var nonce string
+	switch r.FormValue("grant_type") {
+	case "authorization_code":
+		grant, ok := idp.codes[r.FormValue("code")]
+		delete(idp.codes, r.FormValue("code"))
+		challenge := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if !ok || grant.redirectURI != r.FormValue("redirect_uri") || base64.RawURLEncoding.EncodeToString(challenge[:]) != grant.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		nonce = grant.nonce
+	case "refresh_token":
+		if !idp.refresh[r.FormValue("refresh_token")] {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		delete(idp.refresh, r.FormValue("refresh_token"))
+		idp.refreshed++
+	default:
+		http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+		return
+	}
+
+	refresh := randomString()
+	idp.refresh[refresh] = true
+	json.NewEncoder(w).Encode(map[string]any{
+		"id_token":      idp.idToken(nonce),
+		"refresh_token": refresh,
+		"expires_in":    idp.expiresIn,
+	})
+}
+
+// idToken signs an ID token for the current user. Must be called with mu
+// held.
+func (idp *testIdP) idToken(nonce string) string {
+	claims := map[string]any{
+		"iss":    idp.server.URL,
+		"aud":    []string{"pulseboard"},
+		"sub":    "user-1",
+		"email":  idp.email,
+		"groups": idp.groups,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}
+	if idp.verified != nil {
+		claims["email_verified"] = idp.verified
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, digest[:])
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// revokeAll invalidates every refresh token, as when a user is removed.
+func (idp *testIdP) revokeAll() {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	clear(idp.refresh)
+}
+
+// newOIDCTestServer serves a dashboard under /status that logs in with idp.
+func newOIDCTestServer(t *testing.T, idp *testIdP, o OIDC) (*httptest.Server, *http.Client) {
+	t.Helper()
+	var handler http.Handler
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	ms := newMockStore()
+	ms.Update(store.StatusResult{Name: "API", Status: "up"})
+	srv := NewServer(ms, 0, &mockFS{content: "<h1>{{.Title}}</h1>"}, "", testLogger())
+	srv.SetBasePath("/status")
+	o.Issuer = idp.server.URL
+	o.ClientID = "pulseboard"
+	o.ClientSecret = "shh"
+	o.RedirectURL = ts.URL + "/status/auth/callback"
+	if err := ValidateOIDC(o); err != nil {
+		t.Fatalf("ValidateOIDC() error = %v", err)
+	}
+	srv.EnableOIDC(o)
+	var err error
+	if handler, err = srv.Handler(); err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	jar, _ := cookiejar.New(nil)
+	return ts, &http.Client{Jar: jar}
+}
+
+// browse fetches path as a browser would, following redirects.
+func browse(t *testing.T, client *http.Client, u string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, u, nil)
+	req.Header.Set("Accept", "text/html")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", u, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestOIDC_Login(t *testing.T) {
+	idp := newTestIdP(t)
+	ts, client := newOIDCTestServer(t, idp, OIDC{AllowedDomains: []string{"example.com"}})
+
+	// an API client without a session is refused rather than redirected
+	resp, err := client.Get(ts.URL + "/status/api/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("API without session: status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	// a browser is sent through the provider and back to the dashboard
+	code, body := browse(t, client, ts.URL+"/status/")
+	if code != http.StatusOK || !strings.Contains(body, "<h1>PulseBoard</h1>") {
+		t.Fatalf("dashboard after login = %d %q, want 200 dashboard", code, body)
+	}
+
+	resp, err = client.Get(ts.URL + "/status/api/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("API with session: status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if code, body := browse(t, client, ts.URL+"/status/auth/logout"); code != http.StatusOK || !strings.Contains(body, "logged out") {
+		t.Errorf("logout = %d %q, want logged out page", code, body)
+	}
+	resp, err = client.Get(ts.URL + "/status/api/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("API after logout: status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestOIDC_Authorization(t *testing.T) {
+	tests := []struct {
+		name     string
+		oidc     OIDC
+		email    string
+		groups   []string
+		wantCode int
+	}{
+		{"any user", OIDC{AllowAnyAccount: true}, "bob@other.com", nil, http.StatusOK},
+		{"allowed domain", OIDC{AllowedDomains: []string{"EXAMPLE.com"}}, "alice@example.com", nil, http.StatusOK},
+		{"other domain", OIDC{AllowedDomains: []string{"example.com"}}, "mallory@example.com.evil", nil, http.StatusForbidden},
+		{"allowed group", OIDC{AllowedGroups: []string{"sre"}}, "alice@example.com", []string{"dev", "sre"}, http.StatusOK},
+		{"no allowed group", OIDC{AllowedGroups: []string{"sre"}}, "alice@example.com", []string{"dev"}, http.StatusForbidden},
+		{"custom groups claim", OIDC{AllowedGroups: []string{"sre"}, GroupsClaim: "roles"}, "alice@example.com", []string{"sre"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newTestIdP(t)
+			idp.email, idp.groups = tt.email, tt.groups
+			ts, client := newOIDCTestServer(t, idp, tt.oidc)
+
+			if code, body := browse(t, client, ts.URL+"/status/"); code != tt.wantCode {
+				t.Errorf("dashboard = %d %q, want %d", code, body, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestOIDC_RequiresVerifiedEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		verified any
+	}{
+		{"unverified", false},
+		{"no claim", nil},
+		{"string claim", "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newTestIdP(t)
+			idp.verified = tt.verified
+			ts, client := newOIDCTestServer(t, idp, OIDC{AllowedDomains: []string{"example.com"}})
+
+			if code, body := browse(t, client, ts.URL+"/status/"); code != http.StatusForbidden {
+				t.Errorf("dashboard = %d %q, want %d", code, body, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestOIDC_RejectsForgedState(t *testing.T) {
+	idp := newTestIdP(t)
+	ts, client := newOIDCTestServer(t, idp, OIDC{AllowAnyAccount: true})
+
+	// start a login but answer the callback with another state
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if strings.HasPrefix(req.URL.String(), idp.server.URL) {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+	if code, _ := browse(t, client, ts.URL+"/status/"); code != http.StatusFound {
+		t.Fatalf("login = %d, want redirect to provider", code)
+	}
+	if code, _ := browse(t, client, ts.URL+"/status/auth/callback?code=x&state=forged"); code != http.StatusBadRequest {
+		t.Errorf("callback with forged state = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestOIDCProvider_Refresh(t *testing.T) {
+	idp := newTestIdP(t)
+	p := newOIDCProvider(OIDC{Issuer: idp.server.URL, ClientID: "pulseboard", ClientSecret: "shh"}, testLogger())
+	p.minRefreshWait = 10 * time.Millisecond
+
+	// tokens that expire within the refresh skew are refreshed on use
+	idp.expiresIn = 1
+	idp.mu.Lock()
+	idp.refresh["first"] = true
+	idp.mu.Unlock()
+	sid := p.newSession("alice@example.com", &oidcTokens{RefreshToken: "first", ExpiresIn: 1}, nil)
+
+	if err := p.check(context.Background(), sid); err != nil {
+		t.Fatalf("check() error = %v", err)
+	}
+	if idp.refreshed != 1 || p.session(sid).refreshToken == "first" {
+		t.Errorf("refreshed %d times with token %q, want one refresh rotating the token", idp.refreshed, p.session(sid).refreshToken)
+	}
+
+	// an open stream keeps refreshing until the provider revokes the user
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		p.watch(ctx, sid, cancel)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	idp.revokeAll()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch() did not end after revocation")
+	}
+	if ctx.Err() == nil {
+		t.Error("stream context not cancelled after revocation")
+	}
+	if p.session(sid) != nil {
+		t.Error("revoked session not removed")
+	}
+	idp.mu.Lock()
+	refreshed := idp.refreshed
+	idp.mu.Unlock()
+	if refreshed < 2 {
+		t.Errorf("refreshed %d times, want refreshes while the stream was open", refreshed)
+	}
+}
+
+func TestValidateOIDC(t *testing.T) {
+	valid := OIDC{Issuer: "https://accounts.google.com", ClientID: "id", RedirectURL: "https://status.example.com/auth/callback", AllowedDomains: []string{"example.com"}}
+	if err := ValidateOIDC(valid); err != nil {
+		t.Errorf("ValidateOIDC(valid) error = %v", err)
+	}
+
+	for name, mutate := range map[string]func(*OIDC){
+		"no issuer":         func(o *OIDC) { o.Issuer = "" },
+		"no client":         func(o *OIDC) { o.ClientID = "" },
+		"relative redirect": func(o *OIDC) { o.RedirectURL = "/auth/callback" },
+		"wrong redirect":    func(o *OIDC) { o.RedirectURL = "https://status.example.com/callback" },
+		"unrestricted":      func(o *OIDC) { o.AllowedDomains = nil },
+	} {
+		o := valid
+		mutate(&o)
+		if err := ValidateOIDC(o); err == nil {
+			t.Errorf("ValidateOIDC(%s) should fail", name)
+		}
+	}
+}
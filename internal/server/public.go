@@ -0,0 +1,290 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+// Public configures a server to serve a redacted view for people outside
+// the organisation, such as a customer-facing status page.
+//
+// The view omits endpoint URLs, certificate details and retry errors,
+// replaces error messages with generic ones such as "Timed out", and shows
+// only the allowed labels. The endpoint management API and metrics are not
+// served, and no authentication is required.
+type Public struct {
+	// Labels lists the label keys that are shown. Other labels are removed
+	// and cannot be used to select endpoints.
+	Labels []string
+
+	// Names maps endpoint names to the names shown publicly. Endpoints
+	// without an entry keep their names.
+	Names map[string]string
+}
+
+// ValidatePublic reports whether the display names are non-empty and
+// distinct.
+func ValidatePublic(p Public) error {
+	seen := make(map[string]string, len(p.Names))
+	for name, display := range p.Names {
+		if display == "" {
+			return fmt.Errorf("public name of %q is empty", name)
+		}
+		if other, ok := seen[display]; ok {
+			return fmt.Errorf("endpoints %q and %q have the same public name %q", other, name, display)
+		}
+		seen[display] = name
+	}
+	for _, label := range p.Labels {
+		if label == "" {
+			return errors.New("public label is empty")
+		}
+	}
+	return nil
+}
+
+// SetPublic makes the server serve only the redacted, public view of its
+// store. Must be called before [Server.Handler].
+func (s *Server) SetPublic(p Public) {
+	s.public = true
+	s.store = newPublicStore(s.store, p)
+}
+
+// MountPublic serves public, a server set up with [Server.SetPublic], at
+// its base path alongside this server's routes, without authentication.
+// Must be called before [Server.Handler].
+func (s *Server) MountPublic(public *Server) {
+	s.publicView = public
+}
+
+// publicErrors maps fragments of error messages to the generic messages
+// shown publicly, checked in order.
+var publicErrors = []struct {
+	fragments []string
+	message   string
+}{
+	{[]string{"timeout", "deadline exceeded", "timed out"}, "Timed out"},
+	{[]string{"certificate", "x509", "tls"}, "Secure connection failed"},
+	{[]string{"no such host", "nxdomain", "servfail", "lookup"}, "Could not be resolved"},
+	{[]string{"connection refused", "connection reset", "connect failed", "unreachable", "eof"}, "Unreachable"},
+	{[]string{"latency"}, "Slow response"},
+	{[]string{"http ", "status"}, "Unexpected response"},
+}
+
+// publicError returns the generic message shown publicly for an error.
+func publicError(msg string) string {
+	msg = strings.ToLower(msg)
+	for _, e := range publicErrors {
+		for _, f := range e.fragments {
+			if strings.Contains(msg, f) {
+				return e.message
+			}
+		}
+	}
+	return "Check failed"
+}
+
+// publicStore is a read-only view of a store with endpoints renamed and
+// sensitive fields removed, for the public view.
+type publicStore struct {
+	store.Store
+
+	labels   map[string]bool
+	names    map[string]string // endpoint name -> public name
+	internal map[string]string // public name -> endpoint name
+
+	// subs maps each subscriber's channel to the store's channel feeding it
+	mu   sync.Mutex
+	subs map[<-chan store.StatusResult]<-chan store.StatusResult
+}
+
+// newPublicStore wraps st in the redacted view configured by p.
+func newPublicStore(st store.Store, p Public) *publicStore {
+	ps := &publicStore{
+		Store:    st,
+		labels:   make(map[string]bool, len(p.Labels)),
+		names:    maps.Clone(p.Names),
+		internal: make(map[string]string, len(p.Names)),
+		subs:     make(map[<-chan store.StatusResult]<-chan store.StatusResult),
+	}
+	for _, label := range p.Labels {
+		ps.labels[label] = true
+	}
+	for name, display := range p.Names {
+		ps.internal[display] = name
+	}
+	return ps
+}
+
+// publicName returns the name an endpoint is shown as.
+func (ps *publicStore) publicName(name string) string {
+	if display, ok := ps.names[name]; ok {
+		return display
+	}
+	return name
+}
+
+// endpointName returns the endpoint shown as name. Renamed endpoints
+// cannot be looked up by their own names.
+func (ps *publicStore) endpointName(name string) (string, bool) {
+	if internal, ok := ps.internal[name]; ok {
+		return internal, true
+	}
+	if _, renamed := ps.names[name]; renamed {
+		return "", false
+	}
+	return name, true
+}
+
+// publicLabels returns the allowed labels, or nil if there are none.
+func (ps *publicStore) publicLabels(labels map[string]string) map[string]string {
+	var shown map[string]string
+	for k, v := range labels {
+		if ps.labels[k] {
+			if shown == nil {
+				shown = make(map[string]string)
+			}
+			shown[k] = v
+		}
+	}
+	return shown
+}
+
+// redactError returns the generic message for err, or nil.
+func redactError(err *string) *string {
+	if err == nil {
+		return nil
+	}
+	msg := publicError(*err)
+	return &msg
+}
+
+// redact returns r as shown publicly.
+func (ps *publicStore) redact(r store.StatusResult) store.StatusResult {
+	if r.Removed {
+		return store.StatusResult{Name: ps.publicName(r.Name), Removed: true}
+	}
+	r.Name = ps.publicName(r.Name)
+	r.URL = ""
+	r.Labels = ps.publicLabels(r.Labels)
+	r.Error = redactError(r.Error)
+	r.Cert = nil
+	r.AttemptErrors = nil
+	return r
+}
+
+func (ps *publicStore) GetAll() []store.StatusResult {
+	all := ps.Store.GetAll()
+	for i := range all {
+		all[i] = ps.redact(all[i])
+	}
+	return all
+}
+
+func (ps *publicStore) History(name string, since time.Time) []store.Sample {
+	internal, ok := ps.endpointName(name)
+	if !ok {
+		return nil
+	}
+	samples := ps.Store.History(internal, since)
+	for i := range samples {
+		samples[i].Error = redactError(samples[i].Error)
+	}
+	return samples
+}
+
+func (ps *publicStore) Uptime(name string, windows []time.Duration) []store.Uptime {
+	internal, ok := ps.endpointName(name)
+	if !ok {
+		return nil
+	}
+	return ps.Store.Uptime(internal, windows)
+}
+
+func (ps *publicStore) Latency(name string) []store.LatencyStats {
+	internal, ok := ps.endpointName(name)
+	if !ok {
+		return nil
+	}
+	return ps.Store.Latency(internal)
+}
+
+func (ps *publicStore) Incidents() []store.Incident {
+	incidents := ps.Store.Incidents()
+	for i := range incidents {
+		incidents[i].Name = ps.publicName(incidents[i].Name)
+		incidents[i].Labels = ps.publicLabels(incidents[i].Labels)
+		incidents[i].FirstError = redactError(incidents[i].FirstError)
+	}
+	return incidents
+}
+
+func (ps *publicStore) Counters() map[string]store.Counters {
+	counters := make(map[string]store.Counters)
+	for name, c := range ps.Store.Counters() {
+		counters[ps.publicName(name)] = c
+	}
+	return counters
+}
+
+// Update does nothing; the public view is read-only.
+func (ps *publicStore) Update(store.StatusResult) {}
+
+// Delete does nothing; the public view is read-only.
+func (ps *publicStore) Delete(string) {}
+
+// Subscribe returns a channel of redacted updates. A subscriber that falls
+// behind receives only the latest update for each endpoint, so a removal is
+// never lost, though status updates superseded before delivery may be.
+func (ps *publicStore) Subscribe() <-chan store.StatusResult {
+	in := ps.Store.Subscribe()
+	out := make(chan store.StatusResult, cap(in))
+	ps.mu.Lock()
+	ps.subs[out] = in
+	ps.mu.Unlock()
+
+	go func() {
+		defer close(out)
+		// latest undelivered update per endpoint, in arrival order
+		var queue []string
+		pending := make(map[string]store.StatusResult)
+		for {
+			var send chan<- store.StatusResult
+			var next store.StatusResult
+			if len(queue) > 0 {
+				send, next = out, pending[queue[0]]
+			}
+			select {
+			case r, ok := <-in:
+				if !ok {
+					return
+				}
+				r = ps.redact(r)
+				if _, queued := pending[r.Name]; !queued {
+					queue = append(queue, r.Name)
+				}
+				pending[r.Name] = r
+			case send <- next:
+				delete(pending, queue[0])
+				queue = queue[1:]
+			}
+		}
+	}()
+	return out
+}
+
+func (ps *publicStore) Unsubscribe(ch <-chan store.StatusResult) {
+	ps.mu.Lock()
+	in, ok := ps.subs[ch]
+	delete(ps.subs, ch)
+	ps.mu.Unlock()
+	if ok {
+		ps.Store.Unsubscribe(in)
+	}
+}
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+func newPublicTestStore() *mockStore {
+	errMsg := `request failed: Get "https://api.internal/health?api_key=secret": dial tcp 10.0.0.7:443: i/o timeout`
+	ms := newMockStore()
+	ms.Update(store.StatusResult{
+		Name:          "payments-api-prod",
+		URL:           "https://api.internal/health?api_key=secret",
+		Status:        "down",
+		Labels:        map[string]string{"region": "eu", "team": "payments", "host": "10.0.0.7"},
+		Error:         &errMsg,
+		Cert:          &store.CertInfo{Issuer: "CN=Internal CA"},
+		AttemptErrors: []string{errMsg},
+		CheckedAt:     time.Now(),
+	})
+	ms.Update(store.StatusResult{Name: "Website", URL: "https://example.com", Status: "up", CheckedAt: time.Now()})
+	ms.incidents = []store.Incident{{Name: "payments-api-prod", Labels: map[string]string{"region": "eu", "team": "payments"}, FirstError: &errMsg}}
+	return ms
+}
+
+func TestPublic(t *testing.T) {
+	srv := NewServer(newPublicTestStore(), 0, nil, "", testLogger())
+	srv.EnableEndpointAPI(&fakeManager{}, "admin-token")
+	srv.SetPublic(Public{Labels: []string{"region"}, Names: map[string]string{"payments-api-prod": "Payments"}})
+	handler, err := srv.Handler()
+	if err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/api/status")
+	for _, leak := range []string{"api.internal", "secret", "10.0.0.7", "payments-api-prod", "team", "Internal CA"} {
+		if strings.Contains(rec.Body.String(), leak) {
+			t.Errorf("/api/status leaks %q: %s", leak, rec.Body.String())
+		}
+	}
+	var statuses []store.StatusResult
+	if err := json.NewDecoder(rec.Body).Decode(&statuses); err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range statuses {
+		if st.Name != "Payments" {
+			continue
+		}
+		if st.Error == nil || *st.Error != "Timed out" {
+			t.Errorf("Error = %v, want generic message", st.Error)
+		}
+		if st.Labels["region"] != "eu" || len(st.Labels) != 1 {
+			t.Errorf("Labels = %v, want only region", st.Labels)
+		}
+	}
+
+	if rec := get("/api/history?name=Payments"); rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "api.internal") {
+		t.Errorf("history by public name = %d %s, want redacted samples", rec.Code, rec.Body.String())
+	}
+	if rec := get("/api/incidents?label=team:payments"); strings.Contains(rec.Body.String(), "Payments") {
+		t.Errorf("incidents selectable by hidden label: %s", rec.Body.String())
+	}
+
+	for _, path := range []string{"/api/history?name=payments-api-prod", "/metrics", "/api/endpoints"} {
+		if rec := get(path); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestPublic_SSE(t *testing.T) {
+	ms := newMockStore()
+	srv := NewServer(ms, 0, nil, "", testLogger())
+	srv.SetPublic(Public{Names: map[string]string{"db-primary": "Database"}})
+	handler, err := srv.Handler()
+	if err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	errMsg := "connect failed: dial tcp 10.0.0.9:5432: connection refused"
+	ms.Update(store.StatusResult{Name: "db-primary", URL: "postgres://10.0.0.9", Status: "down", Error: &errMsg})
+
+	resp, err := http.Get(ts.URL + "/api/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+
+	// the initial snapshot and later updates are both redacted
+	ms.Update(store.StatusResult{Name: "db-primary", URL: "postgres://10.0.0.9", Status: "up"})
+	for _, want := range []string{`"error":"Unreachable"`, `"status":"up"`} {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(line, `"name":"Database"`) || !strings.Contains(line, want) || strings.Contains(line, "10.0.0.9") {
+			t.Errorf("event = %q, want redacted update with %s", line, want)
+		}
+		reader.ReadString('\n') // blank line ending the event
+	}
+}
+
+func TestPublic_SubscribeKeepsRemovals(t *testing.T) {
+	ms := newMockStore()
+	ps := newPublicStore(ms, Public{Names: map[string]string{"db-primary": "Database"}})
+	ch := ps.Subscribe()
+	defer ps.Unsubscribe(ch)
+
+	// wait for the forwarder to take each update from the store, so only
+	// the public subscription can fall behind
+	in := ps.subs[ch]
+	update := func(r store.StatusResult) {
+		ms.Update(r)
+		for len(in) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// overflow the subscriber's buffer before it reads anything
+	for i := range 2 * cap(ch) {
+		update(store.StatusResult{Name: "api", Status: "up", CheckedAt: time.Unix(int64(i), 0)})
+	}
+	update(store.StatusResult{Name: "db-primary", Status: "up"})
+	ms.Delete("db-primary")
+	update(store.StatusResult{Name: "api", Status: "down"})
+	time.Sleep(50 * time.Millisecond)
+
+	got := make(map[string]store.StatusResult)
+	deadline := time.After(2 * time.Second)
+	for !got["Database"].Removed || got["api"].Status != "down" {
+		select {
+		case r := <-ch:
+			got[r.Name] = r
+		case <-deadline:
+			t.Fatalf("updates received = %+v, want Database removed and api down", got)
+		}
+	}
+}
+
+func TestMountPublic(t *testing.T) {
+	ms := newPublicTestStore()
+	srv := NewServer(ms, 0, nil, "", testLogger())
+	srv.EnableAuth(Auth{Tokens: []string{"api-token"}})
+	public := NewServer(ms, 0, nil, "", testLogger())
+	public.SetBasePath("/public")
+	public.SetPublic(Public{})
+	srv.MountPublic(public)
+	handler, err := srv.Handler()
+	if err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{"/public/api/status", http.StatusOK},
+		{"/public", http.StatusMovedPermanently},
+		{"/api/status", http.StatusUnauthorized},
+		{"/publicity/api/status", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantCode {
+			t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.wantCode)
+		}
+	}
+}
+
+func TestPublicError(t *testing.T) {
+	tests := map[string]string{
+		`request failed: Get "https://x": context deadline exceeded`:        "Timed out",
+		"request failed: tls: failed to verify certificate: x509: expired":  "Secure connection failed",
+		"request failed: dial tcp: lookup api.internal: no such host":       "Could not be resolved",
+		"connect failed: dial tcp 10.0.0.1:22: connect: connection refused": "Unreachable",
+		"latency 2.1s > 1s":         "Slow response",
+		"HTTP 503":                  "Unexpected response",
+		"graphql error: db is down": "Check failed",
+	}
+	for msg, want := range tests {
+		if got := publicError(msg); got != want {
+			t.Errorf("publicError(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestValidatePublic(t *testing.T) {
+	if err := ValidatePublic(Public{Labels: []string{"region"}, Names: map[string]string{"a": "A", "b": "B"}}); err != nil {
+		t.Errorf("ValidatePublic(valid) error = %v", err)
+	}
+	if err := ValidatePublic(Public{Names: map[string]string{"a": "Same", "b": "Same"}}); err == nil {
+		t.Error("ValidatePublic() with duplicate names should fail")
+	}
+	if err := ValidatePublic(Public{Names: map[string]string{"a": ""}}); err == nil {
+		t.Error("ValidatePublic() with an empty name should fail")
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/jpalmerr/pulseboard/internal/store"
@@ -34,6 +36,22 @@ const (
 //   - GET /: Serves the embedded dashboard HTML
 //   - GET /api/status: Returns all current statuses as JSON
 //   - GET /api/sse: Server-Sent Events stream for real-time updates
+//   - GET /api/history: Recent samples for one endpoint as JSON
+//   - GET /api/uptime: Uptime percentages per endpoint as JSON
+//   - GET /api/latency: Latency percentiles per endpoint as JSON
+//   - GET /api/incidents: Open and closed incidents as JSON
+//   - GET /metrics: Metrics in the Prometheus text format
+//   - GET /badge/{name}.svg: SVG status badge for one endpoint
+//   - GET /badge: SVG status badge for endpoints selected by label
+//   - GET /api/health: 200 or 503 from a rollup of endpoint statuses (path configurable)
+//
+// [Server.EnableEndpointAPI] additionally exposes /api/endpoints for
+// changing the polled endpoints at runtime. [Server.SetPublic] serves a
+// redacted view instead, and [Server.MountPublic] serves one alongside.
+//
+// Routes are relative to the base path set by [Server.SetBasePath].
+// [Server.Handler] serves them without a listener, for mounting on an
+// existing HTTP server.
 //
 // The server is designed for graceful shutdown via context cancellation.
 type Server struct {
@@ -43,6 +61,42 @@ type Server struct {
 	assets     fs.FS
 	title      string
 	logger     *slog.Logger
+
+	// endpoints and adminToken back /api/endpoints; nil when disabled
+	endpoints  EndpointManager
+	adminToken string
+
+	// uptimeWindows are reported by /api/uptime when none are requested
+	uptimeWindows []time.Duration
+
+	// pollStats backs the poll queue metrics; nil omits them
+	pollStats PollStats
+
+	// authConfig and oidcConfig configure authentication; it is disabled
+	// when neither is set
+	authConfig Auth
+	oidcConfig *OIDC
+
+	// basePath prefixes every route; empty serves at the root
+	basePath string
+
+	// public serves only the redacted view set by SetPublic; publicView is
+	// a public server mounted alongside this one's routes
+	public     bool
+	publicView *Server
+
+	// healthPath serves the health rollup of healthRules
+	healthPath  string
+	healthRules []HealthRule
+
+	// tls serves HTTPS from Start; nil serves plain HTTP
+	tls *TLS
+
+	// badgeMaxAge is how long badges may be cached; zero disables caching
+	badgeMaxAge time.Duration
+
+	// sseClients counts connected SSE clients
+	sseClients atomic.Int64
 }
 
 // NewServer creates a new HTTP [Server].
@@ -57,15 +111,116 @@ type Server struct {
 // The server is not started until [Server.Start] is called.
 func NewServer(st store.Store, port int, assets fs.FS, title string, logger *slog.Logger) *Server {
 	return &Server{
-		store:  st,
-		port:   port,
-		assets: assets,
-		title:  title,
-		logger: logger,
+		store:         st,
+		port:          port,
+		assets:        assets,
+		title:         title,
+		logger:        logger,
+		uptimeWindows: store.DefaultUptimeWindows,
+		healthPath:    DefaultHealthPath,
+	}
+}
+
+// SetUptimeWindows sets the windows /api/uptime reports when the request
+// names none. An empty slice keeps the current windows.
+func (s *Server) SetUptimeWindows(windows []time.Duration) {
+	if len(windows) > 0 {
+		s.uptimeWindows = windows
 	}
 }
 
-// Start begins serving HTTP requests in a background goroutine.
+// SetBasePath serves everything under path, such as "/status", instead of
+// at the root. Requests for path itself are redirected to path + "/", so
+// the dashboard's relative URLs resolve. An empty path serves at the root.
+func (s *Server) SetBasePath(path string) {
+	s.basePath = strings.TrimSuffix(path, "/")
+}
+
+// Handler returns the handler serving the dashboard, API and SSE stream,
+// for mounting on an existing HTTP server. Requests outside the base path
+// get a 404.
+//
+// Returns an error if the health path clashes with a built-in route.
+func (s *Server) Handler() (http.Handler, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/sse", s.handleSSE)
+	mux.HandleFunc("/api/history", s.handleHistory)
+	mux.HandleFunc("/api/uptime", s.handleUptime)
+	mux.HandleFunc("/api/latency", s.handleLatency)
+	mux.HandleFunc("/api/incidents", s.handleIncidents)
+	mux.HandleFunc("/badge", s.handleGroupBadge)
+	mux.HandleFunc("/badge/", s.handleBadge)
+	if !s.public {
+		mux.HandleFunc("/metrics", s.handleMetrics)
+	}
+	if s.endpoints != nil && !s.public {
+		mux.HandleFunc("/api/endpoints", s.handleEndpoints)
+	}
+	if err := ValidateHealthPath(s.healthPath); err != nil {
+		return nil, err
+	}
+	mux.HandleFunc(s.healthPath, s.handleHealth)
+	if s.assets != nil {
+		mux.HandleFunc("/", s.handleDashboard)
+	}
+
+	var handler http.Handler = mux
+	if auth := s.newAuthenticator(); auth != nil && !s.public {
+		if auth.oidc != nil {
+			mux.HandleFunc("/auth/login", auth.handleLogin)
+			mux.HandleFunc("/auth/callback", auth.handleCallback)
+			mux.HandleFunc("/auth/logout", auth.handleLogout)
+		}
+		handler = auth.middleware(mux)
+	}
+
+	if s.basePath != "" {
+		handler = s.withBasePath(handler)
+	}
+
+	if s.publicView == nil {
+		return handler, nil
+	}
+	public, err := s.publicView.Handler()
+	if err != nil {
+		return nil, err
+	}
+	internal := handler
+	publicBase := s.publicView.basePath
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == publicBase || strings.HasPrefix(r.URL.Path, publicBase+"/") {
+			public.ServeHTTP(w, r)
+			return
+		}
+		internal.ServeHTTP(w, r)
+	}), nil
+}
+
+// withBasePath serves handler under the base path. Requests for the base
+// path itself are redirected to base + "/"; others outside it get a 404.
+func (s *Server) withBasePath(handler http.Handler) http.Handler {
+	base := s.basePath
+	stripped := http.StripPrefix(base, handler)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == base {
+			target := base + "/"
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, base+"/") {
+			http.NotFound(w, r)
+			return
+		}
+		stripped.ServeHTTP(w, r)
+	})
+}
+
+// Start begins serving HTTP requests in a background goroutine, or HTTPS
+// requests if [Server.EnableTLS] was called.
 //
 // Start is non-blocking and returns immediately after confirming the server
 // is listening. The server will continue running until the context is
@@ -74,13 +229,18 @@ func NewServer(st store.Store, port int, assets fs.FS, title string, logger *slo
 //
 // Returns an error if the server fails to bind to the configured port.
 func (s *Server) Start(ctx context.Context) error {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/api/status", s.handleStatus)
-	mux.HandleFunc("/api/sse", s.handleSSE)
-	if s.assets != nil {
-		mux.HandleFunc("/", s.handleDashboard)
+	handler, err := s.Handler()
+	if err != nil {
+		return err
 	}
+	return s.Serve(ctx, handler)
+}
 
+// Serve is like [Server.Start] but serves handler, which must come from
+// [Server.Handler]. Use it to listen with a handler that is also mounted
+// elsewhere: each call to Handler creates its own sessions and sign-in
+// state, so a second handler would not accept the first one's cookies.
+func (s *Server) Serve(ctx context.Context, handler http.Handler) error {
 	// create listener first to verify port availability synchronously
 	addr := fmt.Sprintf(":%d", s.port)
 	ln, err := net.Listen("tcp", addr)
@@ -89,7 +249,7 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 
 	s.httpServer = &http.Server{
-		Handler: mux,
+		Handler: handler,
 		// BaseContext derives all request contexts from the server context.
 		// When ctx is cancelled, all request contexts are also cancelled,
 		// enabling graceful shutdown of long-running handlers like SSE.
@@ -98,8 +258,19 @@ func (s *Server) Start(ctx context.Context) error {
 		},
 	}
 
+	serve := s.httpServer.Serve
+	if s.tls != nil {
+		if err := s.configureTLS(ctx, s.httpServer); err != nil {
+			_ = ln.Close()
+			return err
+		}
+		serve = func(ln net.Listener) error {
+			return s.httpServer.ServeTLS(ln, "", "")
+		}
+	}
+
 	go func() {
-		if err := s.httpServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+		if err := serve(ln); err != nil && err != http.ErrServerClosed {
 			s.logger.Error("http server error", "error", err)
 		}
 	}()
@@ -165,8 +336,213 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// historyResponse is the JSON body returned by /api/history.
+type historyResponse struct {
+	Name    string         `json:"name"`
+	Samples []store.Sample `json:"samples"`
+}
+
+// handleHistory returns the recorded samples for the endpoint given by the
+// name query parameter. The optional since parameter is either an RFC 3339
+// timestamp or a duration such as "15m" counted back from now.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "name query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	var since time.Time
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		var err error
+		since, err = parseSince(raw, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	samples := s.store.History(name, since)
+	if samples == nil {
+		http.Error(w, fmt.Sprintf("unknown endpoint %q", name), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if err := json.NewEncoder(w).Encode(historyResponse{Name: name, Samples: samples}); err != nil {
+		s.logger.Error("failed to encode history response", "error", err)
+	}
+}
+
+// parseSince parses a since parameter relative to now.
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("since must be an RFC 3339 timestamp or a positive duration, got %q", raw)
+	}
+	return now.Add(-d), nil
+}
+
+// uptimeResponse is the JSON body returned by /api/uptime for one endpoint.
+type uptimeResponse struct {
+	Name   string         `json:"name"`
+	Uptime []store.Uptime `json:"uptime"`
+}
+
+// handleUptime returns uptime over the windows given by repeated window
+// query parameters, such as "24h" or "7d", defaulting to the configured
+// windows. With a name parameter it returns that endpoint's uptime;
+// otherwise it returns a list covering every endpoint, sorted by name.
+func (s *Server) handleUptime(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	windows := s.uptimeWindows
+	if raw := r.URL.Query()["window"]; len(raw) > 0 {
+		windows = make([]time.Duration, 0, len(raw))
+		for _, v := range raw {
+			d, err := store.ParseWindow(v)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			windows = append(windows, d)
+		}
+	}
+
+	var body any
+	if name := r.URL.Query().Get("name"); name != "" {
+		uptime := s.store.Uptime(name, windows)
+		if uptime == nil {
+			http.Error(w, fmt.Sprintf("unknown endpoint %q", name), http.StatusNotFound)
+			return
+		}
+		body = uptimeResponse{Name: name, Uptime: uptime}
+	} else {
+		statuses := s.store.GetAll()
+		slices.SortFunc(statuses, func(a, b store.StatusResult) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		all := make([]uptimeResponse, 0, len(statuses))
+		for _, st := range statuses {
+			if uptime := s.store.Uptime(st.Name, windows); uptime != nil {
+				all = append(all, uptimeResponse{Name: st.Name, Uptime: uptime})
+			}
+		}
+		body = all
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.Error("failed to encode uptime response", "error", err)
+	}
+}
+
+// latencyResponse is the JSON body returned by /api/latency for one endpoint.
+type latencyResponse struct {
+	Name    string               `json:"name"`
+	Latency []store.LatencyStats `json:"latency"`
+}
+
+// handleLatency returns latency percentiles over the configured windows.
+// With a name parameter it returns that endpoint's percentiles; otherwise
+// it returns a list covering every endpoint, sorted by name.
+func (s *Server) handleLatency(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body any
+	if name := r.URL.Query().Get("name"); name != "" {
+		latency := s.store.Latency(name)
+		if latency == nil {
+			http.Error(w, fmt.Sprintf("unknown endpoint %q", name), http.StatusNotFound)
+			return
+		}
+		body = latencyResponse{Name: name, Latency: latency}
+	} else {
+		statuses := s.store.GetAll()
+		slices.SortFunc(statuses, func(a, b store.StatusResult) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		all := make([]latencyResponse, 0, len(statuses))
+		for _, st := range statuses {
+			if latency := s.store.Latency(st.Name); latency != nil {
+				all = append(all, latencyResponse{Name: st.Name, Latency: latency})
+			}
+		}
+		body = all
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.Error("failed to encode latency response", "error", err)
+	}
+}
+
+// handleIncidents returns incidents, newest first. The optional state
+// parameter ("open" or "closed") filters by state, and repeated label
+// parameters such as "env:prod" select endpoints by label.
+func (s *Server) handleIncidents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	state := query.Get("state")
+	if state != "" && state != "open" && state != "closed" {
+		http.Error(w, fmt.Sprintf("state must be open or closed, got %q", state), http.StatusBadRequest)
+		return
+	}
+	sel, err := parseLabelSelector(query["label"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	incidents := make([]store.Incident, 0)
+	for _, inc := range s.store.Incidents() {
+		if state != "" && inc.Open() != (state == "open") {
+			continue
+		}
+		if !sel.matches(inc.Labels) {
+			continue
+		}
+		incidents = append(incidents, inc)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if err := json.NewEncoder(w).Encode(incidents); err != nil {
+		s.logger.Error("failed to encode incidents response", "error", err)
+	}
+}
+
 // handleSSE streams status updates via Server-Sent Events.
 //
+// Status updates are sent as unnamed events carrying a JSON [store.StatusResult].
+// Removal of an endpoint is sent as a "removed" event whose data is
+// {"name": "..."}.
+//
 // The handler uses write deadlines to prevent goroutine leaks when clients are
 // slow or disconnected. Without deadlines, a blocked Fprintf call would prevent
 // the handler from detecting context cancellation or channel closure.
@@ -183,7 +559,7 @@ func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 	// writeAndFlush writes SSE data with a deadline to prevent blocking forever.
 	// If the client is slow or disconnected, the write will timeout rather than
 	// blocking indefinitely, allowing the handler to detect shutdown signals.
-	writeAndFlush := func(data []byte) error {
+	writeAndFlush := func(event string, data []byte) error {
 		if deadlinesSupported {
 			if err := rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout)); err != nil {
 				// deadline not supported by underlying connection, continue without
@@ -192,6 +568,11 @@ func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		if event != "" {
+			if _, err := fmt.Fprintf(w, "event: %s\n", event); err != nil {
+				return err
+			}
+		}
 		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
 			return err
 		}
@@ -207,13 +588,15 @@ func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 
 	ch := s.store.Subscribe()
 	defer s.store.Unsubscribe(ch)
+	s.sseClients.Add(1)
+	defer s.sseClients.Add(-1)
 
 	for _, status := range s.store.GetAll() {
 		data, err := json.Marshal(status)
 		if err != nil {
 			continue
 		}
-		if err := writeAndFlush(data); err != nil {
+		if err := writeAndFlush("", data); err != nil {
 			return
 		}
 	}
@@ -224,11 +607,19 @@ func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
 			if !ok {
 				return
 			}
-			data, err := json.Marshal(result)
+			event := ""
+			var data []byte
+			var err error
+			if result.Removed {
+				event = "removed"
+				data, err = json.Marshal(map[string]string{"name": result.Name})
+			} else {
+				data, err = json.Marshal(result)
+			}
 			if err != nil {
 				continue
 			}
-			if err := writeAndFlush(data); err != nil {
+			if err := writeAndFlush(event, data); err != nil {
 				return
 			}
 
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -28,6 +29,7 @@ func testLogger() *slog.Logger {
 type mockStore struct {
 	mu          sync.RWMutex
 	statuses    []store.StatusResult
+	incidents   []store.Incident
 	subscribers map[chan store.StatusResult]struct{}
 	subMu       sync.Mutex
 }
@@ -65,6 +67,93 @@ func (m *mockStore) Update(result store.StatusResult) {
 	m.subMu.Unlock()
 }
 
+func (m *mockStore) History(name string, since time.Time) []store.Sample {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.statuses {
+		if s.Name == name && !s.CheckedAt.Before(since) {
+			return []store.Sample{{CheckedAt: s.CheckedAt, Status: s.Status, ResponseTimeMs: s.ResponseTimeMs, Error: s.Error}}
+		}
+		if s.Name == name {
+			return []store.Sample{}
+		}
+	}
+	return nil
+}
+
+func (m *mockStore) Uptime(name string, windows []time.Duration) []store.Uptime {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.statuses {
+		if s.Name == name {
+			uptime := make([]store.Uptime, len(windows))
+			for i, w := range windows {
+				uptime[i] = store.Uptime{Window: store.FormatWindow(w), Checks: map[string]int{s.Status: 1}}
+			}
+			return uptime
+		}
+	}
+	return nil
+}
+
+func (m *mockStore) Latency(name string) []store.LatencyStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.statuses {
+		if s.Name == name {
+			return []store.LatencyStats{{Window: "1h", Count: 1, P50: s.ResponseTimeMs, P90: s.ResponseTimeMs, P99: s.ResponseTimeMs, Max: s.ResponseTimeMs}}
+		}
+	}
+	return nil
+}
+
+func (m *mockStore) Incidents() []store.Incident {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]store.Incident(nil), m.incidents...)
+}
+
+func (m *mockStore) Counters() map[string]store.Counters {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]store.Counters, len(m.statuses))
+	for _, s := range m.statuses {
+		buckets := make([]uint64, len(store.LatencyBucketBounds)+1)
+		buckets[len(buckets)-1] = 1
+		out[s.Name] = store.Counters{
+			Checks:         map[string]uint64{s.Status: 1},
+			LatencyBuckets: buckets,
+			LatencyCount:   1,
+			LatencySum:     float64(s.ResponseTimeMs) / 1000,
+		}
+	}
+	return out
+}
+
+func (m *mockStore) DroppedUpdates() uint64 {
+	return 7
+}
+
+func (m *mockStore) Delete(name string) {
+	m.mu.Lock()
+	for i, s := range m.statuses {
+		if s.Name == name {
+			m.statuses = append(m.statuses[:i], m.statuses[i+1:]...)
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	m.subMu.Lock()
+	for ch := range m.subscribers {
+		select {
+		case ch <- store.StatusResult{Name: name, Removed: true}:
+		default:
+		}
+	}
+	m.subMu.Unlock()
+}
+
 func (m *mockStore) GetAll() []store.StatusResult {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -163,6 +252,33 @@ func TestHandleSSE_StreamsUpdates(t *testing.T) {
 	}
 }
 
+func TestHandleSSE_RemovedEvent(t *testing.T) {
+	ms := newMockStore()
+	ms.Update(store.StatusResult{Name: "OldAPI", Status: "up"})
+	srv := NewServer(ms, 0, nil, "", testLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/api/sse", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		srv.handleSSE(rec, req)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	ms.Delete("OldAPI")
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	want := "event: removed\ndata: {\"name\":\"OldAPI\"}\n\n"
+	if body := rec.Body.String(); !strings.Contains(body, want) {
+		t.Errorf("response should contain %q, got: %s", want, body)
+	}
+}
+
 func TestHandleSSE_ClientDisconnect(t *testing.T) {
 	ms := newMockStore()
 	srv := NewServer(ms, 0, nil, "", testLogger())
@@ -902,3 +1018,246 @@ func TestHandleDashboard_TitleWithAmpersand(t *testing.T) {
 		t.Errorf("expected ampersand to be escaped, got: %s", body)
 	}
 }
+
+func TestHandleHistory(t *testing.T) {
+	ms := newMockStore()
+	checked := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	errMsg := "timeout"
+	ms.Update(store.StatusResult{Name: "API", Status: "down", ResponseTimeMs: 42, CheckedAt: checked, Error: &errMsg})
+	srv := NewServer(ms, 0, nil, "", testLogger())
+
+	tests := []struct {
+		name        string
+		target      string
+		wantCode    int
+		wantSamples int
+	}{
+		{"all samples", "/api/history?name=API", http.StatusOK, 1},
+		{"since timestamp before", "/api/history?name=API&since=2026-01-01T11:00:00Z", http.StatusOK, 1},
+		{"since timestamp after", "/api/history?name=API&since=2026-01-01T13:00:00Z", http.StatusOK, 0},
+		{"since duration", "/api/history?name=API&since=15m", http.StatusOK, 0},
+		{"missing name", "/api/history", http.StatusBadRequest, 0},
+		{"bad since", "/api/history?name=API&since=yesterday", http.StatusBadRequest, 0},
+		{"unknown endpoint", "/api/history?name=Nope", http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.handleHistory(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var resp historyResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if resp.Name != "API" || len(resp.Samples) != tt.wantSamples {
+				t.Errorf("response = %+v, want %d samples for API", resp, tt.wantSamples)
+			}
+		})
+	}
+}
+
+func TestHandleUptime(t *testing.T) {
+	ms := newMockStore()
+	ms.Update(store.StatusResult{Name: "B", Status: "down"})
+	ms.Update(store.StatusResult{Name: "A", Status: "up"})
+	srv := NewServer(ms, 0, nil, "", testLogger())
+	srv.SetUptimeWindows([]time.Duration{time.Hour})
+
+	tests := []struct {
+		name        string
+		target      string
+		wantCode    int
+		wantWindows []string
+	}{
+		{"configured windows", "/api/uptime?name=A", http.StatusOK, []string{"1h"}},
+		{"requested windows", "/api/uptime?name=A&window=24h&window=7d", http.StatusOK, []string{"24h", "7d"}},
+		{"bad window", "/api/uptime?name=A&window=soon", http.StatusBadRequest, nil},
+		{"zero window", "/api/uptime?window=0d", http.StatusBadRequest, nil},
+		{"unknown endpoint", "/api/uptime?name=Nope", http.StatusNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.handleUptime(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var resp uptimeResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			var got []string
+			for _, u := range resp.Uptime {
+				got = append(got, u.Window)
+			}
+			if resp.Name != "A" || !slices.Equal(got, tt.wantWindows) {
+				t.Errorf("response = %+v, want windows %v for A", resp, tt.wantWindows)
+			}
+		})
+	}
+
+	t.Run("all endpoints", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.handleUptime(rec, httptest.NewRequest(http.MethodGet, "/api/uptime", nil))
+
+		var resp []uptimeResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(resp) != 2 || resp[0].Name != "A" || resp[1].Name != "B" {
+			t.Errorf("response = %+v, want A then B", resp)
+		}
+	})
+}
+
+func TestHandleLatency(t *testing.T) {
+	ms := newMockStore()
+	ms.Update(store.StatusResult{Name: "B", Status: "up", ResponseTimeMs: 20})
+	ms.Update(store.StatusResult{Name: "A", Status: "up", ResponseTimeMs: 10})
+	srv := NewServer(ms, 0, nil, "", testLogger())
+
+	rec := httptest.NewRecorder()
+	srv.handleLatency(rec, httptest.NewRequest(http.MethodGet, "/api/latency?name=A", nil))
+	for _, field := range []string{`"name":"A"`, `"window":"1h"`, `"p50_ms":10`, `"p99_ms":10`, `"max_ms":10`} {
+		if !strings.Contains(rec.Body.String(), field) {
+			t.Errorf("response missing %s: %s", field, rec.Body.String())
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	srv.handleLatency(rec, httptest.NewRequest(http.MethodGet, "/api/latency?name=Nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown endpoint status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.handleLatency(rec, httptest.NewRequest(http.MethodGet, "/api/latency", nil))
+	var all []latencyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &all); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(all) != 2 || all[0].Name != "A" || all[1].Name != "B" {
+		t.Errorf("response = %+v, want A then B", all)
+	}
+}
+
+func TestHandleIncidents(t *testing.T) {
+	ended := time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC)
+	ms := newMockStore()
+	ms.incidents = []store.Incident{
+		{Name: "API", Labels: map[string]string{"env": "prod"}, WorstStatus: "down"},
+		{Name: "DB", Labels: map[string]string{"env": "prod", "tier": "data"}, EndedAt: &ended, WorstStatus: "degraded"},
+		{Name: "Staging", Labels: map[string]string{"env": "staging"}, EndedAt: &ended, WorstStatus: "down"},
+	}
+	srv := NewServer(ms, 0, nil, "", testLogger())
+
+	tests := []struct {
+		name      string
+		target    string
+		wantCode  int
+		wantNames []string
+	}{
+		{"all", "/api/incidents", http.StatusOK, []string{"API", "DB", "Staging"}},
+		{"open", "/api/incidents?state=open", http.StatusOK, []string{"API"}},
+		{"closed", "/api/incidents?state=closed", http.StatusOK, []string{"DB", "Staging"}},
+		{"label", "/api/incidents?label=env:prod", http.StatusOK, []string{"API", "DB"}},
+		{"labels and state", "/api/incidents?label=env=prod&label=tier:data&state=closed", http.StatusOK, []string{"DB"}},
+		{"no match", "/api/incidents?label=env:dev", http.StatusOK, nil},
+		{"bad state", "/api/incidents?state=resolved", http.StatusBadRequest, nil},
+		{"bad label", "/api/incidents?label=prod", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.handleIncidents(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var resp []store.Incident
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			var got []string
+			for _, inc := range resp {
+				got = append(got, inc.Name)
+			}
+			if !slices.Equal(got, tt.wantNames) {
+				t.Errorf("incidents = %v, want %v", got, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestHandleHistory_JSONFields(t *testing.T) {
+	ms := newMockStore()
+	errMsg := "timeout"
+	ms.Update(store.StatusResult{Name: "API", Status: "down", ResponseTimeMs: 42, CheckedAt: time.Now(), Error: &errMsg})
+	srv := NewServer(ms, 0, nil, "", testLogger())
+
+	rec := httptest.NewRecorder()
+	srv.handleHistory(rec, httptest.NewRequest(http.MethodGet, "/api/history?name=API", nil))
+
+	for _, field := range []string{`"checked_at"`, `"status":"down"`, `"response_time_ms":42`, `"error":"timeout"`} {
+		if !strings.Contains(rec.Body.String(), field) {
+			t.Errorf("response missing %s: %s", field, rec.Body.String())
+		}
+	}
+}
+
+func TestHandler_BasePath(t *testing.T) {
+	ms := newMockStore()
+	ms.Update(store.StatusResult{Name: "API", Status: "up"})
+	srv := NewServer(ms, 0, &mockFS{content: "<h1>{{.Title}}</h1>"}, "", testLogger())
+	srv.SetBasePath("/status/")
+
+	handler, err := srv.Handler()
+	if err != nil {
+		t.Fatalf("Handler() error = %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/status/", http.StatusOK, "<h1>PulseBoard</h1>"},
+		{"/status/api/status", http.StatusOK, `"name":"API"`},
+		{"/status/badge/API.svg", http.StatusOK, "<svg"},
+		{"/api/status", http.StatusNotFound, ""},
+		{"/statusx/api/status", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantCode || !strings.Contains(rec.Body.String(), tt.wantBody) {
+			t.Errorf("GET %s = %d %q, want %d containing %q", tt.path, rec.Code, rec.Body.String(), tt.wantCode, tt.wantBody)
+		}
+	}
+
+	// the bare base path redirects so relative URLs resolve under it
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status?x=1", nil))
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/status/?x=1" {
+		t.Errorf("GET /status = %d to %q, want redirect to /status/?x=1", rec.Code, rec.Header().Get("Location"))
+	}
+}
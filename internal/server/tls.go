@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// certReloadInterval is how often the certificate files are checked for
+// changes.
+const certReloadInterval = 10 * time.Second
+
+// TLS configures HTTPS. The certificate is reloaded when its files change,
+// so rotated certificates are picked up without a restart.
+type TLS struct {
+	// CertFile and KeyFile are PEM files holding the certificate chain and
+	// its private key.
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile is a PEM bundle of CAs that client certificates are
+	// verified against. When set, a verified client certificate
+	// authenticates the request if authentication is enabled.
+	ClientCAFile string
+
+	// RequireClientCert rejects connections without a verified client
+	// certificate. Otherwise clients may connect without one.
+	RequireClientCert bool
+
+	// DisableHTTP2 serves HTTP/1.1 only.
+	DisableHTTP2 bool
+}
+
+// ValidateTLS reports whether the certificate, key and client CAs load.
+func ValidateTLS(t TLS) error {
+	if t.CertFile == "" || t.KeyFile == "" {
+		return errors.New("tls requires a certificate file and a key file")
+	}
+	if _, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile); err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	if t.ClientCAFile != "" {
+		if _, err := loadCertPool(t.ClientCAFile); err != nil {
+			return err
+		}
+	} else if t.RequireClientCert {
+		return errors.New("requiring client certificates needs a client CA file")
+	}
+	return nil
+}
+
+// EnableTLS makes [Server.Start] serve HTTPS. Must be called before
+// [Server.Start].
+func (s *Server) EnableTLS(t TLS) {
+	s.tls = &t
+}
+
+// loadCertPool reads a PEM bundle of CA certificates.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("client CA file %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// tlsConfig loads the certificate and returns the TLS settings for the
+// server, reloading the certificate until ctx is cancelled.
+func (s *Server) tlsConfig(ctx context.Context) (*tls.Config, error) {
+	certs, err := newCertReloader(s.tls.CertFile, s.tls.KeyFile, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	go certs.watch(ctx, certReloadInterval)
+
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.getCertificate,
+	}
+	if s.tls.ClientCAFile != "" {
+		pool, err := loadCertPool(s.tls.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if s.tls.RequireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return cfg, nil
+}
+
+// configureTLS sets up srv to serve HTTPS, with HTTP/2 unless disabled.
+func (s *Server) configureTLS(ctx context.Context, srv *http.Server) error {
+	cfg, err := s.tlsConfig(ctx)
+	if err != nil {
+		return err
+	}
+	srv.TLSConfig = cfg
+	if s.tls.DisableHTTP2 {
+		// a non-nil, empty map stops ServeTLS from enabling HTTP/2
+		srv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+	}
+	return nil
+}
+
+// fileStamp identifies a version of a file by its size and modification
+// time.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// statFile returns the stamp of the file at path, following symlinks, as
+// used by Kubernetes secret volumes.
+func statFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{size: info.Size(), modTime: info.ModTime()}, nil
+}
+
+// certReloader serves a certificate loaded from files, reloading it when
+// the files change. A failed reload keeps the current certificate and is
+// retried at the next check, so half-written files are harmless.
+type certReloader struct {
+	certFile, keyFile string
+	logger            *slog.Logger
+
+	mu     sync.RWMutex
+	cert   *tls.Certificate
+	stamps [2]fileStamp // of certFile and keyFile when cert was loaded
+}
+
+// newCertReloader loads the certificate from certFile and keyFile.
+func newCertReloader(certFile, keyFile string, logger *slog.Logger) (*certReloader, error) {
+	c := &certReloader{certFile: certFile, keyFile: keyFile, logger: logger}
+	if _, err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// getCertificate returns the current certificate, for
+// [tls.Config.GetCertificate].
+func (c *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// reload loads the certificate if either file changed since it was last
+// loaded, reporting whether it did.
+func (c *certReloader) reload() (bool, error) {
+	certStamp, err := statFile(c.certFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read TLS certificate: %w", err)
+	}
+	keyStamp, err := statFile(c.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read TLS key: %w", err)
+	}
+	stamps := [2]fileStamp{certStamp, keyStamp}
+
+	c.mu.RLock()
+	unchanged := c.cert != nil && stamps == c.stamps
+	c.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	c.mu.Lock()
+	c.cert, c.stamps = &cert, stamps
+	c.mu.Unlock()
+	return true, nil
+}
+
+// watch reloads the certificate every interval until ctx is cancelled.
+func (c *certReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := c.reload()
+			if err != nil {
+				c.logger.Warn("failed to reload TLS certificate, keeping the current one", "error", err)
+			} else if reloaded {
+				c.logger.Info("TLS certificate reloaded", "cert_file", c.certFile)
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCert is a certificate and key issued for tests, either self-signed
+// or by another testCert acting as a CA.
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, cn string, issuer *testCert, isCA bool) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	parent, parentKey := tmpl, key
+	if issuer != nil {
+		parent, parentKey = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+// write saves the certificate and key as PEM files in dir, returning
+// their paths.
+func (c *testCert) write(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+// tlsKeyPair returns the certificate for use by a TLS client.
+func (c *testCert) tlsKeyPair() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+// freePort returns a port that was free when checked.
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// startTLS starts srv on a free port and returns its base URL.
+func startTLS(t *testing.T, srv *Server) string {
+	t.Helper()
+	srv.port = freePort(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := srv.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return fmt.Sprintf("https://127.0.0.1:%d", srv.port)
+}
+
+// tlsClient returns a client trusting ca, presenting cert if non-nil.
+func tlsClient(ca *testCert, cert *testCert) *http.Client {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	cfg := &tls.Config{RootCAs: pool}
+	if cert != nil {
+		cfg.Certificates = []tls.Certificate{cert.tlsKeyPair()}
+	}
+	return &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: cfg, ForceAttemptHTTP2: true},
+	}
+}
+
+func TestStart_TLS(t *testing.T) {
+	dir := t.TempDir()
+	cert := newTestCert(t, "pulseboard", nil, true)
+	certFile, keyFile := cert.write(t, dir, "server")
+
+	tests := []struct {
+		name      string
+		disable   bool
+		wantProto int
+	}{
+		{"http2 by default", false, 2},
+		{"http1 only", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer(newMockStore(), 0, nil, "", testLogger())
+			srv.EnableTLS(TLS{CertFile: certFile, KeyFile: keyFile, DisableHTTP2: tt.disable})
+			url := startTLS(t, srv)
+
+			resp, err := tlsClient(cert, nil).Get(url + "/api/status")
+			if err != nil {
+				t.Fatalf("GET over TLS error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if resp.ProtoMajor != tt.wantProto {
+				t.Errorf("proto = %s, want HTTP/%d", resp.Proto, tt.wantProto)
+			}
+		})
+	}
+}
+
+func TestStart_TLSClientCert(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "Test CA", nil, true)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := newTestCert(t, "pulseboard", ca, false).write(t, dir, "server")
+	client := newTestCert(t, "ci-bot", ca, false)
+	stranger := newTestCert(t, "stranger", nil, true)
+
+	t.Run("required", func(t *testing.T) {
+		srv := NewServer(newMockStore(), 0, nil, "", testLogger())
+		srv.EnableTLS(TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, RequireClientCert: true})
+		url := startTLS(t, srv)
+
+		if _, err := tlsClient(ca, nil).Get(url + "/api/status"); err == nil {
+			t.Error("GET without a client certificate should fail")
+		}
+		if _, err := tlsClient(ca, stranger).Get(url + "/api/status"); err == nil {
+			t.Error("GET with an untrusted client certificate should fail")
+		}
+		resp, err := tlsClient(ca, client).Get(url + "/api/status")
+		if err != nil {
+			t.Fatalf("GET with a client certificate error = %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("authenticates", func(t *testing.T) {
+		srv := NewServer(newMockStore(), 0, nil, "", testLogger())
+		srv.EnableTLS(TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+		srv.EnableAuth(Auth{Tokens: []string{"api-token"}})
+		url := startTLS(t, srv)
+
+		tests := []struct {
+			name     string
+			cert     *testCert
+			wantCode int
+		}{
+			{"no certificate", nil, http.StatusUnauthorized},
+			{"client certificate", client, http.StatusOK},
+		}
+		for _, tt := range tests {
+			resp, err := tlsClient(ca, tt.cert).Get(url + "/api/status")
+			if err != nil {
+				t.Fatalf("%s: GET error = %v", tt.name, err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("%s: status = %d, want %d", tt.name, resp.StatusCode, tt.wantCode)
+			}
+		}
+	})
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	first := newTestCert(t, "first", nil, true)
+	certFile, keyFile := first.write(t, dir, "server")
+
+	c, err := newCertReloader(certFile, keyFile, testLogger())
+	if err != nil {
+		t.Fatalf("newCertReloader() error = %v", err)
+	}
+	served := func() string {
+		cert, _ := c.getCertificate(nil)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return leaf.Subject.CommonName
+	}
+
+	if reloaded, err := c.reload(); err != nil || reloaded {
+		t.Errorf("reload() of unchanged files = %v, %v, want false, nil", reloaded, err)
+	}
+
+	// a half-written rotation keeps the current certificate
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.reload(); err == nil {
+		t.Error("reload() of an invalid certificate should fail")
+	}
+	if got := served(); got != "first" {
+		t.Errorf("served %q after a failed reload, want %q", got, "first")
+	}
+
+	newTestCert(t, "second", nil, true).write(t, dir, "server")
+	if reloaded, err := c.reload(); err != nil || !reloaded {
+		t.Errorf("reload() of rotated files = %v, %v, want true, nil", reloaded, err)
+	}
+	if got := served(); got != "second" {
+		t.Errorf("served %q after rotation, want %q", got, "second")
+	}
+}
+
+func TestValidateTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := newTestCert(t, "pulseboard", nil, true).write(t, dir, "server")
+	_, otherKey := newTestCert(t, "other", nil, true).write(t, dir, "other")
+
+	tests := []struct {
+		name    string
+		tls     TLS
+		wantErr bool
+	}{
+		{"valid", TLS{CertFile: certFile, KeyFile: keyFile}, false},
+		{"client CA", TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile, RequireClientCert: true}, false},
+		{"missing key", TLS{CertFile: certFile}, true},
+		{"mismatched key", TLS{CertFile: certFile, KeyFile: otherKey}, true},
+		{"missing file", TLS{CertFile: filepath.Join(dir, "nope.crt"), KeyFile: keyFile}, true},
+		{"client CA not PEM", TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile}, true},
+		{"require without CA", TLS{CertFile: certFile, KeyFile: keyFile, RequireClientCert: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTLS(tt.tls)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTLS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"maps"
+	"slices"
+)
+
+// LatencyBucketBounds are the upper bounds, in seconds, of the latency
+// histogram kept in [Counters].
+var LatencyBucketBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Counters are an endpoint's cumulative check counts since the store was
+// created, for export as metrics.
+type Counters struct {
+	// Checks counts results by published status.
+	Checks map[string]uint64
+
+	// LatencyBuckets counts the checks that got a response, slow and failed
+	// ones included, by latency: element i counts latencies up to LatencyBucketBounds[i], and
+	// the last element counts slower checks. Counts are not cumulative.
+	LatencyBuckets []uint64
+
+	// LatencyCount and LatencySum are the number and total latency, in
+	// seconds, of the checks counted in LatencyBuckets.
+	LatencyCount uint64
+	LatencySum   float64
+}
+
+// newCounters creates empty counters.
+func newCounters() *Counters {
+	return &Counters{
+		Checks:         make(map[string]uint64),
+		LatencyBuckets: make([]uint64, len(LatencyBucketBounds)+1),
+	}
+}
+
+// record counts a result.
+func (c *Counters) record(result StatusResult) {
+	c.Checks[result.Status]++
+	if result.NoResponse {
+		return
+	}
+	seconds := float64(result.ResponseTimeMs) / 1000
+	i, _ := slices.BinarySearch(LatencyBucketBounds, seconds)
+	c.LatencyBuckets[i]++
+	c.LatencyCount++
+	c.LatencySum += seconds
+}
+
+// clone returns a deep copy of c.
+func (c *Counters) clone() Counters {
+	return Counters{
+		Checks:         maps.Clone(c.Checks),
+		LatencyBuckets: slices.Clone(c.LatencyBuckets),
+		LatencyCount:   c.LatencyCount,
+		LatencySum:     c.LatencySum,
+	}
+}
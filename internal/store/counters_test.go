@@ -0,0 +1,62 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Counters(t *testing.T) {
+	store := NewMemoryStore()
+	errMsg := "timeout"
+	slow := "latency 2s > 1s"
+
+	store.Update(StatusResult{Name: "API", Status: "up", ResponseTimeMs: 3})
+	store.Update(StatusResult{Name: "API", Status: "up", ResponseTimeMs: 300})
+	store.Update(StatusResult{Name: "API", Status: "degraded", ResponseTimeMs: 2000, Error: &slow})
+	store.Update(StatusResult{Name: "API", Status: "down", ResponseTimeMs: 30000, Error: &errMsg, NoResponse: true})
+
+	c := store.Counters()["API"]
+	if c.Checks["up"] != 2 || c.Checks["degraded"] != 1 || c.Checks["down"] != 1 {
+		t.Errorf("Checks = %v, want 2 up, 1 degraded and 1 down", c.Checks)
+	}
+	// 3ms in the first bucket, 300ms in the 0.5s bucket and the slow response
+	// in the 2.5s bucket; the check without a response is not timed
+	if c.LatencyCount != 3 || c.LatencyBuckets[0] != 1 || c.LatencyBuckets[6] != 1 || c.LatencyBuckets[8] != 1 {
+		t.Errorf("latency = %+v, want 3 checks in the 5ms, 0.5s and 2.5s buckets", c)
+	}
+	if c.LatencySum < 2.302 || c.LatencySum > 2.304 {
+		t.Errorf("LatencySum = %v, want 2.303", c.LatencySum)
+	}
+
+	store.Delete("API")
+	if _, ok := store.Counters()["API"]; ok {
+		t.Error("Counters() after Delete still has API")
+	}
+}
+
+func TestMemoryStore_DroppedUpdates(t *testing.T) {
+	store := NewMemoryStore()
+	ch := store.Subscribe()
+	defer store.Unsubscribe(ch)
+
+	for i := 0; i < cap(ch)+5; i++ {
+		store.Update(StatusResult{Name: "API", Status: "up"})
+	}
+	if got := store.DroppedUpdates(); got != 5 {
+		t.Errorf("DroppedUpdates() = %d, want 5", got)
+	}
+}
+
+func TestFileStore_CountersStartEmpty(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestFileStore(t, dir, FileOptions{})
+	s.Update(StatusResult{Name: "API", Status: "up", CheckedAt: time.Now()})
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened := openTestFileStore(t, dir, FileOptions{})
+	if got := reopened.Counters()["API"]; len(got.Checks) != 0 {
+		t.Errorf("Counters() after replay = %+v, want replayed results not counted", got)
+	}
+}
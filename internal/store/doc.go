@@ -9,10 +9,15 @@
 //   - [Store]: Interface defining storage and subscription operations
 //   - [MemoryStore]: In-memory implementation of Store with pub/sub
 //   - [StatusResult]: Storage representation of an endpoint's status
+//   - [Sample]: A historical poll result, bounded by [HistoryLimits]
+//   - [Uptime]: Availability over a rolling window, per [UptimeOptions]
+//   - [LatencyStats]: Latency percentiles over a rolling window
+//   - [Incident]: A period during which an endpoint was not up
 //
 // The store is designed for concurrent access with proper synchronization.
 // Subscribers receive updates via channels with non-blocking sends (slow
-// subscribers will miss updates rather than block the system).
+// subscribers will miss updates rather than block the system, though never
+// an endpoint's removal).
 //
 // Users of the pulseboard library should not need to interact with this
 // package directly. Storage is managed internally by PulseBoard.
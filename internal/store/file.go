@@ -0,0 +1,405 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSegmentBytes is the size at which the active log segment is sealed.
+	DefaultSegmentBytes = 4 << 20
+
+	// DefaultCompactSegments is the number of sealed segments that triggers
+	// compaction.
+	DefaultCompactSegments = 4
+
+	segmentPrefix  = "segment-"
+	snapshotPrefix = "snapshot-"
+	logSuffix      = ".log"
+)
+
+// FileOptions configures a [FileStore].
+type FileOptions struct {
+	// History bounds the history kept per endpoint, in memory and on disk.
+	History HistoryLimits
+
+	// Uptime configures uptime computation; see [WithUptime].
+	Uptime UptimeOptions
+
+	// LatencyWindows are the latency percentile windows; see
+	// [WithLatencyWindows].
+	LatencyWindows []time.Duration
+
+	// IncidentLimit is the number of closed incidents kept; see
+	// [WithIncidentLimit].
+	IncidentLimit int
+
+	// SegmentBytes is the size at which the active segment is sealed and a
+	// new one started. Defaults to [DefaultSegmentBytes].
+	SegmentBytes int64
+
+	// CompactSegments is the number of sealed segments at which they are
+	// compacted into a snapshot. Defaults to [DefaultCompactSegments].
+	CompactSegments int
+}
+
+// record is a single line of a segment or snapshot.
+type record struct {
+	// Op is "update", "delete" or, in snapshots, "uptime", "latency" or
+	// "incidents".
+	Op string `json:"op"`
+
+	// Result is the stored result of an update.
+	Result *StatusResult `json:"result,omitempty"`
+
+	// Name is the endpoint removed by a delete or whose ledger is restored.
+	Name string `json:"name,omitempty"`
+
+	// Ledger is the uptime ledger restored by an uptime record.
+	Ledger *ledger `json:"ledger,omitempty"`
+
+	// Latency holds the histograms restored by a latency record.
+	Latency *latencyTracker `json:"latency,omitempty"`
+
+	// Incidents are restored by an incidents record.
+	Incidents []Incident `json:"incidents,omitempty"`
+}
+
+// FileStore is a [Store] that persists results to an append-only log so
+// status and history survive restarts.
+//
+// Every Update and Delete is applied to an embedded [MemoryStore], which
+// serves all reads, and appended as a JSON line to the active segment in the
+// store's directory. Segments are sealed once they
+// reach FileOptions.SegmentBytes. When FileOptions.CompactSegments sealed
+// segments have accumulated, the current state is written to a snapshot
+// and the segments it covers are deleted, so disk use stays proportional to
+// the retained history.
+//
+// On open the latest snapshot and the segments after it are replayed, with
+// history limits applied as results are loaded.
+type FileStore struct {
+	*MemoryStore
+
+	dir    string
+	opts   FileOptions
+	logger *slog.Logger
+
+	// mu serialises writes to the log and compaction
+	mu          sync.Mutex
+	active      *os.File
+	activeSeq   int
+	activeSize  int64
+	snapshotSeq int // segments up to and including this are in the snapshot
+}
+
+// OpenFileStore opens or creates a FileStore in dir and replays its contents.
+//
+// Write errors after opening are logged rather than returned; the in-memory
+// state stays authoritative. Call [FileStore.Close] when done.
+func OpenFileStore(dir string, opts FileOptions, logger *slog.Logger) (*FileStore, error) {
+	if opts.SegmentBytes <= 0 {
+		opts.SegmentBytes = DefaultSegmentBytes
+	}
+	if opts.CompactSegments <= 0 {
+		opts.CompactSegments = DefaultCompactSegments
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	s := &FileStore{
+		MemoryStore: NewMemoryStore(WithHistoryLimits(opts.History), WithUptime(opts.Uptime), WithLatencyWindows(opts.LatencyWindows), WithIncidentLimit(opts.IncidentLimit)),
+		dir:         dir,
+		opts:        opts,
+		logger:      logger,
+	}
+
+	lastSeq, err := s.replay()
+	if err != nil {
+		return nil, err
+	}
+	s.MemoryStore.resetCounters()
+
+	// always start a fresh segment so a torn write at the end of the last
+	// one is never appended to
+	if err := s.openSegment(lastSeq + 1); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update stores result and appends it to the log.
+func (s *FileStore) Update(result StatusResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// apply first so a compaction triggered by the append includes it
+	s.MemoryStore.Update(result)
+	result.Uptime, result.Latency = nil, nil // derived; rebuilt on replay
+	s.append(record{Op: "update", Result: &result})
+}
+
+// Delete removes the named result and appends the deletion to the log.
+func (s *FileStore) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.MemoryStore.Delete(name)
+	s.append(record{Op: "delete", Name: name})
+}
+
+// Close syncs and closes the active segment.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active == nil {
+		return nil
+	}
+	err := s.active.Sync()
+	if cerr := s.active.Close(); err == nil {
+		err = cerr
+	}
+	s.active = nil
+	return err
+}
+
+// append writes rec to the active segment, sealing it and compacting as
+// needed. Must be called with mu held.
+func (s *FileStore) append(rec record) {
+	if s.active == nil {
+		return
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		s.logger.Error("failed to encode store record", "error", err)
+		return
+	}
+	line = append(line, '\n')
+
+	n, err := s.active.Write(line)
+	s.activeSize += int64(n)
+	if err != nil {
+		s.logger.Error("failed to write store record", "segment", s.active.Name(), "error", err)
+		return
+	}
+
+	if s.activeSize >= s.opts.SegmentBytes {
+		if err := s.roll(); err != nil {
+			s.logger.Error("failed to roll store segment", "error", err)
+		}
+	}
+}
+
+// roll seals the active segment and starts the next one, compacting if
+// enough sealed segments have accumulated. Must be called with mu held.
+func (s *FileStore) roll() error {
+	sealed := s.activeSeq
+	if err := s.active.Close(); err != nil {
+		return err
+	}
+	s.active = nil
+	if err := s.openSegment(sealed + 1); err != nil {
+		return err
+	}
+
+	if sealed-s.snapshotSeq >= s.opts.CompactSegments {
+		return s.compact(sealed)
+	}
+	return nil
+}
+
+// compact writes the current state as a snapshot covering every segment up
+// to and including seq, then deletes those segments and older snapshots.
+// Must be called with mu held, before anything is written after seq.
+func (s *FileStore) compact(seq int) error {
+	final := filepath.Join(s.dir, fileName(snapshotPrefix, seq))
+	tmp := final + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, rec := range s.MemoryStore.snapshot() {
+		if err := enc.Encode(rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		return err
+	}
+
+	s.snapshotSeq = seq
+	return s.removeCovered()
+}
+
+// replay loads the latest snapshot and the segments after it, removing any
+// files the snapshot covers. It returns the highest sequence number seen.
+func (s *FileStore) replay() (int, error) {
+	segments, snapshots, err := s.list()
+	if err != nil {
+		return 0, err
+	}
+
+	lastSeq := 0
+	if len(snapshots) > 0 {
+		s.snapshotSeq = snapshots[len(snapshots)-1]
+		lastSeq = s.snapshotSeq
+		if err := s.load(fileName(snapshotPrefix, s.snapshotSeq)); err != nil {
+			return 0, err
+		}
+	}
+	for _, seq := range segments {
+		if seq <= s.snapshotSeq {
+			continue
+		}
+		if err := s.load(fileName(segmentPrefix, seq)); err != nil {
+			return 0, err
+		}
+		lastSeq = seq
+	}
+
+	return lastSeq, s.removeCovered()
+}
+
+// load applies the records in the named file to the memory store. Lines
+// that cannot be decoded, such as a write torn by a crash, are skipped.
+func (s *FileStore) load(name string) error {
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	skipped := 0
+	for scanner.Scan() {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			skipped++
+			continue
+		}
+		switch {
+		case rec.Op == "update" && rec.Result != nil:
+			s.MemoryStore.Update(*rec.Result)
+		case rec.Op == "delete":
+			s.MemoryStore.Delete(rec.Name)
+		case rec.Op == "uptime" && rec.Ledger != nil:
+			s.MemoryStore.restoreLedger(rec.Name, rec.Ledger)
+		case rec.Op == "latency" && rec.Latency != nil:
+			s.MemoryStore.restoreLatency(rec.Name, rec.Latency)
+		case rec.Op == "incidents":
+			s.MemoryStore.restoreIncidents(rec.Incidents)
+		default:
+			skipped++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if skipped > 0 {
+		s.logger.Warn("skipped unreadable store records", "file", name, "count", skipped)
+	}
+	return nil
+}
+
+// openSegment creates segment seq and makes it the active segment.
+func (s *FileStore) openSegment(seq int) error {
+	f, err := os.OpenFile(filepath.Join(s.dir, fileName(segmentPrefix, seq)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open store segment: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.active, s.activeSeq, s.activeSize = f, seq, info.Size()
+	return nil
+}
+
+// removeCovered deletes segments covered by the current snapshot, older
+// snapshots and leftover temporary files.
+func (s *FileStore) removeCovered() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		remove := strings.HasSuffix(name, ".tmp")
+		if seq, ok := parseFileName(segmentPrefix, name); ok && seq <= s.snapshotSeq {
+			remove = true
+		}
+		if seq, ok := parseFileName(snapshotPrefix, name); ok && seq < s.snapshotSeq {
+			remove = true
+		}
+		if remove {
+			if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// list returns the sequence numbers of the segments and snapshots in the
+// store directory, in ascending order.
+func (s *FileStore) list() (segments, snapshots []int, err error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read store directory: %w", err)
+	}
+	for _, e := range entries {
+		if seq, ok := parseFileName(segmentPrefix, e.Name()); ok {
+			segments = append(segments, seq)
+		}
+		if seq, ok := parseFileName(snapshotPrefix, e.Name()); ok {
+			snapshots = append(snapshots, seq)
+		}
+	}
+	sort.Ints(segments)
+	sort.Ints(snapshots)
+	return segments, snapshots, nil
+}
+
+// fileName returns the name of the segment or snapshot with the given prefix
+// and sequence number.
+func fileName(prefix string, seq int) string {
+	return fmt.Sprintf("%s%08d%s", prefix, seq, logSuffix)
+}
+
+// parseFileName returns the sequence number of a file named by [fileName].
+func parseFileName(prefix, name string) (int, bool) {
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, logSuffix) {
+		return 0, false
+	}
+	seq, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), logSuffix))
+	return seq, err == nil && seq > 0
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func openTestFileStore(t *testing.T, dir string, opts FileOptions) *FileStore {
+	t.Helper()
+	if opts.History.Samples == 0 {
+		opts.History = HistoryLimits{Samples: 100}
+	}
+	s, err := OpenFileStore(dir, opts, discardLogger())
+	if err != nil {
+		t.Fatalf("OpenFileStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// storeFiles returns the names of the segments and snapshots in dir.
+func storeFiles(t *testing.T, dir string) (segments, snapshots []string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, e := range entries {
+		switch {
+		case strings.HasPrefix(e.Name(), segmentPrefix):
+			segments = append(segments, e.Name())
+		case strings.HasPrefix(e.Name(), snapshotPrefix):
+			snapshots = append(snapshots, e.Name())
+		}
+	}
+	return segments, snapshots
+}
+
+func TestFileStore_ReplaysAfterReopen(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	errMsg := "connection refused"
+
+	s := openTestFileStore(t, dir, FileOptions{})
+	s.Update(StatusResult{Name: "API", URL: "https://api.example.com", Status: "up", CheckedAt: base})
+	s.Update(StatusResult{Name: "API", URL: "https://api.example.com", Status: "down", CheckedAt: base.Add(time.Minute), Error: &errMsg, Labels: map[string]string{"env": "prod"}})
+	s.Update(StatusResult{Name: "Gone", Status: "up", CheckedAt: base})
+	s.Delete("Gone")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened := openTestFileStore(t, dir, FileOptions{})
+	all := reopened.GetAll()
+	if len(all) != 1 {
+		t.Fatalf("GetAll() = %+v, want only API", all)
+	}
+	got := all[0]
+	if got.Name != "API" || got.Status != "down" || got.Error == nil || *got.Error != errMsg || got.Labels["env"] != "prod" {
+		t.Errorf("latest = %+v, want the down result with error and labels", got)
+	}
+	if history := statusesOf(reopened.History("API", time.Time{})); len(history) != 2 || history[0] != "up" {
+		t.Errorf("History() = %v, want [up down]", history)
+	}
+	if reopened.History("Gone", time.Time{}) != nil {
+		t.Error("deleted endpoint should have no history after replay")
+	}
+}
+
+func TestFileStore_Compaction(t *testing.T) {
+	dir := t.TempDir()
+	opts := FileOptions{
+		History:         HistoryLimits{Samples: 5},
+		SegmentBytes:    256,
+		CompactSegments: 2,
+	}
+	base := time.Now().Add(-time.Hour)
+
+	s := openTestFileStore(t, dir, opts)
+	for i := 0; i < 100; i++ {
+		status := "up"
+		if i%2 == 1 {
+			status = "down"
+		}
+		s.Update(StatusResult{Name: "API", Status: status, CheckedAt: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	segments, snapshots := storeFiles(t, dir)
+	if len(snapshots) != 1 {
+		t.Errorf("snapshots = %v, want exactly one", snapshots)
+	}
+	if len(segments) > opts.CompactSegments+1 {
+		t.Errorf("segments = %v, want at most %d after compaction", segments, opts.CompactSegments+1)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened := openTestFileStore(t, dir, opts)
+	history := reopened.History("API", time.Time{})
+	if len(history) != 5 {
+		t.Fatalf("History() = %d samples, want 5", len(history))
+	}
+	if last := history[4]; last.Status != "down" || !last.CheckedAt.Equal(base.Add(99*time.Second)) {
+		t.Errorf("last sample = %+v, want the 100th result", last)
+	}
+	// uptime covers every result, not just the retained history
+	uptime := reopened.Uptime("API", []time.Duration{24 * time.Hour})
+	if checks := uptime[0].Checks; checks["up"] != 50 || checks["down"] != 50 {
+		t.Errorf("Uptime() checks = %v, want 50 up and 50 down", checks)
+	}
+	if latency := reopened.Latency("API"); latency[len(latency)-1].Count != 100 {
+		t.Errorf("Latency() = %+v, want all 100 checks", latency)
+	}
+	if incidents := reopened.Incidents(); len(incidents) != 50 || !incidents[0].Open() {
+		t.Errorf("Incidents() = %d, want 50 with the latest open", len(incidents))
+	}
+}
+
+func TestFileStore_SkipsTornWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openTestFileStore(t, dir, FileOptions{})
+	s.Update(StatusResult{Name: "API", Status: "up", CheckedAt: time.Now()})
+	segment := s.active.Name()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// simulate a crash part-way through writing a record
+	f, err := os.OpenFile(segment, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	_, _ = f.WriteString(`{"op":"update","result":{"name":"API","sta`)
+	_ = f.Close()
+
+	reopened := openTestFileStore(t, dir, FileOptions{})
+	if all := reopened.GetAll(); len(all) != 1 || all[0].Status != "up" {
+		t.Errorf("GetAll() = %+v, want API up", all)
+	}
+
+	// new writes go to a fresh segment, not after the torn record
+	if reopened.active.Name() == segment {
+		t.Error("reopened store should not append to the torn segment")
+	}
+}
+
+func TestFileStore_RetentionOnReplay(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	s := openTestFileStore(t, dir, FileOptions{History: HistoryLimits{Samples: 100}})
+	s.Update(StatusResult{Name: "API", Status: "down", CheckedAt: now.Add(-3 * time.Hour)})
+	s.Update(StatusResult{Name: "API", Status: "up", CheckedAt: now.Add(-time.Minute)})
+	_ = s.Close()
+
+	reopened := openTestFileStore(t, dir, FileOptions{History: HistoryLimits{Samples: 100, MaxAge: time.Hour}})
+	if history := statusesOf(reopened.History("API", time.Time{})); len(history) != 1 || history[0] != "up" {
+		t.Errorf("History() = %v, want [up]", history)
+	}
+}
+
+func TestParseFileName(t *testing.T) {
+	if seq, ok := parseFileName(segmentPrefix, fileName(segmentPrefix, 42)); !ok || seq != 42 {
+		t.Errorf("parseFileName(fileName(42)) = %d, %v", seq, ok)
+	}
+	for _, name := range []string{"segment-abc.log", "snapshot-00000001.log", "segment-00000001.log.tmp", filepath.Join("x", "y")} {
+		if _, ok := parseFileName(segmentPrefix, name); ok {
+			t.Errorf("parseFileName(%q) should not match", name)
+		}
+	}
+}
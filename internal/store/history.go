@@ -0,0 +1,101 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultHistorySamples is the default number of samples kept per endpoint.
+	DefaultHistorySamples = 1000
+
+	// DefaultHistoryAge is the default maximum age of a kept sample.
+	DefaultHistoryAge = 24 * time.Hour
+)
+
+// Sample is a single historical poll result.
+type Sample struct {
+	// CheckedAt is when the poll completed.
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Status is the published health status.
+	Status string `json:"status"`
+
+	// ResponseTimeMs is the request latency in milliseconds.
+	ResponseTimeMs int64 `json:"response_time_ms"`
+
+	// Error contains the error message if the poll failed.
+	Error *string `json:"error"`
+}
+
+// HistoryLimits bounds the history kept per endpoint.
+type HistoryLimits struct {
+	// Samples is the maximum number of samples kept. Must be positive.
+	Samples int
+
+	// MaxAge is how long samples are kept. Zero keeps samples until they are
+	// displaced by newer ones.
+	MaxAge time.Duration
+}
+
+// sampleOf returns the history sample for a status result.
+func sampleOf(r StatusResult) Sample {
+	return Sample{
+		CheckedAt:      r.CheckedAt,
+		Status:         r.Status,
+		ResponseTimeMs: r.ResponseTimeMs,
+		Error:          r.Error,
+	}
+}
+
+// ring is a fixed-capacity buffer of samples in insertion order. Once full,
+// each push overwrites the oldest sample.
+type ring struct {
+	buf   []Sample
+	start int // index of the oldest sample
+	n     int // number of samples held
+}
+
+// newRing creates a ring holding at most capacity samples.
+func newRing(capacity int) *ring {
+	return &ring{buf: make([]Sample, capacity)}
+}
+
+// push appends s, overwriting the oldest sample if the ring is full.
+func (r *ring) push(s Sample) {
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = s
+		r.n++
+		return
+	}
+	r.buf[r.start] = s
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// at returns the i-th oldest sample.
+func (r *ring) at(i int) Sample {
+	return r.buf[(r.start+i)%len(r.buf)]
+}
+
+// dropBefore discards samples checked before cutoff. Samples are assumed to
+// be pushed in time order.
+func (r *ring) dropBefore(cutoff time.Time) {
+	for r.n > 0 && r.buf[r.start].CheckedAt.Before(cutoff) {
+		r.buf[r.start] = Sample{}
+		r.start = (r.start + 1) % len(r.buf)
+		r.n--
+	}
+}
+
+// since returns a copy of the samples checked at or after since, oldest
+// first.
+func (r *ring) since(since time.Time) []Sample {
+	first := sort.Search(r.n, func(i int) bool {
+		return !r.at(i).CheckedAt.Before(since)
+	})
+	out := make([]Sample, 0, r.n-first)
+	for i := first; i < r.n; i++ {
+		out = append(out, r.at(i))
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+// statusesOf returns the statuses of samples in order.
+func statusesOf(samples []Sample) []string {
+	out := make([]string, len(samples))
+	for i, s := range samples {
+		out[i] = s.Status
+	}
+	return out
+}
+
+func TestRing_OverwritesOldest(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newRing(3)
+	for i, status := range []string{"a", "b", "c", "d", "e"} {
+		r.push(Sample{Status: status, CheckedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+
+	got := statusesOf(r.since(time.Time{}))
+	want := []string{"c", "d", "e"}
+	if len(got) != len(want) {
+		t.Fatalf("since() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("since() = %v, want %v", got, want)
+		}
+	}
+
+	if got := statusesOf(r.since(base.Add(3 * time.Minute))); len(got) != 2 || got[0] != "d" {
+		t.Errorf("since(3m) = %v, want [d e]", got)
+	}
+}
+
+func TestRing_DropBefore(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newRing(5)
+	for i := 0; i < 4; i++ {
+		r.push(Sample{Status: "up", CheckedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+
+	r.dropBefore(base.Add(2 * time.Minute))
+	if got := r.since(time.Time{}); len(got) != 2 || !got[0].CheckedAt.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("after dropBefore(2m) samples = %v, want the last 2", got)
+	}
+
+	// the ring keeps working after wrapping past dropped slots
+	for i := 4; i < 9; i++ {
+		r.push(Sample{Status: "down", CheckedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+	if got := r.since(time.Time{}); len(got) != 5 || got[0].Status != "down" {
+		t.Errorf("samples = %v, want 5 down samples", statusesOf(got))
+	}
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultIncidentLimit is the number of closed incidents kept.
+const DefaultIncidentLimit = 1000
+
+// Incident is a period during which an endpoint was not up. It opens when
+// an endpoint's status leaves up, or when its first result is not up, and
+// closes when it is up again.
+type Incident struct {
+	// Name is the endpoint's name.
+	Name string `json:"name"`
+
+	// Labels are the endpoint's labels when the incident opened.
+	Labels map[string]string `json:"labels"`
+
+	// StartedAt is when the first result that was not up was checked.
+	StartedAt time.Time `json:"started_at"`
+
+	// EndedAt is when the endpoint was next up, or nil while the incident is
+	// open. An incident whose endpoint is deleted ends at its last check.
+	EndedAt *time.Time `json:"ended_at"`
+
+	// DurationSeconds is the incident's length so far, up to now while it
+	// is open.
+	DurationSeconds float64 `json:"duration_seconds"`
+
+	// WorstStatus is the most severe status seen: "down", then "unknown",
+	// then "degraded".
+	WorstStatus string `json:"worst_status"`
+
+	// FirstError is the first error reported during the incident.
+	FirstError *string `json:"first_error"`
+}
+
+// Open reports whether the incident is still open.
+func (i Incident) Open() bool {
+	return i.EndedAt == nil
+}
+
+// severity ranks statuses other than up by how bad they are.
+func severity(status string) int {
+	switch status {
+	case "down":
+		return 3
+	case "unknown":
+		return 2
+	case "degraded":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// incidents tracks the open incident of each endpoint and a bounded list of
+// closed incidents, oldest first.
+type incidents struct {
+	open   map[string]*Incident
+	closed []Incident
+	limit  int
+}
+
+func newIncidents(limit int) *incidents {
+	return &incidents{open: make(map[string]*Incident), limit: limit}
+}
+
+// record applies a result to the endpoint's incident, opening, updating or
+// closing it.
+func (t *incidents) record(result StatusResult) {
+	inc := t.open[result.Name]
+	if result.Status == "up" {
+		if inc != nil {
+			t.close(result.Name, result.CheckedAt)
+		}
+		return
+	}
+
+	if inc == nil {
+		inc = &Incident{
+			Name:        result.Name,
+			Labels:      maps.Clone(result.Labels),
+			StartedAt:   result.CheckedAt,
+			WorstStatus: result.Status,
+		}
+		t.open[result.Name] = inc
+	}
+	if severity(result.Status) > severity(inc.WorstStatus) {
+		inc.WorstStatus = result.Status
+	}
+	if inc.FirstError == nil && result.Error != nil {
+		msg := *result.Error
+		inc.FirstError = &msg
+	}
+}
+
+// close ends the named endpoint's open incident, if any, at the given time.
+func (t *incidents) close(name string, at time.Time) {
+	inc := t.open[name]
+	if inc == nil {
+		return
+	}
+	delete(t.open, name)
+	inc.EndedAt = &at
+	t.closed = append(t.closed, *inc)
+	if over := len(t.closed) - t.limit; over > 0 {
+		t.closed = append(t.closed[:0:0], t.closed[over:]...)
+	}
+}
+
+// all returns the closed incidents, oldest first, followed by the open
+// ones; restore rebuilds the tracker from such a list.
+func (t *incidents) all() []Incident {
+	out := slices.Clone(t.closed)
+	for _, inc := range t.open {
+		out = append(out, *inc)
+	}
+	return out
+}
+
+// restore replaces the tracked incidents with list, as returned by all.
+// Open incidents of endpoints for which keep returns false are dropped.
+func (t *incidents) restore(list []Incident, keep func(name string) bool) {
+	t.open = make(map[string]*Incident)
+	t.closed = nil
+	for _, inc := range list {
+		if inc.EndedAt != nil {
+			t.closed = append(t.closed, inc)
+		} else if keep(inc.Name) {
+			t.open[inc.Name] = &inc
+		}
+	}
+	if over := len(t.closed) - t.limit; over > 0 {
+		t.closed = t.closed[over:]
+	}
+}
+
+// list returns copies of all incidents, newest first, with durations
+// computed as of now.
+func (t *incidents) list(now time.Time) []Incident {
+	out := make([]Incident, 0, len(t.open)+len(t.closed))
+	for _, inc := range t.open {
+		out = append(out, *inc)
+	}
+	for i := len(t.closed) - 1; i >= 0; i-- {
+		out = append(out, t.closed[i])
+	}
+	for i := range out {
+		end := now
+		if out[i].EndedAt != nil {
+			end = *out[i].EndedAt
+		}
+		out[i].DurationSeconds = end.Sub(out[i].StartedAt).Seconds()
+		out[i].Labels = maps.Clone(out[i].Labels)
+	}
+	sortIncidents(out)
+	return out
+}
+
+// sortIncidents orders incidents newest first, breaking ties by name.
+func sortIncidents(list []Incident) {
+	slices.SortStableFunc(list, func(a, b Incident) int {
+		if c := b.StartedAt.Compare(a.StartedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Incidents(t *testing.T) {
+	store := NewMemoryStore()
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeout, refused := "timeout", "connection refused"
+
+	for _, r := range []StatusResult{
+		{Name: "API", Status: "up", CheckedAt: base},
+		{Name: "API", Status: "degraded", CheckedAt: base.Add(time.Minute)},
+		{Name: "API", Status: "down", CheckedAt: base.Add(2 * time.Minute), Error: &timeout},
+		{Name: "API", Status: "down", CheckedAt: base.Add(3 * time.Minute), Error: &refused},
+		{Name: "API", Status: "up", CheckedAt: base.Add(5 * time.Minute)},
+		{Name: "DB", Status: "down", CheckedAt: base.Add(4 * time.Minute), Labels: map[string]string{"tier": "data"}},
+	} {
+		store.Update(r)
+	}
+
+	list := store.Incidents()
+	if len(list) != 2 {
+		t.Fatalf("Incidents() = %+v, want 2", list)
+	}
+
+	// newest first: DB opened at 4m, API at 1m
+	db, api := list[0], list[1]
+	if db.Name != "DB" || !db.Open() || db.Labels["tier"] != "data" || db.WorstStatus != "down" {
+		t.Errorf("DB incident = %+v, want open down incident with labels", db)
+	}
+	if db.DurationSeconds <= 0 {
+		t.Errorf("open incident duration = %v, want time since start", db.DurationSeconds)
+	}
+	if api.Name != "API" || api.Open() || !api.StartedAt.Equal(base.Add(time.Minute)) || !api.EndedAt.Equal(base.Add(5*time.Minute)) {
+		t.Errorf("API incident = %+v, want closed from 1m to 5m", api)
+	}
+	if api.DurationSeconds != 240 || api.WorstStatus != "down" || api.FirstError == nil || *api.FirstError != timeout {
+		t.Errorf("API incident = %+v, want 240s, worst down, first error timeout", api)
+	}
+
+	// deleting an endpoint closes its incident at its last check
+	store.Delete("DB")
+	list = store.Incidents()
+	if list[0].Name != "DB" || list[0].Open() || !list[0].EndedAt.Equal(base.Add(4*time.Minute)) {
+		t.Errorf("DB incident after Delete = %+v, want closed at its last check", list[0])
+	}
+}
+
+func TestMemoryStore_IncidentLimit(t *testing.T) {
+	store := NewMemoryStore(WithIncidentLimit(2))
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		at := base.Add(time.Duration(i) * time.Hour)
+		store.Update(StatusResult{Name: "API", Status: "down", CheckedAt: at})
+		store.Update(StatusResult{Name: "API", Status: "up", CheckedAt: at.Add(time.Minute)})
+	}
+
+	list := store.Incidents()
+	if len(list) != 2 || !list[0].StartedAt.Equal(base.Add(4*time.Hour)) {
+		t.Errorf("Incidents() = %+v, want the 2 newest", list)
+	}
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// latencyBuckets is the number of histogram buckets. Bucket i holds
+	// latencies up to 2^(i/4) ms, so bucket bounds grow by about 19% and
+	// the last bucket, which also holds anything slower, starts at about
+	// 3.5 minutes.
+	latencyBuckets = 72
+
+	// latencySlots is the number of sub-histograms per window. The window
+	// slides in steps of 1/latencySlots of its length, and each window
+	// takes under 2 KiB per endpoint.
+	latencySlots = 6
+)
+
+// DefaultLatencyWindows are the windows over which latency percentiles are
+// reported.
+var DefaultLatencyWindows = []time.Duration{time.Hour, 24 * time.Hour}
+
+// LatencyStats summarises an endpoint's response times over a rolling window.
+//
+// Percentiles are estimated from a histogram and are accurate to within
+// about 19%; Max is exact. Every check that got a response is counted,
+// including slow and failed ones; checks without a response are not.
+type LatencyStats struct {
+	// Window is the window length, such as "1h" or "7d".
+	Window string `json:"window"`
+
+	// Count is the number of checks in the window.
+	Count int `json:"count"`
+
+	// P50, P90 and P99 are latency percentiles in milliseconds.
+	P50 int64 `json:"p50_ms"`
+	P90 int64 `json:"p90_ms"`
+	P99 int64 `json:"p99_ms"`
+
+	// Max is the slowest check in milliseconds.
+	Max int64 `json:"max_ms"`
+}
+
+// latencySlot is a histogram of the checks in one slot of a window.
+type latencySlot struct {
+	// Epoch identifies the slot's time range; slots from older epochs are
+	// stale and reset before reuse.
+	Epoch  int64                  `json:"epoch"`
+	Counts [latencyBuckets]uint32 `json:"counts"`
+	Max    int64                  `json:"max"`
+}
+
+// latencyWindow is a ring of slot histograms covering one window, so its
+// memory use is fixed regardless of how many checks it sees.
+type latencyWindow struct {
+	Length time.Duration             `json:"length"`
+	Slots  [latencySlots]latencySlot `json:"slots"`
+}
+
+// latencyTracker records an endpoint's latencies over each window.
+type latencyTracker struct {
+	Windows []latencyWindow `json:"windows"`
+}
+
+// newLatencyTracker creates a tracker for the given windows.
+func newLatencyTracker(windows []time.Duration) *latencyTracker {
+	t := &latencyTracker{Windows: make([]latencyWindow, len(windows))}
+	for i, w := range windows {
+		t.Windows[i].Length = w
+	}
+	return t
+}
+
+// record adds a check that took ms milliseconds at the given time.
+func (t *latencyTracker) record(ms int64, at time.Time) {
+	bucket := latencyBucket(ms)
+	for i := range t.Windows {
+		w := &t.Windows[i]
+		epoch := w.epoch(at)
+		slot := &w.Slots[(epoch%latencySlots+latencySlots)%latencySlots]
+		if slot.Epoch != epoch {
+			*slot = latencySlot{Epoch: epoch}
+		}
+		slot.Counts[bucket]++
+		slot.Max = max(slot.Max, ms)
+	}
+}
+
+// stats summarises each window as of now.
+func (t *latencyTracker) stats(now time.Time) []LatencyStats {
+	out := make([]LatencyStats, len(t.Windows))
+	for i := range t.Windows {
+		out[i] = t.Windows[i].stats(now)
+	}
+	return out
+}
+
+// epoch returns the index of the slot-sized interval containing at.
+func (w *latencyWindow) epoch(at time.Time) int64 {
+	slotLen := int64(w.Length / latencySlots)
+	if slotLen <= 0 {
+		slotLen = 1
+	}
+	return at.UnixNano() / slotLen
+}
+
+// stats merges the slots that fall within the window ending at now.
+func (w *latencyWindow) stats(now time.Time) LatencyStats {
+	current := w.epoch(now)
+	var merged [latencyBuckets]uint64
+	var total uint64
+	s := LatencyStats{Window: FormatWindow(w.Length)}
+	for _, slot := range w.Slots {
+		if slot.Epoch <= current-latencySlots || slot.Epoch > current {
+			continue
+		}
+		for b, c := range slot.Counts {
+			merged[b] += uint64(c)
+			total += uint64(c)
+		}
+		s.Max = max(s.Max, slot.Max)
+	}
+	if total == 0 {
+		return s
+	}
+	s.Count = int(total)
+	s.P50 = percentile(merged, total, 0.50, s.Max)
+	s.P90 = percentile(merged, total, 0.90, s.Max)
+	s.P99 = percentile(merged, total, 0.99, s.Max)
+	return s
+}
+
+// percentile returns the upper bound of the bucket holding quantile q,
+// capped at the observed maximum.
+func percentile(counts [latencyBuckets]uint64, total uint64, q float64, maxMs int64) int64 {
+	rank := uint64(math.Ceil(q * float64(total)))
+	var seen uint64
+	for b, c := range counts {
+		seen += c
+		if seen >= rank {
+			return min(bucketUpperBound(b), maxMs)
+		}
+	}
+	return maxMs
+}
+
+// latencyBucket returns the bucket for a latency of ms milliseconds.
+func latencyBucket(ms int64) int {
+	if ms <= 1 {
+		return 0
+	}
+	b := int(math.Ceil(4 * math.Log2(float64(ms))))
+	return min(b, latencyBuckets-1)
+}
+
+// bucketUpperBound returns the largest latency, in whole milliseconds, held
+// by bucket b.
+func bucketUpperBound(b int) int64 {
+	if b >= latencyBuckets-1 {
+		return math.MaxInt64
+	}
+	return int64(math.Floor(math.Exp2(float64(b) / 4)))
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyBucket_Bounds(t *testing.T) {
+	for _, ms := range []int64{0, 1, 2, 3, 7, 100, 999, 1000, 12345, 60000} {
+		b := latencyBucket(ms)
+		if upper := bucketUpperBound(b); ms > upper {
+			t.Errorf("latency %dms in bucket %d with upper bound %d", ms, b, upper)
+		}
+		if b > 0 {
+			if lower := bucketUpperBound(b - 1); ms <= lower {
+				t.Errorf("latency %dms in bucket %d, but fits bucket %d (upper %d)", ms, b, b-1, lower)
+			}
+		}
+	}
+	if b := latencyBucket(24 * 60 * 60 * 1000); b != latencyBuckets-1 {
+		t.Errorf("latencyBucket(1 day) = %d, want overflow bucket %d", b, latencyBuckets-1)
+	}
+}
+
+func TestLatencyTracker_Percentiles(t *testing.T) {
+	tracker := newLatencyTracker([]time.Duration{time.Hour})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// 1..100ms, one check each
+	for ms := int64(1); ms <= 100; ms++ {
+		tracker.record(ms, now.Add(-time.Duration(ms)*time.Second))
+	}
+
+	s := tracker.stats(now)[0]
+	if s.Window != "1h" || s.Count != 100 || s.Max != 100 {
+		t.Fatalf("stats = %+v, want 100 checks in 1h with max 100", s)
+	}
+	for _, tc := range []struct {
+		name      string
+		got, want int64
+	}{
+		{"p50", s.P50, 50},
+		{"p90", s.P90, 90},
+		{"p99", s.P99, 99},
+	} {
+		// estimates are bucket upper bounds, within about 19%
+		if tc.got < tc.want || float64(tc.got) > float64(tc.want)*1.19 {
+			t.Errorf("%s = %d, want within 19%% above %d", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestLatencyTracker_Slides(t *testing.T) {
+	tracker := newLatencyTracker([]time.Duration{time.Hour, 24 * time.Hour})
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tracker.record(5000, start)
+	tracker.record(10, start.Add(2*time.Hour))
+
+	stats := tracker.stats(start.Add(2 * time.Hour))
+	if stats[0].Count != 1 || stats[0].Max != 10 {
+		t.Errorf("1h stats = %+v, want only the recent check", stats[0])
+	}
+	if stats[1].Count != 2 || stats[1].Max != 5000 {
+		t.Errorf("24h stats = %+v, want both checks", stats[1])
+	}
+
+	// a slot reused after the window has passed starts afresh
+	tracker.record(20, start.Add(26*time.Hour))
+	if s := tracker.stats(start.Add(26 * time.Hour))[1]; s.Count != 1 || s.Max != 20 {
+		t.Errorf("24h stats a day later = %+v, want only the latest check", s)
+	}
+
+	if s := tracker.stats(start.Add(100 * time.Hour))[0]; s.Count != 0 || s.P99 != 0 {
+		t.Errorf("stats of an empty window = %+v, want zero", s)
+	}
+}
+
+func TestMemoryStore_Latency(t *testing.T) {
+	store := NewMemoryStore(WithLatencyWindows([]time.Duration{time.Hour}))
+	now := time.Now()
+	errMsg := "timeout"
+
+	store.Update(StatusResult{Name: "API", Status: "up", ResponseTimeMs: 40, CheckedAt: now.Add(-time.Minute)})
+	store.Update(StatusResult{Name: "API", Status: "down", ResponseTimeMs: 10000, CheckedAt: now, Error: &errMsg, NoResponse: true})
+
+	latest := store.GetAll()[0]
+	if len(latest.Latency) != 1 || latest.Latency[0].Count != 1 || latest.Latency[0].Max != 40 {
+		t.Errorf("Latency on result = %+v, want one check excluding the one without a response", latest.Latency)
+	}
+	if got := store.Latency("API"); len(got) != 1 || got[0].Count != 1 {
+		t.Errorf("Latency() = %+v, want one window with one check", got)
+	}
+	if got := store.Latency("Unknown"); got != nil {
+		t.Errorf("Latency(Unknown) = %v, want nil", got)
+	}
+
+	// responses over a latency threshold are the ones the tail must show
+	slow := "latency 5s > 1s"
+	for i := range 10 {
+		store.Update(StatusResult{Name: "Slow", Status: "up", ResponseTimeMs: 40, CheckedAt: now.Add(time.Duration(i-10) * time.Second)})
+	}
+	store.Update(StatusResult{Name: "Slow", Status: "degraded", ResponseTimeMs: 5000, CheckedAt: now, Error: &slow})
+	if got := store.Latency("Slow"); len(got) != 1 || got[0].Count != 11 || got[0].P99 < 5000 || got[0].Max != 5000 {
+		t.Errorf("Latency(Slow) = %+v, want the slow response in p99 and max", got)
+	}
+
+	store.Delete("API")
+	if got := store.Latency("API"); got != nil {
+		t.Errorf("Latency() after Delete = %v, want nil", got)
+	}
+}
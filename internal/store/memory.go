@@ -1,7 +1,11 @@
 package store
 
 import (
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // MemoryStore is an in-memory implementation of [Store].
@@ -12,37 +16,276 @@ import (
 //
 // Subscribers receive updates via buffered channels (buffer size 100). Updates
 // are sent non-blocking; if a subscriber's buffer is full, the update is dropped
-// for that subscriber to prevent blocking the entire system.
+// for that subscriber to prevent blocking the entire system. Removals are
+// never dropped: they are held until the subscriber has room.
+//
+// Each endpoint's recent results are kept in a ring buffer bounded by
+// [HistoryLimits], its uptime is accumulated in hourly buckets covering
+// the longest of the configured [UptimeOptions] windows, and its latency is
+// summarised in fixed-size histograms per latency window. Status transitions
+// open and close [Incident] records.
 type MemoryStore struct {
 	mu          sync.RWMutex
 	statuses    map[string]StatusResult
-	subscribers map[chan StatusResult]struct{}
+	history     map[string]*ring
+	limits      HistoryLimits
+	ledgers     map[string]*ledger
+	uptime      UptimeOptions
+	latencies   map[string]*latencyTracker
+	latencyWins []time.Duration
+	incidents   *incidents
+	counters    map[string]*Counters
+	subscribers map[chan StatusResult]*subscriber
 	subMu       sync.RWMutex
+	dropped     atomic.Uint64
+}
+
+// MemoryOption configures a [MemoryStore].
+type MemoryOption func(*MemoryStore)
+
+// WithHistoryLimits sets how much history is kept per endpoint. Limits with
+// a non-positive sample count are ignored.
+func WithHistoryLimits(limits HistoryLimits) MemoryOption {
+	return func(m *MemoryStore) {
+		if limits.Samples > 0 {
+			m.limits = limits
+		}
+	}
+}
+
+// WithUptime sets how uptime is computed. Empty Windows and a non-positive
+// MaxGap keep their defaults; DegradedCredit is used as given.
+func WithUptime(opts UptimeOptions) MemoryOption {
+	return func(m *MemoryStore) {
+		if len(opts.Windows) == 0 {
+			opts.Windows = m.uptime.Windows
+		}
+		if opts.MaxGap <= 0 {
+			opts.MaxGap = m.uptime.MaxGap
+		}
+		m.uptime = opts
+	}
+}
+
+// WithLatencyWindows sets the windows over which latency percentiles are
+// reported. An empty slice keeps the default windows.
+func WithLatencyWindows(windows []time.Duration) MemoryOption {
+	return func(m *MemoryStore) {
+		if len(windows) > 0 {
+			m.latencyWins = windows
+		}
+	}
+}
+
+// WithIncidentLimit sets how many closed incidents are kept. A
+// non-positive limit keeps the default.
+func WithIncidentLimit(limit int) MemoryOption {
+	return func(m *MemoryStore) {
+		if limit > 0 {
+			m.incidents.limit = limit
+		}
+	}
 }
 
 // NewMemoryStore creates a new in-memory [Store] implementation.
 //
+// History defaults to [DefaultHistorySamples] samples per endpoint, at most
+// [DefaultHistoryAge] old. Uptime defaults to the [DefaultUptimeWindows],
+// crediting degraded time at [DefaultDegradedCredit], and latency to the
+// [DefaultLatencyWindows]. The latest [DefaultIncidentLimit] closed incidents
+// are kept.
+//
 // The store is immediately ready for use. No cleanup is required when done.
-func NewMemoryStore() *MemoryStore {
-	return &MemoryStore{
-		statuses:    make(map[string]StatusResult),
-		subscribers: make(map[chan StatusResult]struct{}),
+func NewMemoryStore(opts ...MemoryOption) *MemoryStore {
+	m := &MemoryStore{
+		statuses: make(map[string]StatusResult),
+		history:  make(map[string]*ring),
+		limits:   HistoryLimits{Samples: DefaultHistorySamples, MaxAge: DefaultHistoryAge},
+		ledgers:  make(map[string]*ledger),
+		uptime: UptimeOptions{
+			Windows:        DefaultUptimeWindows,
+			DegradedCredit: DefaultDegradedCredit,
+			MaxGap:         DefaultUptimeMaxGap,
+		},
+		latencies:   make(map[string]*latencyTracker),
+		latencyWins: DefaultLatencyWindows,
+		incidents:   newIncidents(DefaultIncidentLimit),
+		counters:    make(map[string]*Counters),
+		subscribers: make(map[chan StatusResult]*subscriber),
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // Update stores a [StatusResult] and notifies all subscribers.
 //
 // The result is stored using its Name as the key. Subsequent updates with
-// the same name replace the previous value. All subscribers receive the
-// update (unless their buffer is full).
+// the same name replace the previous value. The stored result carries the
+// endpoint's uptime over the configured windows as of CheckedAt. All
+// subscribers receive the update (unless their buffer is full).
 func (m *MemoryStore) Update(result StatusResult) {
 	m.mu.Lock()
+	l := m.ledgers[result.Name]
+	if l == nil {
+		l = &ledger{}
+		m.ledgers[result.Name] = l
+	}
+	l.record(result.Status, result.CheckedAt, m.uptime.MaxGap, m.uptime.retention())
+	result.Uptime = m.uptimeOf(l, m.uptime.Windows, result.CheckedAt)
+
+	lt := m.latencies[result.Name]
+	if lt == nil {
+		lt = newLatencyTracker(m.latencyWins)
+		m.latencies[result.Name] = lt
+	}
+	// slow or unexpected answers count; failures without one have no
+	// response time to record
+	if !result.NoResponse {
+		lt.record(result.ResponseTimeMs, result.CheckedAt)
+	}
+	result.Latency = lt.stats(result.CheckedAt)
+
+	m.incidents.record(result)
+
+	c := m.counters[result.Name]
+	if c == nil {
+		c = newCounters()
+		m.counters[result.Name] = c
+	}
+	c.record(result)
+
 	m.statuses[result.Name] = result
+	h := m.history[result.Name]
+	if h == nil {
+		h = newRing(m.limits.Samples)
+		m.history[result.Name] = h
+	}
+	h.push(sampleOf(result))
+	if m.limits.MaxAge > 0 {
+		h.dropBefore(result.CheckedAt.Add(-m.limits.MaxAge))
+	}
 	m.mu.Unlock()
 
 	m.notifySubscribers(result)
 }
 
+// History returns the samples for name checked at or after since, oldest
+// first. Samples older than the configured maximum age are never returned.
+func (m *MemoryStore) History(name string, since time.Time) []Sample {
+	if m.limits.MaxAge > 0 {
+		if cutoff := time.Now().Add(-m.limits.MaxAge); since.Before(cutoff) {
+			since = cutoff
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	h := m.history[name]
+	if h == nil {
+		return nil
+	}
+	return h.since(since)
+}
+
+// Uptime returns the named endpoint's uptime over each window as of now.
+// Returns nil for an unknown name.
+func (m *MemoryStore) Uptime(name string, windows []time.Duration) []Uptime {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	l := m.ledgers[name]
+	if l == nil {
+		return nil
+	}
+	return m.uptimeOf(l, windows, time.Now())
+}
+
+// uptimeOf computes l's uptime over each window ending at now. Callers must
+// hold m.mu.
+func (m *MemoryStore) uptimeOf(l *ledger, windows []time.Duration, now time.Time) []Uptime {
+	out := make([]Uptime, len(windows))
+	for i, w := range windows {
+		out[i] = l.uptime(w, now, m.uptime)
+	}
+	return out
+}
+
+// Latency returns the named endpoint's latency percentiles over each
+// configured window as of now. Returns nil for an unknown name.
+func (m *MemoryStore) Latency(name string) []LatencyStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lt := m.latencies[name]
+	if lt == nil {
+		return nil
+	}
+	return lt.stats(time.Now())
+}
+
+// Incidents returns all open incidents and the retained closed ones, newest
+// first.
+func (m *MemoryStore) Incidents() []Incident {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.incidents.list(time.Now())
+}
+
+// Counters returns a copy of each endpoint's cumulative counters, keyed by
+// name.
+func (m *MemoryStore) Counters() map[string]Counters {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make(map[string]Counters, len(m.counters))
+	for name, c := range m.counters {
+		out[name] = c.clone()
+	}
+	return out
+}
+
+// DroppedUpdates returns how many updates were dropped because a
+// subscriber's buffer was full.
+func (m *MemoryStore) DroppedUpdates() uint64 {
+	return m.dropped.Load()
+}
+
+// resetCounters clears all counters, so results replayed on open are not
+// counted as new checks.
+func (m *MemoryStore) resetCounters() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters = make(map[string]*Counters)
+}
+
+// Delete removes the result, history, uptime, latency and counters stored
+// under name, and closes its open incident as of its last check.
+//
+// If a result was present, subscribers receive a [StatusResult] with Removed
+// set so they can drop the endpoint. Deleting an unknown name is a no-op.
+func (m *MemoryStore) Delete(name string) {
+	m.mu.Lock()
+	last, ok := m.statuses[name]
+	if ok {
+		m.incidents.close(name, last.CheckedAt)
+	}
+	delete(m.statuses, name)
+	delete(m.history, name)
+	delete(m.ledgers, name)
+	delete(m.latencies, name)
+	delete(m.counters, name)
+	m.mu.Unlock()
+
+	if ok {
+		m.notifySubscribers(StatusResult{Name: name, Removed: true})
+	}
+}
+
 // GetAll returns a snapshot of all currently stored status results.
 //
 // The returned slice is a copy; modifications do not affect the store.
@@ -61,14 +304,15 @@ func (m *MemoryStore) GetAll() []StatusResult {
 // Subscribe creates a new subscription and returns a channel for receiving updates.
 //
 // The returned channel has a buffer of 100 messages. If the buffer fills
-// (slow consumer), new updates are dropped for this subscriber.
+// (slow consumer), new updates are dropped for this subscriber, except
+// removals, which are delivered once the subscriber catches up.
 //
 // Caller must call [MemoryStore.Unsubscribe] when done to prevent resource leaks.
 func (m *MemoryStore) Subscribe() <-chan StatusResult {
 	ch := make(chan StatusResult, 100)
 
 	m.subMu.Lock()
-	m.subscribers[ch] = struct{}{}
+	m.subscribers[ch] = &subscriber{ch: ch, done: make(chan struct{})}
 	m.subMu.Unlock()
 
 	return ch
@@ -83,10 +327,10 @@ func (m *MemoryStore) Unsubscribe(ch <-chan StatusResult) {
 	defer m.subMu.Unlock()
 
 	// find and delete the channel (need to convert to the right type)
-	for subCh := range m.subscribers {
+	for subCh, sub := range m.subscribers {
 		if subCh == ch {
 			delete(m.subscribers, subCh)
-			close(subCh)
+			sub.close()
 			break
 		}
 	}
@@ -94,17 +338,189 @@ func (m *MemoryStore) Unsubscribe(ch <-chan StatusResult) {
 
 // notifySubscribers sends the result to all active subscribers.
 //
-// This is non-blocking: if a subscriber's channel buffer is full, the message
-// is dropped for that subscriber rather than blocking the update path.
+// This is non-blocking: if a subscriber's channel buffer is full, an update
+// is dropped for that subscriber rather than blocking the update path, and
+// a removal is queued for delivery in the background.
 func (m *MemoryStore) notifySubscribers(result StatusResult) {
 	m.subMu.RLock()
 	defer m.subMu.RUnlock()
 
-	for ch := range m.subscribers {
-		select {
-		case ch <- result:
-		default:
+	for _, sub := range m.subscribers {
+		if !sub.send(result) {
 			// subscriber is slow, drop the message
+			m.dropped.Add(1)
 		}
 	}
 }
+
+// subscriber is a subscription's channel and the removals waiting for room
+// in it.
+type subscriber struct {
+	ch   chan StatusResult
+	done chan struct{} // closed by close to stop flush
+
+	mu       sync.Mutex
+	removals []string // undelivered removals, oldest first
+	flushing bool     // a flush goroutine is running
+	wg       sync.WaitGroup
+}
+
+// send delivers result without blocking, reporting false if it was dropped.
+// A removal that does not fit is queued instead. Updates for an endpoint
+// with a queued removal are dropped, so they cannot overtake it.
+func (s *subscriber) send(result StatusResult) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if result.Removed {
+		if !s.flushing {
+			select {
+			case s.ch <- result:
+				return true
+			default:
+			}
+		}
+		if !slices.Contains(s.removals, result.Name) {
+			s.removals = append(s.removals, result.Name)
+		}
+		if !s.flushing {
+			s.flushing = true
+			s.wg.Add(1)
+			go s.flush()
+		}
+		return true
+	}
+
+	if slices.Contains(s.removals, result.Name) {
+		return false
+	}
+	select {
+	case s.ch <- result:
+		return true
+	default:
+		return false
+	}
+}
+
+// flush delivers queued removals, waiting for room in the channel, until
+// none are left or the subscription is closed.
+func (s *subscriber) flush() {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		if len(s.removals) == 0 {
+			s.flushing = false
+			s.mu.Unlock()
+			return
+		}
+		name := s.removals[0]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- StatusResult{Name: name, Removed: true}:
+		case <-s.done:
+			return
+		}
+
+		s.mu.Lock()
+		s.removals = s.removals[1:]
+		s.mu.Unlock()
+	}
+}
+
+// close stops any flush in progress and closes the channel. The caller
+// must hold the store's subMu, so no send is in progress.
+func (s *subscriber) close() {
+	close(s.done)
+	s.wg.Wait()
+	close(s.ch)
+}
+
+// snapshot returns records that rebuild the store's current results and
+// history when applied in order: each endpoint's older samples followed by
+// its latest result and then its uptime ledger and latency histograms, which
+// replace those partially rebuilt from the samples, and finally all
+// incidents.
+func (m *MemoryStore) snapshot() []record {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.statuses))
+	for name := range m.statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var recs []record
+	for _, name := range names {
+		latest := m.statuses[name]
+		if h := m.history[name]; h != nil {
+			for _, sample := range h.since(time.Time{}) {
+				if sample.CheckedAt.Equal(latest.CheckedAt) {
+					continue
+				}
+				recs = append(recs, record{Op: "update", Result: &StatusResult{
+					Name:           name,
+					Status:         sample.Status,
+					ResponseTimeMs: sample.ResponseTimeMs,
+					CheckedAt:      sample.CheckedAt,
+					Error:          sample.Error,
+				}})
+			}
+		}
+		latest.Uptime, latest.Latency = nil, nil
+		recs = append(recs, record{Op: "update", Result: &latest})
+		if l := m.ledgers[name]; l != nil {
+			ledger := *l
+			ledger.Buckets = slices.Clone(l.Buckets)
+			recs = append(recs, record{Op: "uptime", Name: name, Ledger: &ledger})
+		}
+		if lt := m.latencies[name]; lt != nil {
+			tracker := latencyTracker{Windows: slices.Clone(lt.Windows)}
+			recs = append(recs, record{Op: "latency", Name: name, Latency: &tracker})
+		}
+	}
+	recs = append(recs, record{Op: "incidents", Incidents: m.incidents.all()})
+	return recs
+}
+
+// restoreLatency replaces the latency histograms for name. Histograms for
+// windows that are no longer configured are discarded.
+func (m *MemoryStore) restoreLatency(name string, t *latencyTracker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.statuses[name]; !ok {
+		return
+	}
+	restored := newLatencyTracker(m.latencyWins)
+	for i := range restored.Windows {
+		for _, w := range t.Windows {
+			if w.Length == restored.Windows[i].Length {
+				restored.Windows[i] = w
+			}
+		}
+	}
+	m.latencies[name] = restored
+}
+
+// restoreIncidents replaces all incidents.
+func (m *MemoryStore) restoreIncidents(list []Incident) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.incidents.restore(list, func(name string) bool {
+		_, ok := m.statuses[name]
+		return ok
+	})
+}
+
+// restoreLedger replaces the uptime ledger for name.
+func (m *MemoryStore) restoreLedger(name string, l *ledger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.statuses[name]; ok {
+		m.ledgers[name] = l
+	}
+}
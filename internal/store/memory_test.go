@@ -273,3 +273,141 @@ func TestMemoryStore_GetAllReturnsLatest(t *testing.T) {
 		t.Errorf("GetAll()[0].ResponseTimeMs = %v, want %v", all[0].ResponseTimeMs, 300)
 	}
 }
+
+func TestMemoryStore_Delete(t *testing.T) {
+	store := NewMemoryStore()
+	store.Update(StatusResult{Name: "A", Status: "up"})
+	store.Update(StatusResult{Name: "B", Status: "up"})
+
+	ch := store.Subscribe()
+	defer store.Unsubscribe(ch)
+
+	store.Delete("A")
+	store.Delete("Unknown")
+
+	results := store.GetAll()
+	if len(results) != 1 || results[0].Name != "B" {
+		t.Errorf("GetAll() after Delete = %+v, want only B", results)
+	}
+
+	select {
+	case result := <-ch:
+		if result.Name != "A" || !result.Removed {
+			t.Errorf("received %+v, want removal of A", result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Delete() did not notify subscriber")
+	}
+
+	// deleting an unknown name must not notify
+	select {
+	case result := <-ch:
+		t.Errorf("unexpected notification %+v", result)
+	default:
+	}
+}
+
+func TestMemoryStore_DeleteReachesSlowSubscriber(t *testing.T) {
+	store := NewMemoryStore()
+	store.Update(StatusResult{Name: "A", Status: "up"})
+
+	ch := store.Subscribe()
+	defer store.Unsubscribe(ch)
+
+	// fill the subscriber's buffer before it reads anything
+	for i := 0; i < cap(ch); i++ {
+		store.Update(StatusResult{Name: "B", Status: "up"})
+	}
+	store.Delete("A")
+	// an update for A re-added meanwhile must not overtake the removal
+	store.Update(StatusResult{Name: "A", Status: "up"})
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case result := <-ch:
+			if result.Name != "A" {
+				continue
+			}
+			if !result.Removed {
+				t.Fatalf("received %+v before the removal of A", result)
+			}
+			return
+		case <-timeout:
+			t.Fatal("Delete() removal never reached the slow subscriber")
+		}
+	}
+}
+
+func TestMemoryStore_UnsubscribeWithQueuedRemoval(t *testing.T) {
+	store := NewMemoryStore()
+	store.Update(StatusResult{Name: "A", Status: "up"})
+
+	ch := store.Subscribe()
+	for i := 0; i < cap(ch); i++ {
+		store.Update(StatusResult{Name: "B", Status: "up"})
+	}
+	store.Delete("A")
+
+	done := make(chan struct{})
+	go func() {
+		store.Unsubscribe(ch)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Unsubscribe() blocked on a queued removal")
+	}
+}
+
+func TestMemoryStore_History(t *testing.T) {
+	store := NewMemoryStore(WithHistoryLimits(HistoryLimits{Samples: 3}))
+	base := time.Now().Add(-time.Hour)
+	errMsg := "timeout"
+
+	for i, status := range []string{"up", "down", "up", "degraded"} {
+		r := StatusResult{
+			Name:           "API",
+			Status:         status,
+			ResponseTimeMs: int64(i * 10),
+			CheckedAt:      base.Add(time.Duration(i) * time.Minute),
+		}
+		if status == "down" {
+			r.Error = &errMsg
+		}
+		store.Update(r)
+	}
+
+	samples := store.History("API", time.Time{})
+	if got := statusesOf(samples); len(got) != 3 || got[0] != "down" || got[2] != "degraded" {
+		t.Fatalf("History() statuses = %v, want [down up degraded]", got)
+	}
+	if samples[0].Error == nil || *samples[0].Error != "timeout" || samples[0].ResponseTimeMs != 10 {
+		t.Errorf("History()[0] = %+v, want error and latency preserved", samples[0])
+	}
+
+	if got := store.History("API", base.Add(2*time.Minute)); len(got) != 2 {
+		t.Errorf("History(since 2m) = %d samples, want 2", len(got))
+	}
+	if got := store.History("Unknown", time.Time{}); got != nil {
+		t.Errorf("History(Unknown) = %v, want nil", got)
+	}
+
+	store.Delete("API")
+	if got := store.History("API", time.Time{}); got != nil {
+		t.Errorf("History() after Delete = %v, want nil", got)
+	}
+}
+
+func TestMemoryStore_HistoryMaxAge(t *testing.T) {
+	store := NewMemoryStore(WithHistoryLimits(HistoryLimits{Samples: 10, MaxAge: time.Hour}))
+	now := time.Now()
+
+	store.Update(StatusResult{Name: "API", Status: "down", CheckedAt: now.Add(-2 * time.Hour)})
+	store.Update(StatusResult{Name: "API", Status: "up", CheckedAt: now.Add(-time.Minute)})
+
+	if got := statusesOf(store.History("API", time.Time{})); len(got) != 1 || got[0] != "up" {
+		t.Errorf("History() statuses = %v, want [up]", got)
+	}
+}
@@ -14,21 +14,67 @@ type StatusResult struct {
 	// URL is the target URL that was polled.
 	URL string `json:"url"`
 
-	// Status is the determined health status (e.g., "up", "down", "degraded").
+	// Status is the published health status (e.g., "up", "down", "degraded").
 	Status string `json:"status"`
 
+	// RawStatus is the status of the latest check before thresholds and flap
+	// detection were applied.
+	RawStatus string `json:"raw_status,omitempty"`
+
+	// Flapping reports whether the endpoint is flapping, in which case Status
+	// is held at its last value.
+	Flapping bool `json:"flapping,omitempty"`
+
 	// Labels contains key-value metadata for grouping and filtering.
 	Labels map[string]string `json:"labels"`
 
 	// ResponseTimeMs is the request latency in milliseconds.
 	ResponseTimeMs int64 `json:"response_time_ms"`
 
+	// NoResponse marks a check that failed without a response, such as a
+	// refused connection or a timeout. ResponseTimeMs is then the time until
+	// the check failed rather than a response time.
+	NoResponse bool `json:"no_response,omitempty"`
+
 	// CheckedAt is the timestamp of the last poll.
 	CheckedAt time.Time `json:"checked_at"`
 
 	// Error contains the error message if the poll failed.
 	// nil indicates no error (though status may still be "down").
 	Error *string `json:"error"`
+
+	// Cert describes the server's TLS certificate when it was checked.
+	Cert *CertInfo `json:"cert,omitempty"`
+
+	// Attempts is the number of attempts made during the last poll.
+	Attempts int `json:"attempts,omitempty"`
+
+	// AttemptErrors holds the errors of retried attempts in the last poll.
+	AttemptErrors []string `json:"attempt_errors,omitempty"`
+
+	// Uptime is the endpoint's availability over the configured windows as
+	// of CheckedAt.
+	Uptime []Uptime `json:"uptime,omitempty"`
+
+	// Latency summarises the endpoint's response times over the configured
+	// windows as of CheckedAt.
+	Latency []LatencyStats `json:"latency,omitempty"`
+
+	// Removed marks a notification that the endpoint was deleted from the
+	// store. Only Name is set on such results.
+	Removed bool `json:"removed,omitempty"`
+}
+
+// CertInfo is the storage representation of a TLS certificate's details.
+type CertInfo struct {
+	// NotAfter is when the certificate expires.
+	NotAfter time.Time `json:"not_after"`
+
+	// Issuer is the issuer's distinguished name.
+	Issuer string `json:"issuer"`
+
+	// DNSNames contains the certificate's subject alternative names.
+	DNSNames []string `json:"dns_names"`
 }
 
 // Store defines the interface for storing and subscribing to status updates.
@@ -45,8 +91,38 @@ type Store interface {
 	// The returned slice is a snapshot; modifications do not affect the store.
 	GetAll() []StatusResult
 
+	// History returns the recorded samples for the named endpoint checked at
+	// or after since, oldest first. Returns nil for an unknown name.
+	History(name string, since time.Time) []Sample
+
+	// Uptime returns the named endpoint's availability over each window,
+	// ending now. Returns nil for an unknown name.
+	Uptime(name string, windows []time.Duration) []Uptime
+
+	// Latency returns the named endpoint's latency percentiles over each
+	// configured window, ending now. Returns nil for an unknown name.
+	Latency(name string) []LatencyStats
+
+	// Incidents returns all open incidents and the retained closed ones,
+	// newest first.
+	Incidents() []Incident
+
+	// Counters returns each endpoint's cumulative check counts, keyed by
+	// name.
+	Counters() map[string]Counters
+
+	// DroppedUpdates returns how many updates were dropped because a
+	// subscriber was too slow to receive them.
+	DroppedUpdates() uint64
+
+	// Delete removes the named result, history, uptime, latency and
+	// counters, closes its open incident and, if it was present, notifies
+	// all subscribers with a result that has only Name and Removed set.
+	Delete(name string)
+
 	// Subscribe returns a channel that receives status updates.
-	// The returned channel has a buffer; slow consumers may miss updates.
+	// The returned channel has a buffer; slow consumers may miss updates,
+	// but not removals.
 	// Caller must call Unsubscribe when done to prevent resource leaks.
 	Subscribe() <-chan StatusResult
 
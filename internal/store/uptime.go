@@ -0,0 +1,241 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDegradedCredit is the default fraction of degraded time counted
+	// as available.
+	DefaultDegradedCredit = 0.5
+
+	// DefaultUptimeMaxGap is the default longest gap between two results
+	// that is attributed to the earlier result's status.
+	DefaultUptimeMaxGap = 5 * time.Minute
+
+	// bucketSize is the granularity at which uptime is recorded.
+	bucketSize = time.Hour
+)
+
+// DefaultUptimeWindows are the windows reported with every status result.
+var DefaultUptimeWindows = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+// UptimeOptions configures how uptime is computed.
+type UptimeOptions struct {
+	// Windows are the rolling windows reported with every status result.
+	// The longest window also sets how long uptime data is kept.
+	Windows []time.Duration
+
+	// DegradedCredit is the fraction, between 0 and 1, of degraded time
+	// counted as available.
+	DegradedCredit float64
+
+	// MaxGap is the longest gap between two results that is attributed to
+	// the earlier result's status. Longer gaps, such as while the dashboard
+	// was stopped, are not counted.
+	MaxGap time.Duration
+}
+
+// retention returns how long uptime data must be kept to serve the
+// longest window.
+func (o UptimeOptions) retention() time.Duration {
+	var longest time.Duration
+	for _, w := range o.Windows {
+		longest = max(longest, w)
+	}
+	return longest
+}
+
+// Uptime is the availability of an endpoint over a rolling window.
+//
+// Availability is time-weighted: each result's status is assumed to hold
+// until the next result. Time in an unknown status is excluded.
+type Uptime struct {
+	// Window is the window length, such as "24h" or "7d".
+	Window string `json:"window"`
+
+	// Percent is the available share of the observed time, or nil if the
+	// endpoint was not observed during the window.
+	Percent *float64 `json:"percent"`
+
+	// DowntimeMinutes is the unavailable time: down time plus the uncredited
+	// share of degraded time.
+	DowntimeMinutes float64 `json:"downtime_minutes"`
+
+	// Checks counts the results in the window by status.
+	Checks map[string]int `json:"checks"`
+}
+
+// statusIndex maps a status to its slot in a bucket.
+func statusIndex(status string) int {
+	switch status {
+	case "up":
+		return 0
+	case "degraded":
+		return 1
+	case "down":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// statusNames are the statuses in bucket slot order.
+var statusNames = [4]string{"up", "degraded", "down", "unknown"}
+
+// uptimeBucket accumulates the time spent in, and results reporting, each
+// status during one hour.
+type uptimeBucket struct {
+	Start  time.Time        `json:"start"`
+	Time   [4]time.Duration `json:"time"`
+	Checks [4]int           `json:"checks"`
+}
+
+// ledger records an endpoint's uptime in hourly buckets.
+type ledger struct {
+	Buckets    []uptimeBucket `json:"buckets"`
+	LastStatus string         `json:"last_status"`
+	LastAt     time.Time      `json:"last_at"`
+}
+
+// record adds a result, attributing the time since the previous result to
+// the previous status, and discards buckets older than retention.
+func (l *ledger) record(status string, at time.Time, maxGap, retention time.Duration) {
+	if !l.LastAt.IsZero() {
+		if !at.After(l.LastAt) {
+			return // out of order; already accounted for
+		}
+		if at.Sub(l.LastAt) <= maxGap {
+			l.credit(statusIndex(l.LastStatus), l.LastAt, at)
+		}
+	}
+	l.bucket(at).Checks[statusIndex(status)]++
+	l.LastStatus, l.LastAt = status, at
+
+	cutoff := at.Add(-retention - bucketSize)
+	drop := 0
+	for drop < len(l.Buckets) && l.Buckets[drop].Start.Before(cutoff) {
+		drop++
+	}
+	l.Buckets = l.Buckets[drop:]
+}
+
+// credit adds the time between from and to to status, split across buckets.
+func (l *ledger) credit(status int, from, to time.Time) {
+	for from.Before(to) {
+		end := from.Truncate(bucketSize).Add(bucketSize)
+		if end.After(to) {
+			end = to
+		}
+		l.bucket(from).Time[status] += end.Sub(from)
+		from = end
+	}
+}
+
+// bucket returns the bucket containing t, creating it if needed. Results
+// arrive in time order, so new buckets are appended.
+func (l *ledger) bucket(t time.Time) *uptimeBucket {
+	start := t.Truncate(bucketSize)
+	for i := len(l.Buckets) - 1; i >= 0; i-- {
+		if l.Buckets[i].Start.Equal(start) {
+			return &l.Buckets[i]
+		}
+		if l.Buckets[i].Start.Before(start) {
+			break
+		}
+	}
+	l.Buckets = append(l.Buckets, uptimeBucket{Start: start})
+	return &l.Buckets[len(l.Buckets)-1]
+}
+
+// uptime computes availability over the window ending at now. Buckets that
+// straddle the start of the window are prorated.
+func (l *ledger) uptime(window time.Duration, now time.Time, opts UptimeOptions) Uptime {
+	from := now.Add(-window)
+	var total [4]time.Duration
+	checks := make(map[string]int)
+
+	for _, b := range l.Buckets {
+		end := b.Start.Add(bucketSize)
+		if !end.After(from) || b.Start.After(now) {
+			continue
+		}
+		fraction := 1.0
+		if b.Start.Before(from) {
+			fraction = float64(end.Sub(from)) / float64(bucketSize)
+		}
+		for i := range total {
+			total[i] += time.Duration(float64(b.Time[i]) * fraction)
+			if b.Checks[i] > 0 {
+				checks[statusNames[i]] += b.Checks[i]
+			}
+		}
+	}
+
+	// the latest status holds from the last result until now, for at most
+	// MaxGap
+	if !l.LastAt.IsZero() {
+		start, end := l.LastAt, l.LastAt.Add(opts.MaxGap)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(now) {
+			end = now
+		}
+		if end.After(start) {
+			total[statusIndex(l.LastStatus)] += end.Sub(start)
+		}
+	}
+
+	u := Uptime{Window: FormatWindow(window), Checks: checks}
+	observed := total[0] + total[1] + total[2]
+	if observed > 0 {
+		available := float64(total[0]) + opts.DegradedCredit*float64(total[1])
+		pct := 100 * available / float64(observed)
+		u.Percent = &pct
+		u.DowntimeMinutes = (float64(observed) - available) / float64(time.Minute)
+	}
+	return u
+}
+
+// ParseWindow parses a window length: a Go duration such as "12h", or a
+// whole number of days such as "7d".
+func ParseWindow(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+	}
+	if d <= 0 {
+		return 0, errors.New("window must be positive")
+	}
+	return d, nil
+}
+
+// FormatWindow formats a window length as accepted by [ParseWindow], using
+// days for multiples of a day longer than one day.
+func FormatWindow(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d > day && d%day == 0:
+		return fmt.Sprintf("%dd", d/day)
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
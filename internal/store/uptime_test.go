@@ -0,0 +1,146 @@
+package store
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLedger_Uptime(t *testing.T) {
+	opts := UptimeOptions{DegradedCredit: 0.5, MaxGap: 10 * time.Minute}
+	retention := 24 * time.Hour
+	base := time.Date(2026, 1, 1, 10, 50, 0, 0, time.UTC)
+
+	var l ledger
+	// 20m up (across an hour boundary), 10m degraded, 10m down, then up
+	for _, step := range []struct {
+		status string
+		offset time.Duration
+	}{
+		{"up", 0},
+		{"up", 10 * time.Minute},
+		{"degraded", 20 * time.Minute},
+		{"down", 30 * time.Minute},
+		{"up", 40 * time.Minute},
+	} {
+		l.record(step.status, base.Add(step.offset), opts.MaxGap, retention)
+	}
+
+	if len(l.Buckets) != 2 {
+		t.Fatalf("buckets = %d, want 2 (10:00 and 11:00)", len(l.Buckets))
+	}
+
+	u := l.uptime(24*time.Hour, base.Add(40*time.Minute), opts)
+	if u.Window != "24h" {
+		t.Errorf("Window = %q, want 24h", u.Window)
+	}
+	// (20 + 0.5*10) / 40
+	if u.Percent == nil || math.Abs(*u.Percent-62.5) > 1e-9 {
+		t.Errorf("Percent = %v, want 62.5", u.Percent)
+	}
+	if math.Abs(u.DowntimeMinutes-15) > 1e-9 {
+		t.Errorf("DowntimeMinutes = %v, want 15", u.DowntimeMinutes)
+	}
+	if u.Checks["up"] != 3 || u.Checks["degraded"] != 1 || u.Checks["down"] != 1 {
+		t.Errorf("Checks = %v, want 3 up, 1 degraded, 1 down", u.Checks)
+	}
+
+	// the latest status holds until now, capped at MaxGap
+	later := l.uptime(24*time.Hour, base.Add(2*time.Hour), opts)
+	if later.Percent == nil || math.Abs(*later.Percent-70) > 1e-9 {
+		t.Errorf("Percent with open interval = %v, want 70", later.Percent)
+	}
+}
+
+func TestLedger_GapsAndWindows(t *testing.T) {
+	opts := UptimeOptions{DegradedCredit: 0.5, MaxGap: 5 * time.Minute}
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var l ledger
+	l.record("down", base, opts.MaxGap, 24*time.Hour)
+	// a gap longer than MaxGap, e.g. while stopped, is not counted
+	l.record("up", base.Add(time.Hour), opts.MaxGap, 24*time.Hour)
+	l.record("up", base.Add(time.Hour+5*time.Minute), opts.MaxGap, 24*time.Hour)
+
+	now := base.Add(time.Hour + 5*time.Minute)
+	if u := l.uptime(24*time.Hour, now, opts); u.Percent == nil || *u.Percent != 100 {
+		t.Errorf("Percent = %v, want 100 with the gap excluded", u.Percent)
+	}
+
+	// a window after the last result sees only the capped open interval
+	if u := l.uptime(time.Minute, now.Add(time.Hour), opts); u.Percent != nil {
+		t.Errorf("Percent = %v, want nil for an unobserved window", *u.Percent)
+	}
+
+	// results older than retention are discarded
+	l.record("up", base.Add(48*time.Hour), opts.MaxGap, 24*time.Hour)
+	if l.Buckets[0].Start.Before(base.Add(23 * time.Hour)) {
+		t.Errorf("oldest bucket = %v, want buckets older than retention dropped", l.Buckets[0].Start)
+	}
+}
+
+func TestMemoryStore_Uptime(t *testing.T) {
+	store := NewMemoryStore(WithUptime(UptimeOptions{Windows: []time.Duration{time.Hour}, DegradedCredit: 1}))
+	now := time.Now()
+
+	store.Update(StatusResult{Name: "API", Status: "degraded", CheckedAt: now.Add(-2 * time.Minute)})
+	store.Update(StatusResult{Name: "API", Status: "up", CheckedAt: now.Add(-time.Minute)})
+
+	latest := store.GetAll()[0]
+	if len(latest.Uptime) != 1 || latest.Uptime[0].Window != "1h" {
+		t.Fatalf("Uptime on result = %+v, want the configured 1h window", latest.Uptime)
+	}
+	if p := latest.Uptime[0].Percent; p == nil || *p != 100 {
+		t.Errorf("Percent = %v, want 100 with full degraded credit", p)
+	}
+
+	if got := store.Uptime("API", []time.Duration{time.Hour, 24 * time.Hour}); len(got) != 2 {
+		t.Errorf("Uptime() = %+v, want two windows", got)
+	}
+	if got := store.Uptime("Unknown", []time.Duration{time.Hour}); got != nil {
+		t.Errorf("Uptime(Unknown) = %v, want nil", got)
+	}
+
+	store.Delete("API")
+	if got := store.Uptime("API", []time.Duration{time.Hour}); got != nil {
+		t.Errorf("Uptime() after Delete = %v, want nil", got)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"24h", 24 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"0d", 0, true},
+		{"-1h", 0, true},
+		{"xd", 0, true},
+		{"week", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseWindow(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+		if err == nil {
+			if back, _ := ParseWindow(FormatWindow(got)); back != got {
+				t.Errorf("FormatWindow(%v) = %q does not round-trip", got, FormatWindow(got))
+			}
+		}
+	}
+
+	for d, want := range map[time.Duration]string{
+		24 * time.Hour:      "24h",
+		48 * time.Hour:      "2d",
+		30 * 24 * time.Hour: "30d",
+		90 * time.Minute:    "90m",
+	} {
+		if got := FormatWindow(d); got != want {
+			t.Errorf("FormatWindow(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
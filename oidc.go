@@ -0,0 +1,78 @@
+package pulseboard
+
+import (
+	"github.com/jpalmerr/pulseboard/internal/server"
+)
+
+// OIDC configures single sign-on with an OpenID Connect provider. See
+// [WithOIDC].
+type OIDC struct {
+	// Issuer is the provider's issuer URL, such as
+	// "https://accounts.google.com". Its endpoints are discovered from
+	// Issuer + "/.well-known/openid-configuration".
+	Issuer string
+
+	// ClientID and ClientSecret identify PulseBoard to the provider.
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the absolute URL of the dashboard's /auth/callback
+	// route, such as "https://status.example.com/auth/callback". It must
+	// be registered with the provider.
+	RedirectURL string
+
+	// Scopes are requested in addition to "openid". Defaults to "email"
+	// and "profile". Some providers only issue refresh tokens for the
+	// "offline_access" scope.
+	Scopes []string
+
+	// AllowedDomains, if set, admits only users whose verified email
+	// address is in one of these domains.
+	AllowedDomains []string
+
+	// AllowedGroups, if set, admits only users whose groups claim lists
+	// one of these groups.
+	AllowedGroups []string
+
+	// AllowAnyAccount admits every user the provider authenticates, which
+	// for a public provider such as Google means anyone with an account.
+	// It is required when neither AllowedDomains nor AllowedGroups is set.
+	AllowAnyAccount bool
+
+	// GroupsClaim names the ID token claim that lists a user's groups.
+	// Defaults to "groups".
+	GroupsClaim string
+}
+
+// WithOIDC requires browsers to log in with an OpenID Connect provider
+// using the authorization code flow. Unauthenticated page loads are
+// redirected to the provider; API requests without a session get 401
+// Unauthorized. Sessions are refreshed with the provider's refresh token
+// while in use, so an open dashboard is disconnected once the user loses
+// access. /auth/logout ends the session.
+//
+// Users and tokens from [WithAuth] are still accepted, for API clients and
+// probes, and its Public paths stay public.
+//
+// Example:
+//
+//	pulseboard.WithOIDC(pulseboard.OIDC{
+//	    Issuer:         "https://accounts.google.com",
+//	    ClientID:       os.Getenv("OIDC_CLIENT_ID"),
+//	    ClientSecret:   os.Getenv("OIDC_CLIENT_SECRET"),
+//	    RedirectURL:    "https://status.example.com/auth/callback",
+//	    AllowedDomains: []string{"example.com"},
+//	})
+//
+// Returns an error if the issuer is not an http or https URL, the client
+// ID is empty, RedirectURL is not an absolute URL of /auth/callback, or no
+// AllowedDomains or AllowedGroups are given without AllowAnyAccount.
+func WithOIDC(o OIDC) Option {
+	return func(cfg *pbConfig) error {
+		if err := server.ValidateOIDC(server.OIDC(o)); err != nil {
+			return err
+		}
+		cfg.oidc = &o
+		return nil
+	}
+}
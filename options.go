@@ -2,8 +2,12 @@ package pulseboard
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/server"
+	"github.com/jpalmerr/pulseboard/internal/store"
 )
 
 // pbConfig holds mutable state during PulseBoard construction.
@@ -15,6 +19,19 @@ type pbConfig struct {
 	maxConcurrency  int
 	logger          *slog.Logger
 	statusCallbacks []func(StatusResult)
+	adminToken      string
+	history         store.HistoryLimits
+	uptime          store.UptimeOptions
+	latencyWindows  []time.Duration
+	healthPath      string
+	healthRules     []HealthRule
+	basePath        string
+	auth            Auth
+	oidc            *OIDC
+	public          *PublicPage
+	tls             *server.TLS
+	secrets         []string
+	store           StoreBackend
 }
 
 // Option is a function that configures a [PulseBoard] instance during construction.
@@ -64,8 +81,9 @@ func WithEndpoints(endpoints ...Endpoint) Option {
 
 // WithPollingInterval sets how often all endpoints are polled.
 //
-// The interval applies globally to all endpoints. Each polling cycle
-// polls all endpoints concurrently (up to [WithMaxConcurrency] limit).
+// The interval applies to every endpoint that does not set its own via
+// [WithInterval]. Each endpoint is scheduled independently, so endpoints are
+// polled concurrently (up to the [WithMaxConcurrency] limit) as they fall due.
 // Defaults to 15 seconds if not specified.
 //
 // Example:
@@ -111,9 +129,10 @@ func WithPort(port int) Option {
 
 // WithMaxConcurrency sets the maximum number of concurrent HTTP requests.
 //
-// This limits how many endpoints are polled simultaneously during each
-// polling cycle. Use this to avoid overwhelming target services or to
-// respect rate limits. Defaults to 10 if not specified.
+// This sets the size of the polling worker pool, limiting how many endpoints
+// are polled simultaneously. A slow endpoint occupies a single worker and does
+// not delay other endpoints. Use this to avoid overwhelming target services or
+// to respect rate limits. Defaults to 10 if not specified.
 //
 // Example:
 //
@@ -210,3 +229,116 @@ func WithTitle(title string) Option {
 		return nil
 	}
 }
+
+// WithAdminToken enables the endpoint management API at /api/endpoints,
+// which adds, replaces and removes endpoints at runtime. Requests must send
+// the token as "Authorization: Bearer <token>".
+//
+// The request body is JSON, for example:
+//
+//	{"name": "API", "url": "https://api.example.com/health", "labels": {"env": "prod"}}
+//
+// POST adds an endpoint, PUT replaces one, and DELETE /api/endpoints?name=API
+// removes one. Returns an error if token is empty.
+func WithAdminToken(token string) Option {
+	return func(cfg *pbConfig) error {
+		if token == "" {
+			return errors.New("admin token cannot be empty")
+		}
+		cfg.adminToken = token
+		return nil
+	}
+}
+
+// WithHistory sets how much status history is kept per endpoint and served
+// at /api/history: at most samples results, none older than maxAge.
+// A maxAge of zero keeps results until newer ones displace them.
+//
+// Defaults to 1000 samples and 24 hours.
+//
+// Returns an error if samples is less than 1 or maxAge is negative.
+func WithHistory(samples int, maxAge time.Duration) Option {
+	return func(cfg *pbConfig) error {
+		if samples < 1 {
+			return fmt.Errorf("history samples must be at least 1, got %d", samples)
+		}
+		if maxAge < 0 {
+			return fmt.Errorf("history max age cannot be negative, got %s", maxAge)
+		}
+		cfg.history = store.HistoryLimits{Samples: samples, MaxAge: maxAge}
+		return nil
+	}
+}
+
+// WithUptime sets how uptime is reported in /api/status, /api/uptime and
+// on the dashboard. degradedCredit is the fraction, between 0 and 1, of time
+// spent degraded that counts as available: 0 treats degraded as down, 1 as
+// up. windows are the rolling windows reported with every status; the
+// longest also sets how long uptime data is kept.
+//
+// Defaults to a credit of 0.5 and windows of 24 hours, 7 days and 30 days.
+// Passing no windows keeps the default windows.
+//
+// Returns an error if degradedCredit is outside [0, 1] or a window is not
+// positive.
+func WithUptime(degradedCredit float64, windows ...time.Duration) Option {
+	return func(cfg *pbConfig) error {
+		if degradedCredit < 0 || degradedCredit > 1 {
+			return fmt.Errorf("degraded credit must be between 0 and 1, got %g", degradedCredit)
+		}
+		for _, w := range windows {
+			if w <= 0 {
+				return fmt.Errorf("uptime window must be positive, got %s", w)
+			}
+		}
+		cfg.uptime.DegradedCredit = degradedCredit
+		if len(windows) > 0 {
+			cfg.uptime.Windows = windows
+		}
+		return nil
+	}
+}
+
+// WithLatencyWindows sets the rolling windows over which latency
+// percentiles (p50, p90, p99 and max) are reported in /api/status,
+// /api/latency and on expanded dashboard cards.
+//
+// Each window uses a fixed amount of memory per endpoint regardless of how
+// many checks it sees. Defaults to 1 hour and 24 hours.
+//
+// Returns an error if no windows are given or a window is not positive.
+func WithLatencyWindows(windows ...time.Duration) Option {
+	return func(cfg *pbConfig) error {
+		if len(windows) == 0 {
+			return errors.New("at least one latency window is required")
+		}
+		for _, w := range windows {
+			if w <= 0 {
+				return fmt.Errorf("latency window must be positive, got %s", w)
+			}
+		}
+		cfg.latencyWindows = windows
+		return nil
+	}
+}
+
+// WithStore selects where status and history are kept. Defaults to
+// [MemoryStore]; use [FileStore] to keep state across restarts.
+//
+// Example:
+//
+//	pb, err := pulseboard.New(
+//	    pulseboard.WithEndpoint(ep),
+//	    pulseboard.WithStore(pulseboard.FileStore("/var/lib/pulseboard")),
+//	)
+//
+// Returns an error if a file store has no directory.
+func WithStore(b StoreBackend) Option {
+	return func(cfg *pbConfig) error {
+		if b.file && b.dir == "" {
+			return errors.New("file store directory cannot be empty")
+		}
+		cfg.store = b
+		return nil
+	}
+}
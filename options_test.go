@@ -2,10 +2,22 @@ package pulseboard
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestNew_Valid(t *testing.T) {
@@ -388,3 +400,342 @@ func TestWithTitle_DefaultsToEmpty(t *testing.T) {
 		t.Errorf("title = %q, want empty string", pb.title)
 	}
 }
+
+func TestWithHistory(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+
+	tests := []struct {
+		name    string
+		samples int
+		maxAge  time.Duration
+		wantErr bool
+	}{
+		{"valid", 500, time.Hour, false},
+		{"no age limit", 500, 0, false},
+		{"zero samples", 0, time.Hour, true},
+		{"negative age", 500, -time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb, err := New(WithEndpoint(ep), WithHistory(tt.samples, tt.maxAge))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (pb.history.Samples != tt.samples || pb.history.MaxAge != tt.maxAge) {
+				t.Errorf("history = %+v, want %d samples, %s", pb.history, tt.samples, tt.maxAge)
+			}
+		})
+	}
+}
+
+func TestWithUptime(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+
+	tests := []struct {
+		name        string
+		credit      float64
+		windows     []time.Duration
+		wantWindows int
+		wantErr     bool
+	}{
+		{"default windows", 0.5, nil, 3, false},
+		{"custom windows", 0, []time.Duration{time.Hour, 90 * 24 * time.Hour}, 2, false},
+		{"full credit", 1, nil, 3, false},
+		{"credit above one", 1.5, nil, 0, true},
+		{"negative credit", -0.1, nil, 0, true},
+		{"zero window", 0.5, []time.Duration{0}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb, err := New(WithEndpoint(ep), WithUptime(tt.credit, tt.windows...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (pb.uptime.DegradedCredit != tt.credit || len(pb.uptime.Windows) != tt.wantWindows) {
+				t.Errorf("uptime = %+v, want credit %g and %d windows", pb.uptime, tt.credit, tt.wantWindows)
+			}
+		})
+	}
+}
+
+func TestWithLatencyWindows(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+
+	pb, err := New(WithEndpoint(ep), WithLatencyWindows(5*time.Minute, time.Hour))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if len(pb.latencyWindows) != 2 || pb.latencyWindows[0] != 5*time.Minute {
+		t.Errorf("latencyWindows = %v, want [5m 1h]", pb.latencyWindows)
+	}
+
+	if _, err := New(WithEndpoint(ep), WithLatencyWindows()); err == nil {
+		t.Error("New() expected error for no latency windows, got nil")
+	}
+	if _, err := New(WithEndpoint(ep), WithLatencyWindows(-time.Minute)); err == nil {
+		t.Error("New() expected error for negative latency window, got nil")
+	}
+}
+
+func TestWithHealthCheck(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+
+	tests := []struct {
+		name     string
+		path     string
+		rules    []HealthRule
+		wantPath string
+		wantErr  bool
+	}{
+		{"default path", "", nil, "/api/health", false},
+		{"custom path", "/healthz", []HealthRule{{Labels: map[string]string{"critical": "true"}}}, "/healthz", false},
+		{"relative path", "healthz", nil, "", true},
+		{"taken path", "/api/status", nil, "", true},
+		{"negative min up", "/healthz", []HealthRule{{MinUp: -1}}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb, err := New(WithEndpoint(ep), WithHealthCheck(tt.path, tt.rules...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (pb.healthPath != tt.wantPath || len(pb.healthRules) != len(tt.rules)) {
+				t.Errorf("health = %q %+v, want %q with %d rules", pb.healthPath, pb.healthRules, tt.wantPath, len(tt.rules))
+			}
+		})
+	}
+}
+
+func TestWithBasePath(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"/status", "/status", false},
+		{"/status/", "/status", false},
+		{"/", "", false},
+		{"status", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			pb, err := New(WithEndpoint(ep), WithBasePath(tt.path))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && pb.basePath != tt.want {
+				t.Errorf("basePath = %q, want %q", pb.basePath, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAuth(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+	hash, err := bcrypt.GenerateFromPassword([]byte("pw"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		auth    Auth
+		wantErr bool
+	}{
+		{"users", Auth{Users: map[string]string{"alice": string(hash)}}, false},
+		{"tokens", Auth{Tokens: []string{"t"}, Public: []string{"/api/health"}}, false},
+		{"nothing", Auth{}, true},
+		{"plain password", Auth{Users: map[string]string{"alice": "pw"}}, true},
+		{"empty token", Auth{Tokens: []string{""}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithEndpoint(ep), WithAuth(tt.auth))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithOIDC(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+	valid := OIDC{
+		Issuer:         "https://accounts.example.com",
+		ClientID:       "pulseboard",
+		RedirectURL:    "https://status.example.com/auth/callback",
+		AllowedDomains: []string{"example.com"},
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"valid", []Option{WithOIDC(valid)}, false},
+		{"with public paths", []Option{WithOIDC(valid), WithAuth(Auth{Public: []string{"/api/health"}})}, false},
+		{"public paths alone", []Option{WithAuth(Auth{Public: []string{"/api/health"}})}, true},
+		{"no client", []Option{WithOIDC(OIDC{Issuer: valid.Issuer, RedirectURL: valid.RedirectURL})}, true},
+		{"wrong redirect", []Option{WithOIDC(OIDC{Issuer: valid.Issuer, ClientID: "pulseboard", RedirectURL: "https://status.example.com/"})}, true},
+		{"unrestricted", []Option{WithOIDC(OIDC{Issuer: valid.Issuer, ClientID: "pulseboard", RedirectURL: valid.RedirectURL})}, true},
+		{"any account", []Option{WithOIDC(OIDC{Issuer: valid.Issuer, ClientID: "pulseboard", RedirectURL: valid.RedirectURL, AllowAnyAccount: true})}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(append([]Option{WithEndpoint(ep)}, tt.opts...)...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithPublicPage(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"path", []Option{WithPublicPage(PublicPage{Path: "/public/"})}, false},
+		{"port", []Option{WithPublicPage(PublicPage{Port: 8081, Names: map[string]string{"Test": "Website"}})}, false},
+		{"neither", []Option{WithPublicPage(PublicPage{Path: "/"})}, true},
+		{"relative path", []Option{WithPublicPage(PublicPage{Path: "public"})}, true},
+		{"dashboard port", []Option{WithPublicPage(PublicPage{Port: defaultPort})}, true},
+		{"base path", []Option{WithBasePath("/status"), WithPublicPage(PublicPage{Path: "/status"})}, true},
+		{"duplicate names", []Option{WithPublicPage(PublicPage{Path: "/public", Names: map[string]string{"a": "A", "b": "A"}})}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(append([]Option{WithEndpoint(ep)}, tt.opts...)...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// writeTestCert writes a self-signed certificate for localhost and its key
+// to dir, returning the certificate, as PEM, and the file paths.
+func writeTestCert(t *testing.T, dir string) (certPEM []byte, certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	certFile, keyFile = filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPEM, certFile, keyFile
+}
+
+func TestWithTLS(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+	_, certFile, keyFile := writeTestCert(t, t.TempDir())
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"cert and key", []Option{WithTLS(certFile, keyFile)}, false},
+		{"client CA", []Option{WithTLS(certFile, keyFile), WithClientCA(certFile, true), WithoutHTTP2()}, false},
+		{"client CA first", []Option{WithClientCA(certFile, false), WithTLS(certFile, keyFile)}, false},
+		{"missing key", []Option{WithTLS(certFile, "")}, true},
+		{"key is not a key", []Option{WithTLS(certFile, certFile)}, true},
+		{"client CA is not PEM", []Option{WithTLS(certFile, keyFile), WithClientCA(keyFile, false)}, true},
+		{"empty client CA", []Option{WithTLS(certFile, keyFile), WithClientCA("", true)}, true},
+		{"client CA without TLS", []Option{WithClientCA(certFile, false)}, true},
+		{"HTTP/1.1 without TLS", []Option{WithoutHTTP2()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(append([]Option{WithEndpoint(ep)}, tt.opts...)...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadHTPasswd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "htpasswd")
+	content := "# users\nalice:$2y$05$abc\n\nbob:$2y$05$def\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := LoadHTPasswd(path)
+	if err != nil {
+		t.Fatalf("LoadHTPasswd() error = %v", err)
+	}
+	if len(users) != 2 || users["alice"] != "$2y$05$abc" || users["bob"] != "$2y$05$def" {
+		t.Errorf("users = %v, want alice and bob", users)
+	}
+
+	if err := os.WriteFile(path, []byte("alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHTPasswd(path); err == nil || !strings.Contains(err.Error(), ":1:") {
+		t.Errorf("LoadHTPasswd() error = %v, want line 1 error", err)
+	}
+}
+
+func TestWithStore(t *testing.T) {
+	ep, _ := NewEndpoint("Test", "https://example.com")
+
+	tests := []struct {
+		name    string
+		backend StoreBackend
+		wantErr bool
+	}{
+		{"memory", MemoryStore(), false},
+		{"file", FileStore(t.TempDir()), false},
+		{"file without directory", FileStore(""), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithEndpoint(ep), WithStore(tt.backend))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
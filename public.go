@@ -0,0 +1,99 @@
+package pulseboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jpalmerr/pulseboard/dashboard"
+	"github.com/jpalmerr/pulseboard/internal/server"
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+// PublicPage configures a redacted status page for customers. See
+// [WithPublicPage].
+type PublicPage struct {
+	// Path serves the page under this path, such as "/public", on the
+	// dashboard's port and from [PulseBoard.Handler]. With Port set, it is
+	// the page's base path on that port instead and may be empty.
+	Path string
+
+	// Port serves the page on a port of its own, without the internal
+	// dashboard, when started with [PulseBoard.Start].
+	Port int
+
+	// Title is the page's title. Defaults to the dashboard's title.
+	Title string
+
+	// Labels lists the label keys shown on the page. Other labels are
+	// hidden.
+	Labels []string
+
+	// Names maps endpoint names to the names shown on the page. Endpoints
+	// without an entry keep their names.
+	Names map[string]string
+}
+
+// WithPublicPage serves a public status page alongside the dashboard. The
+// page shows the same endpoints with everything that could reveal internal
+// details removed:
+//
+//   - endpoint URLs, TLS certificate details and retry errors are omitted
+//   - error messages are replaced by generic ones, such as "Timed out" or
+//     "Unreachable"
+//   - only the labels in page.Labels are shown
+//   - endpoints are shown by their names in page.Names, if given
+//
+// The page has no metrics or management API and is served without
+// authentication, even when [WithAuth] or [WithOIDC] protect the dashboard.
+//
+// Example:
+//
+//	pulseboard.WithPublicPage(pulseboard.PublicPage{
+//	    Port:   8081,
+//	    Title:  "Acme Status",
+//	    Labels: []string{"region"},
+//	    Names:  map[string]string{"payments-api-prod": "Payments"},
+//	})
+//
+// Returns an error if neither a path nor a port is given, the path does not
+// start with "/", the port is out of range, or two endpoints share a public
+// name.
+func WithPublicPage(page PublicPage) Option {
+	return func(cfg *pbConfig) error {
+		if page.Path != "" && !strings.HasPrefix(page.Path, "/") {
+			return fmt.Errorf("public page path must start with /, got %q", page.Path)
+		}
+		page.Path = strings.TrimSuffix(page.Path, "/")
+		if page.Port == 0 && page.Path == "" {
+			return errors.New("public page requires a path other than / or a port")
+		}
+		if page.Port < 0 || page.Port > 65535 {
+			return fmt.Errorf("public page port must be between 1 and 65535, got %d", page.Port)
+		}
+		if err := server.ValidatePublic(server.Public{Labels: page.Labels, Names: page.Names}); err != nil {
+			return err
+		}
+		cfg.public = &page
+		return nil
+	}
+}
+
+// newPublicServer creates the server of the public page, reading from st.
+// Must be called with mu held.
+func (pb *PulseBoard) newPublicServer(st store.Store) *server.Server {
+	title := pb.public.Title
+	if title == "" {
+		title = pb.title
+	}
+	srv := server.NewServer(st, pb.public.Port, dashboard.Assets, title, pb.logger)
+	srv.SetUptimeWindows(pb.uptime.Windows)
+	srv.SetBadgeMaxAge(pb.pollingInterval)
+	srv.SetBasePath(pb.public.Path)
+	srv.SetPublic(server.Public{Labels: pb.public.Labels, Names: pb.public.Names})
+	if pb.tls != nil {
+		// customers are not asked for client certificates
+		srv.EnableTLS(server.TLS{CertFile: pb.tls.CertFile, KeyFile: pb.tls.KeyFile, DisableHTTP2: pb.tls.DisableHTTP2})
+	}
+	return srv
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/jpalmerr/pulseboard/dashboard"
 	"github.com/jpalmerr/pulseboard/internal/poller"
+	"github.com/jpalmerr/pulseboard/internal/redact"
 	"github.com/jpalmerr/pulseboard/internal/server"
 	"github.com/jpalmerr/pulseboard/internal/store"
 )
@@ -43,14 +46,42 @@ const (
 // trigger graceful shutdown.
 type PulseBoard struct {
 	title           string
-	endpoints       []Endpoint
 	pollingInterval time.Duration
 	port            int
 	maxConcurrency  int
 	logger          *slog.Logger
 	statusCallbacks []func(StatusResult)
+	adminToken      string
+	history         store.HistoryLimits
+	uptime          store.UptimeOptions
+	latencyWindows  []time.Duration
+	healthPath      string
+	healthRules     []HealthRule
+	basePath        string
+	auth            Auth
+	oidc            *OIDC
+	public          *PublicPage
+	tls             *server.TLS
+	storeBackend    StoreBackend
+
+	// mu guards endpoints, redactor and the components created by Start or
+	// Run, which are nil while PulseBoard is not running
+	mu        sync.RWMutex
+	endpoints []Endpoint
+	redactor  *redact.Redactor
+	scheduler *poller.Scheduler
+	damper    *poller.Damper
+	store     store.Store
+	handler   http.Handler
+	runCtx    context.Context
 }
 
+// ErrEndpointNotFound is returned when changing an endpoint that does not exist.
+var ErrEndpointNotFound = errors.New("endpoint not found")
+
+// ErrDuplicateEndpoint is returned when adding an endpoint whose name is taken.
+var ErrDuplicateEndpoint = errors.New("duplicate endpoint name")
+
 // New creates a new [PulseBoard] instance with the given options.
 //
 // At least one endpoint must be configured via [WithEndpoint] or [WithEndpoints].
@@ -74,6 +105,15 @@ func New(opts ...Option) (*PulseBoard, error) {
 		pollingInterval: defaultPollingInterval,
 		port:            defaultPort,
 		maxConcurrency:  defaultMaxConcurrency,
+		history: store.HistoryLimits{
+			Samples: store.DefaultHistorySamples,
+			MaxAge:  store.DefaultHistoryAge,
+		},
+		uptime: store.UptimeOptions{
+			Windows:        store.DefaultUptimeWindows,
+			DegradedCredit: store.DefaultDegradedCredit,
+		},
+		latencyWindows: store.DefaultLatencyWindows,
 	}
 
 	for _, opt := range opts {
@@ -90,15 +130,32 @@ func New(opts ...Option) (*PulseBoard, error) {
 	seen := make(map[string]bool, len(cfg.endpoints))
 	for _, ep := range cfg.endpoints {
 		if seen[ep.name] {
-			return nil, fmt.Errorf("duplicate endpoint name: %q", ep.name)
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateEndpoint, ep.name)
 		}
 		seen[ep.name] = true
 	}
 
+	if len(cfg.auth.Users) == 0 && len(cfg.auth.Tokens) == 0 && len(cfg.auth.Public) > 0 && cfg.oidc == nil {
+		return nil, errors.New("auth requires at least one user or token, or OIDC")
+	}
+
 	if cfg.port < 1 || cfg.port > 65535 {
 		return nil, fmt.Errorf("port must be between 1 and 65535, got %d", cfg.port)
 	}
 
+	if p := cfg.public; p != nil {
+		if p.Port == cfg.port {
+			return nil, fmt.Errorf("public page port %d is the dashboard's port; set a path instead", p.Port)
+		}
+		if p.Port == 0 && p.Path == cfg.basePath {
+			return nil, fmt.Errorf("public page path %q is the dashboard's base path", p.Path)
+		}
+	}
+
+	if err := validateTLS(cfg.tls); err != nil {
+		return nil, err
+	}
+
 	// default to slog.Default() if no logger provided
 	logger := cfg.logger
 	if logger == nil {
@@ -113,6 +170,19 @@ func New(opts ...Option) (*PulseBoard, error) {
 		maxConcurrency:  cfg.maxConcurrency,
 		logger:          logger,
 		statusCallbacks: cfg.statusCallbacks,
+		adminToken:      cfg.adminToken,
+		history:         cfg.history,
+		uptime:          cfg.uptime,
+		latencyWindows:  cfg.latencyWindows,
+		healthPath:      cfg.healthPath,
+		healthRules:     cfg.healthRules,
+		basePath:        cfg.basePath,
+		auth:            cfg.auth,
+		oidc:            cfg.oidc,
+		public:          cfg.public,
+		tls:             cfg.tls,
+		redactor:        redact.New(cfg.secrets),
+		storeBackend:    cfg.store,
 	}, nil
 }
 
@@ -124,7 +194,8 @@ func New(opts ...Option) (*PulseBoard, error) {
 //   - All configured endpoints are polled immediately, then at the configured interval
 //   - The HTTP server starts on the configured port
 //   - Poll results are logged to stdout
-//   - The dashboard is available at http://localhost:<port>
+//   - The dashboard is available at http://localhost:<port>, or https with
+//     [WithTLS], under the base path set by [WithBasePath]
 //
 // The caller controls the lifecycle via context cancellation. For signal handling,
 // use [signal.NotifyContext]:
@@ -133,20 +204,97 @@ func New(opts ...Option) (*PulseBoard, error) {
 //	defer cancel()
 //	pb.Start(ctx)
 //
-// Returns nil on graceful shutdown. Returns an error if the HTTP server fails to start.
+// Endpoints can be changed while running with [PulseBoard.AddEndpoint],
+// [PulseBoard.UpdateEndpoint] and [PulseBoard.RemoveEndpoint].
+//
+// To serve the dashboard from an existing HTTP server instead, use
+// [PulseBoard.Run] and [PulseBoard.Handler].
+//
+// Returns nil on graceful shutdown. Returns an error if the store cannot be
+// opened or the HTTP server fails to start.
 func (pb *PulseBoard) Start(ctx context.Context) error {
+	return pb.run(ctx, true)
+}
+
+// Run polls endpoints like [PulseBoard.Start] but does not listen for HTTP
+// requests; the dashboard is served by [PulseBoard.Handler] on the
+// caller's own server. Run blocks until ctx is cancelled.
+//
+// Returns nil on graceful shutdown, or an error if the store cannot be
+// opened.
+func (pb *PulseBoard) Run(ctx context.Context) error {
+	return pb.run(ctx, false)
+}
+
+// run polls endpoints until ctx is cancelled, serving the dashboard on the
+// configured port if listen is set.
+func (pb *PulseBoard) run(ctx context.Context, listen bool) error {
+	pb.mu.Lock()
 	pb.logger.Info("pulseboard starting", "endpoint_count", len(pb.endpoints))
 	pb.logger.Info("polling configured", "interval", pb.pollingInterval.String())
-	pb.logger.Info("dashboard available", "url", fmt.Sprintf("http://localhost:%d", pb.port))
+	if listen {
+		pb.logger.Info("dashboard available", "url", fmt.Sprintf("%s://localhost:%d%s/", pb.scheme(), pb.port, pb.basePath))
+	}
 
 	if ctx.Err() != nil {
+		pb.mu.Unlock()
 		return nil
 	}
 
+	uptime := pb.uptime
+	uptime.MaxGap = pb.uptimeMaxGap()
+	statusStore, err := pb.storeBackend.open(pb.history, uptime, pb.latencyWindows, pb.logger)
+	if err != nil {
+		pb.mu.Unlock()
+		return err
+	}
+	// drop persisted state of endpoints that are no longer configured
+	for _, r := range statusStore.GetAll() {
+		if pb.indexOf(r.Name) < 0 {
+			statusStore.Delete(r.Name)
+		}
+	}
+
 	pollerEndpoints := pb.toPollerEndpoints()
-	statusStore := store.NewMemoryStore()
 	scheduler := poller.NewScheduler(pollerEndpoints, pb.pollingInterval, pb.maxConcurrency, pb.logger)
+
+	httpServer := server.NewServer(statusStore, pb.port, dashboard.Assets, pb.title, pb.logger)
+	httpServer.EnableEndpointAPI(endpointManager{pb}, pb.adminToken)
+	httpServer.SetUptimeWindows(pb.uptime.Windows)
+	httpServer.SetPollStats(scheduler)
+	httpServer.SetBadgeMaxAge(pb.pollingInterval)
+	httpServer.EnableHealth(pb.healthPath, toServerHealthRules(pb.healthRules))
+	httpServer.SetBasePath(pb.basePath)
+	httpServer.EnableAuth(server.Auth(pb.auth))
+	if pb.oidc != nil {
+		httpServer.EnableOIDC(server.OIDC(*pb.oidc))
+	}
+	if pb.tls != nil {
+		httpServer.EnableTLS(*pb.tls)
+	}
+	var publicServer *server.Server
+	if pb.public != nil {
+		publicServer = pb.newPublicServer(statusStore)
+		if pb.public.Port == 0 {
+			httpServer.MountPublic(publicServer)
+		} else if listen {
+			pb.logger.Info("public page available", "url", fmt.Sprintf("%s://localhost:%d%s/", pb.scheme(), pb.public.Port, pb.public.Path))
+		}
+	}
+	handler, err := httpServer.Handler()
+	if err != nil {
+		pb.mu.Unlock()
+		if err := closeStore(statusStore); err != nil {
+			pb.logger.Error("failed to close store", "error", err)
+		}
+		return fmt.Errorf("failed to start HTTP server: %w", err)
+	}
+
 	scheduler.Start(ctx)
+	damper := poller.NewDamper(pollerEndpoints)
+	pb.scheduler, pb.damper, pb.store = scheduler, damper, statusStore
+	pb.handler, pb.runCtx = handler, ctx
+	pb.mu.Unlock()
 
 	// track the results consumer goroutine to ensure clean shutdown
 	var wg sync.WaitGroup
@@ -154,9 +302,20 @@ func (pb *PulseBoard) Start(ctx context.Context) error {
 	go func() {
 		defer wg.Done()
 		for result := range scheduler.Results() {
+			// hold the read lock so a concurrent removal cannot interleave
+			// with publishing a result for the endpoint being removed
+			pb.mu.RLock()
+			if pb.indexOf(result.EndpointName) < 0 {
+				pb.mu.RUnlock()
+				continue
+			}
+			result = damper.Apply(result)
+			result = redactResult(pb.redactor, result)
+
 			// store update first (callbacks fire after data is persisted)
 			storeResult := pollerResultToStoreResult(result)
 			statusStore.Update(storeResult)
+			pb.mu.RUnlock()
 
 			if len(pb.statusCallbacks) > 0 {
 				publicResult := pollerResultToPublicResult(result)
@@ -168,6 +327,7 @@ func (pb *PulseBoard) Start(ctx context.Context) error {
 			// log poll results (DEBUG level for success to reduce noise)
 			logAttrs := []any{
 				"status", result.Status,
+				"raw_status", result.RawStatus,
 				"endpoint", result.EndpointName,
 				"url", result.URL,
 				"latency_ms", result.Latency.Milliseconds(),
@@ -184,12 +344,34 @@ func (pb *PulseBoard) Start(ctx context.Context) error {
 	cleanup := func() {
 		scheduler.Stop() // closes results channel
 		wg.Wait()        // wait for all results to be processed
+
+		if err := closeStore(statusStore); err != nil {
+			pb.logger.Error("failed to close store", "error", err)
+		}
+
+		pb.mu.Lock()
+		pb.scheduler, pb.damper, pb.store = nil, nil, nil
+		pb.handler, pb.runCtx = nil, nil
+		pb.mu.Unlock()
 	}
 
-	httpServer := server.NewServer(statusStore, pb.port, dashboard.Assets, pb.title, pb.logger)
-	if err := httpServer.Start(ctx); err != nil {
-		cleanup()
-		return fmt.Errorf("failed to start HTTP server: %w", err)
+	if listen {
+		// stop the dashboard's server if the public page's cannot start
+		serveCtx, stopServing := context.WithCancel(ctx)
+		defer stopServing()
+		// serve the handler shared with pb.Handler, so both accept the
+		// same session cookies
+		if err := httpServer.Serve(serveCtx, handler); err != nil {
+			cleanup()
+			return fmt.Errorf("failed to start HTTP server: %w", err)
+		}
+		if publicServer != nil && pb.public.Port != 0 {
+			if err := publicServer.Start(serveCtx); err != nil {
+				stopServing()
+				cleanup()
+				return fmt.Errorf("failed to start public page server: %w", err)
+			}
+		}
 	}
 
 	<-ctx.Done()
@@ -198,33 +380,91 @@ func (pb *PulseBoard) Start(ctx context.Context) error {
 	return nil
 }
 
+// uptimeMaxGap returns the longest gap between results that uptime
+// attributes to the earlier status: three of the longest polling intervals,
+// so a missed poll or two is tolerated but downtime of PulseBoard itself is
+// not counted. Must be called with mu held.
+func (pb *PulseBoard) uptimeMaxGap() time.Duration {
+	longest := pb.pollingInterval
+	for _, ep := range pb.endpoints {
+		longest = max(longest, ep.interval)
+	}
+	return max(3*longest, store.DefaultUptimeMaxGap)
+}
+
 // toPollerEndpoints converts Endpoint slice to poller.EndpointInfo slice.
+// Must be called with mu held.
 func (pb *PulseBoard) toPollerEndpoints() []poller.EndpointInfo {
 	result := make([]poller.EndpointInfo, len(pb.endpoints))
-
 	for i, ep := range pb.endpoints {
-		var extractor poller.StatusExtractor
-		if ep.extractor != nil {
-			// wrap the pulseboard extractor to return string
-			pbExtractor := ep.extractor
-			extractor = func(body []byte, statusCode int) string {
-				return pbExtractor(body, statusCode).String()
-			}
-		}
+		result[i] = toPollerEndpoint(ep)
+	}
+	return result
+}
 
-		result[i] = poller.EndpointInfo{
-			Name:      ep.name,
-			URL:       ep.url,
-			Labels:    copyMap(ep.labels),
-			Headers:   copyMap(ep.headers),
-			Timeout:   ep.timeout,
-			Extractor: extractor,
-			Method:    ep.method,
-			Interval:  ep.interval,
+// toPollerEndpoint converts an Endpoint to a poller.EndpointInfo.
+func toPollerEndpoint(ep Endpoint) poller.EndpointInfo {
+	var extractor poller.StatusExtractor
+	if ep.extractor != nil {
+		// wrap the pulseboard extractor to return string
+		pbExtractor := ep.extractor
+		extractor = func(body []byte, statusCode int) string {
+			return pbExtractor(body, statusCode).String()
 		}
 	}
 
-	return result
+	info := poller.EndpointInfo{
+		Name:      ep.name,
+		URL:       ep.url,
+		Labels:    copyMap(ep.labels),
+		Headers:   copyMap(ep.headers),
+		Timeout:   ep.timeout,
+		Extractor: extractor,
+		Method:    ep.method,
+		GraphQL:   ep.graphQL,
+		Interval:  ep.interval,
+		Type:      string(ep.Type()),
+		TLS:       poller.TLSOptions{WarnWithin: ep.certWarning},
+
+		FailureThreshold: ep.FailureThreshold(),
+		SuccessThreshold: ep.SuccessThreshold(),
+		Flap: poller.FlapOptions{
+			Transitions: ep.damping.flapTransitions,
+			Window:      ep.damping.flapWindow,
+		},
+		Retry: poller.RetryOptions{
+			Retries:      ep.retry.retries,
+			Backoff:      ep.retry.backoff,
+			ShareTimeout: ep.retry.shareTimeout,
+		},
+		Latency: poller.LatencyThresholds{
+			Degraded: ep.latency.degraded,
+			Down:     ep.latency.down,
+		},
+	}
+	if ep.body != "" {
+		info.Body = []byte(ep.body)
+	}
+	switch ep.endpointType {
+	case EndpointTypeTCP:
+		info.TCP = poller.TCPOptions{
+			Send:   []byte(ep.send),
+			Expect: []byte(ep.expect),
+		}
+	case EndpointTypeDNS:
+		info.DNS = poller.DNSOptions{
+			Server:     ep.resolver,
+			RecordType: ep.recordType,
+			Expect:     ep.ExpectedAnswers(),
+		}
+	case EndpointTypeGRPC:
+		info.GRPC = poller.GRPCOptions{
+			Service:    ep.grpcService,
+			Insecure:   ep.insecure,
+			SkipVerify: ep.tlsSkipVerify,
+		}
+	}
+	return info
 }
 
 // Endpoints returns a copy of the configured endpoints.
@@ -232,11 +472,100 @@ func (pb *PulseBoard) toPollerEndpoints() []poller.EndpointInfo {
 // The returned slice is a copy; modifying it does not affect the PulseBoard.
 // Each [Endpoint] in the slice is immutable.
 func (pb *PulseBoard) Endpoints() []Endpoint {
+	pb.mu.RLock()
+	defer pb.mu.RUnlock()
+
 	cp := make([]Endpoint, len(pb.endpoints))
 	copy(cp, pb.endpoints)
 	return cp
 }
 
+// AddEndpoint adds an endpoint to the polling list.
+//
+// AddEndpoint is safe to call while [PulseBoard.Start] is running, in which
+// case the endpoint is polled immediately. Returns an error wrapping
+// [ErrDuplicateEndpoint] if an endpoint with the same name exists.
+func (pb *PulseBoard) AddEndpoint(ep Endpoint) error {
+	if ep.name == "" {
+		return errors.New("endpoint name is required")
+	}
+
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	if pb.indexOf(ep.name) >= 0 {
+		return fmt.Errorf("%w: %q", ErrDuplicateEndpoint, ep.name)
+	}
+	if pb.scheduler != nil {
+		info := toPollerEndpoint(ep)
+		if err := pb.scheduler.Add(info); err != nil {
+			return err
+		}
+		pb.damper.Set(info)
+	}
+	pb.endpoints = append(pb.endpoints, ep)
+	return nil
+}
+
+// UpdateEndpoint replaces the endpoint with the same name as ep.
+//
+// UpdateEndpoint is safe to call while [PulseBoard.Start] is running, in
+// which case the endpoint is polled immediately with its new configuration
+// and threshold and flap state start afresh. Returns an error wrapping
+// [ErrEndpointNotFound] if no endpoint has that name.
+func (pb *PulseBoard) UpdateEndpoint(ep Endpoint) error {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	i := pb.indexOf(ep.name)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrEndpointNotFound, ep.name)
+	}
+	if pb.scheduler != nil {
+		info := toPollerEndpoint(ep)
+		if err := pb.scheduler.Update(info); err != nil {
+			return err
+		}
+		pb.damper.Set(info)
+	}
+	pb.endpoints[i] = ep
+	return nil
+}
+
+// RemoveEndpoint stops polling the named endpoint.
+//
+// RemoveEndpoint is safe to call while [PulseBoard.Start] is running, in
+// which case the endpoint's status is dropped and connected dashboards
+// remove its card. Returns an error wrapping [ErrEndpointNotFound] if no
+// endpoint has that name.
+func (pb *PulseBoard) RemoveEndpoint(name string) error {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	i := pb.indexOf(name)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrEndpointNotFound, name)
+	}
+	if pb.scheduler != nil {
+		pb.scheduler.Remove(name)
+		pb.damper.Remove(name)
+		pb.store.Delete(name)
+	}
+	pb.endpoints = slices.Delete(pb.endpoints, i, i+1)
+	return nil
+}
+
+// indexOf returns the position of the named endpoint, or -1.
+// Must be called with mu held.
+func (pb *PulseBoard) indexOf(name string) int {
+	for i, ep := range pb.endpoints {
+		if ep.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 // Port returns the configured HTTP port for the dashboard server.
 func (pb *PulseBoard) Port() int {
 	return pb.port
@@ -261,8 +590,38 @@ func pollerResultToStoreResult(pr poller.StatusResult) store.StatusResult {
 		Status:         pr.Status,
 		Labels:         pr.Labels,
 		ResponseTimeMs: pr.Latency.Milliseconds(),
+		NoResponse:     poller.IsTransportError(pr.Error),
 		CheckedAt:      pr.CheckedAt,
 		Error:          errStr,
+		Cert:           pollerCertToStoreCert(pr.Cert),
+		RawStatus:      pr.RawStatus,
+		Flapping:       pr.Flapping,
+		Attempts:       pr.Attempts,
+		AttemptErrors:  errorStrings(pr.AttemptErrors),
+	}
+}
+
+// errorStrings returns the messages of errs, or nil if errs is empty.
+func errorStrings(errs []error) []string {
+	if len(errs) == 0 {
+		return nil
+	}
+	out := make([]string, len(errs))
+	for i, err := range errs {
+		out[i] = err.Error()
+	}
+	return out
+}
+
+// pollerCertToStoreCert converts certificate details for the store.
+func pollerCertToStoreCert(c *poller.CertInfo) *store.CertInfo {
+	if c == nil {
+		return nil
+	}
+	return &store.CertInfo{
+		NotAfter: c.NotAfter,
+		Issuer:   c.Issuer,
+		DNSNames: append([]string(nil), c.DNSNames...),
 	}
 }
 
@@ -270,15 +629,32 @@ func pollerResultToStoreResult(pr poller.StatusResult) store.StatusResult {
 // Creates defensive copies of mutable fields to prevent data races.
 func pollerResultToPublicResult(pr poller.StatusResult) StatusResult {
 	return StatusResult{
-		EndpointName: pr.EndpointName,
-		URL:          pr.URL,
-		Status:       Status(pr.Status),
-		Labels:       copyMap(pr.Labels),
-		Latency:      pr.Latency,
-		CheckedAt:    pr.CheckedAt,
-		Error:        pr.Error,
-		RawResponse:  copyBytes(pr.RawResponse),
-		StatusCode:   pr.StatusCode,
+		EndpointName:  pr.EndpointName,
+		URL:           pr.URL,
+		Status:        Status(pr.Status),
+		Labels:        copyMap(pr.Labels),
+		Latency:       pr.Latency,
+		CheckedAt:     pr.CheckedAt,
+		Error:         pr.Error,
+		RawResponse:   copyBytes(pr.RawResponse),
+		StatusCode:    pr.StatusCode,
+		Cert:          pollerCertToPublicCert(pr.Cert),
+		RawStatus:     Status(pr.RawStatus),
+		Flapping:      pr.Flapping,
+		Attempts:      pr.Attempts,
+		AttemptErrors: append([]error(nil), pr.AttemptErrors...),
+	}
+}
+
+// pollerCertToPublicCert converts certificate details to the public API type.
+func pollerCertToPublicCert(c *poller.CertInfo) *CertInfo {
+	if c == nil {
+		return nil
+	}
+	return &CertInfo{
+		NotAfter: c.NotAfter,
+		Issuer:   c.Issuer,
+		DNSNames: append([]string(nil), c.DNSNames...),
 	}
 }
 
@@ -1,6 +1,15 @@
 package pulseboard
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/poller"
+	"github.com/jpalmerr/pulseboard/internal/redact"
+	"github.com/jpalmerr/pulseboard/internal/server"
+)
 
 func TestToPollerEndpoints_LabelsCopied(t *testing.T) {
 	ep, err := NewEndpoint("Test", "https://example.com",
@@ -113,3 +122,496 @@ func TestToPollerEndpoints_NilHeaders(t *testing.T) {
 		t.Errorf("expected nil or empty headers, got %v", headers)
 	}
 }
+
+func TestToPollerEndpoints_TCP(t *testing.T) {
+	ep, err := NewTCPEndpoint("Mail", "mail.internal:25", WithExpect("220"))
+	if err != nil {
+		t.Fatalf("NewTCPEndpoint() error = %v", err)
+	}
+
+	pb, err := New(WithEndpoint(ep), WithPort(19100))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	info := pb.toPollerEndpoints()[0]
+	if info.Type != "tcp" {
+		t.Errorf("Type = %q, want %q", info.Type, "tcp")
+	}
+	if info.URL != "tcp://mail.internal:25" {
+		t.Errorf("URL = %q, want %q", info.URL, "tcp://mail.internal:25")
+	}
+	if string(info.TCP.Expect) != "220" {
+		t.Errorf("TCP.Expect = %q, want %q", info.TCP.Expect, "220")
+	}
+	if len(info.TCP.Send) != 0 {
+		t.Errorf("TCP.Send = %q, want empty", info.TCP.Send)
+	}
+}
+
+func TestToPollerEndpoints_TLS(t *testing.T) {
+	ep, err := NewTLSEndpoint("Mail", "mail.example.com:465", WithCertExpiryWarning(7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("NewTLSEndpoint() error = %v", err)
+	}
+
+	pb, err := New(WithEndpoint(ep), WithPort(19101))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	info := pb.toPollerEndpoints()[0]
+	if info.Type != "tls" {
+		t.Errorf("Type = %q, want %q", info.Type, "tls")
+	}
+	if info.TLS.WarnWithin != 7*24*time.Hour {
+		t.Errorf("TLS.WarnWithin = %v, want 168h", info.TLS.WarnWithin)
+	}
+}
+
+func TestToPollerEndpoints_DNS(t *testing.T) {
+	ep, err := NewDNSEndpoint("API DNS", "api.example.com",
+		WithRecordType("AAAA"),
+		WithResolver("10.0.0.2:53"),
+		WithExpectedAnswers("2001:db8::1"),
+	)
+	if err != nil {
+		t.Fatalf("NewDNSEndpoint() error = %v", err)
+	}
+
+	pb, err := New(WithEndpoint(ep), WithPort(19102))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	info := pb.toPollerEndpoints()[0]
+	if info.Type != "dns" {
+		t.Errorf("Type = %q, want %q", info.Type, "dns")
+	}
+	if info.DNS.Server != "10.0.0.2:53" || info.DNS.RecordType != "AAAA" {
+		t.Errorf("DNS = %+v, want server 10.0.0.2:53 and type AAAA", info.DNS)
+	}
+	if len(info.DNS.Expect) != 1 || info.DNS.Expect[0] != "2001:db8::1" {
+		t.Errorf("DNS.Expect = %v, want [2001:db8::1]", info.DNS.Expect)
+	}
+}
+
+func TestToPollerEndpoints_GRPC(t *testing.T) {
+	ep, err := NewGRPCEndpoint("Orders", "orders.internal:50051",
+		WithGRPCService("orders.v1.OrderService"),
+		WithTLSSkipVerify(),
+		WithHeaders("x-api-key", "secret"),
+	)
+	if err != nil {
+		t.Fatalf("NewGRPCEndpoint() error = %v", err)
+	}
+
+	pb, err := New(WithEndpoint(ep), WithPort(19103))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	info := pb.toPollerEndpoints()[0]
+	if info.Type != "grpc" {
+		t.Errorf("Type = %q, want %q", info.Type, "grpc")
+	}
+	if info.GRPC.Service != "orders.v1.OrderService" || !info.GRPC.SkipVerify || info.GRPC.Insecure {
+		t.Errorf("GRPC = %+v, want service with skip verify", info.GRPC)
+	}
+	if info.Headers["x-api-key"] != "secret" {
+		t.Errorf("Headers[x-api-key] = %q, want metadata passed through", info.Headers["x-api-key"])
+	}
+}
+
+func TestToPollerEndpoints_Body(t *testing.T) {
+	gql, err := NewEndpoint("GraphQL", "https://api.example.com/graphql", WithGraphQL("{ ok }", nil))
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+	plain, err := NewEndpoint("Plain", "https://api.example.com/health")
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	pb, err := New(WithEndpoints(gql, plain), WithPort(19104))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	infos := pb.toPollerEndpoints()
+	if string(infos[0].Body) != `{"query":"{ ok }"}` || !infos[0].GraphQL {
+		t.Errorf("GraphQL endpoint: Body = %q, GraphQL = %v", infos[0].Body, infos[0].GraphQL)
+	}
+	if infos[0].Method != "POST" {
+		t.Errorf("GraphQL endpoint: Method = %q, want POST", infos[0].Method)
+	}
+	if infos[1].Body != nil || infos[1].GraphQL {
+		t.Errorf("plain endpoint: Body = %q, GraphQL = %v, want none", infos[1].Body, infos[1].GraphQL)
+	}
+}
+
+func TestToPollerEndpoints_Damping(t *testing.T) {
+	ep, err := NewEndpoint("API", "https://api.example.com",
+		WithFailureThreshold(3),
+		WithFlapDetection(4, 5*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	pb, err := New(WithEndpoint(ep), WithPort(19105))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	info := pb.toPollerEndpoints()[0]
+	if info.FailureThreshold != 3 || info.SuccessThreshold != 1 {
+		t.Errorf("thresholds = %d/%d, want 3/1", info.FailureThreshold, info.SuccessThreshold)
+	}
+	if info.Flap.Transitions != 4 || info.Flap.Window != 5*time.Minute {
+		t.Errorf("Flap = %+v, want 4 transitions in 5m", info.Flap)
+	}
+}
+
+func TestToPollerEndpoints_Retry(t *testing.T) {
+	ep, err := NewEndpoint("API", "https://api.example.com",
+		WithRetries(3, time.Second),
+		WithSharedRetryTimeout(),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	pb, err := New(WithEndpoint(ep), WithPort(19106))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	want := poller.RetryOptions{Retries: 3, Backoff: time.Second, ShareTimeout: true}
+	if got := pb.toPollerEndpoints()[0].Retry; got != want {
+		t.Errorf("Retry = %+v, want %+v", got, want)
+	}
+}
+
+func TestToPollerEndpoints_LatencyThresholds(t *testing.T) {
+	ep, err := NewEndpoint("API", "https://api.example.com",
+		WithLatencyThresholds(time.Second, 3*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	pb, err := New(WithEndpoint(ep), WithPort(19107))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	want := poller.LatencyThresholds{Degraded: time.Second, Down: 3 * time.Second}
+	if got := pb.toPollerEndpoints()[0].Latency; got != want {
+		t.Errorf("Latency = %+v, want %+v", got, want)
+	}
+}
+
+func TestPollerResultConversion_Attempts(t *testing.T) {
+	pr := poller.StatusResult{
+		EndpointName:  "API",
+		Status:        "up",
+		Attempts:      3,
+		AttemptErrors: []error{errors.New("HTTP 502"), errors.New("HTTP 503")},
+	}
+
+	storeResult := pollerResultToStoreResult(pr)
+	if storeResult.Attempts != 3 || len(storeResult.AttemptErrors) != 2 || storeResult.AttemptErrors[1] != "HTTP 503" {
+		t.Errorf("store result attempts = %d, errors = %v", storeResult.Attempts, storeResult.AttemptErrors)
+	}
+
+	public := pollerResultToPublicResult(pr)
+	if public.Attempts != 3 || len(public.AttemptErrors) != 2 {
+		t.Errorf("public result attempts = %d, errors = %v", public.Attempts, public.AttemptErrors)
+	}
+}
+
+func TestPollerResultConversion_NoResponse(t *testing.T) {
+	refused := poller.StatusResult{
+		EndpointName: "API",
+		Status:       "down",
+		Error:        &poller.TransportError{Err: errors.New("connect failed: connection refused")},
+	}
+	if got := pollerResultToStoreResult(refused); !got.NoResponse {
+		t.Errorf("NoResponse = false for %v, want true", refused.Error)
+	}
+
+	slow := poller.StatusResult{
+		EndpointName: "API",
+		Status:       "degraded",
+		StatusCode:   200,
+		Latency:      2 * time.Second,
+		Error:        errors.New("latency 2s > 1s"),
+	}
+	if got := pollerResultToStoreResult(slow); got.NoResponse {
+		t.Errorf("NoResponse = true for %v, want false", slow.Error)
+	}
+}
+
+func TestRedactResult(t *testing.T) {
+	r := redact.New([]string{"envsecretvalue"})
+	result := redactResult(r, poller.StatusResult{
+		EndpointName:  "API",
+		URL:           "https://api.example.com/v1/envsecretvalue/health?api_key=inlinekey",
+		Error:         errors.New("unexpected answer envsecretvalue"),
+		AttemptErrors: []error{errors.New("attempt with envsecretvalue failed")},
+		RawResponse:   []byte(`{"error": "bad key envsecretvalue", "retry": "https://api.example.com/?token=t0k3n"}`),
+	})
+
+	outputs := map[string]string{
+		"URL":           result.URL,
+		"Error":         result.Error.Error(),
+		"AttemptErrors": result.AttemptErrors[0].Error(),
+		"RawResponse":   string(result.RawResponse),
+	}
+	for name, out := range outputs {
+		for _, secret := range []string{"envsecretvalue", "inlinekey", "t0k3n"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("%s leaks %q: %s", name, secret, out)
+			}
+		}
+	}
+}
+
+func TestPollerResultConversion_Damping(t *testing.T) {
+	pr := poller.StatusResult{
+		EndpointName: "API",
+		Status:       "up",
+		RawStatus:    "down",
+		Flapping:     true,
+	}
+
+	storeResult := pollerResultToStoreResult(pr)
+	if storeResult.Status != "up" || storeResult.RawStatus != "down" || !storeResult.Flapping {
+		t.Errorf("store result = %+v, want published up, raw down, flapping", storeResult)
+	}
+
+	public := pollerResultToPublicResult(pr)
+	if public.Status != StatusUp || public.RawStatus != StatusDown || !public.Flapping {
+		t.Errorf("public result = %+v, want published up, raw down, flapping", public)
+	}
+}
+
+func TestPollerResultConversion_Cert(t *testing.T) {
+	notAfter := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+	pr := poller.StatusResult{
+		EndpointName: "Mail",
+		Status:       "degraded",
+		Cert: &poller.CertInfo{
+			NotAfter: notAfter,
+			Issuer:   "CN=Test CA",
+			DNSNames: []string{"mail.example.com"},
+		},
+	}
+
+	storeResult := pollerResultToStoreResult(pr)
+	if storeResult.Cert == nil || !storeResult.Cert.NotAfter.Equal(notAfter) {
+		t.Errorf("store Cert = %+v, want NotAfter %v", storeResult.Cert, notAfter)
+	}
+
+	public := pollerResultToPublicResult(pr)
+	if public.Cert == nil || public.Cert.Issuer != "CN=Test CA" {
+		t.Fatalf("public Cert = %+v, want issuer CN=Test CA", public.Cert)
+	}
+
+	// the public copy must not alias the poller's slice
+	public.Cert.DNSNames[0] = "changed"
+	if pr.Cert.DNSNames[0] != "mail.example.com" {
+		t.Error("public Cert.DNSNames aliases poller result")
+	}
+
+	if got := pollerResultToPublicResult(poller.StatusResult{}).Cert; got != nil {
+		t.Errorf("Cert = %+v, want nil when not checked", got)
+	}
+}
+
+func TestEndpointChanges_BeforeStart(t *testing.T) {
+	a, _ := NewEndpoint("A", "https://a.example.com")
+	b, _ := NewEndpoint("B", "https://b.example.com")
+	pb, err := New(WithEndpoint(a), WithPort(19108))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := pb.AddEndpoint(b); err != nil {
+		t.Fatalf("AddEndpoint() error = %v", err)
+	}
+	if err := pb.AddEndpoint(b); !errors.Is(err, ErrDuplicateEndpoint) {
+		t.Errorf("AddEndpoint() duplicate error = %v, want ErrDuplicateEndpoint", err)
+	}
+	if err := pb.AddEndpoint(Endpoint{}); err == nil {
+		t.Error("AddEndpoint() of zero Endpoint should fail")
+	}
+
+	b2, _ := NewEndpoint("B", "https://b2.example.com")
+	if err := pb.UpdateEndpoint(b2); err != nil {
+		t.Fatalf("UpdateEndpoint() error = %v", err)
+	}
+	missing, _ := NewEndpoint("C", "https://c.example.com")
+	if err := pb.UpdateEndpoint(missing); !errors.Is(err, ErrEndpointNotFound) {
+		t.Errorf("UpdateEndpoint() missing error = %v, want ErrEndpointNotFound", err)
+	}
+
+	if err := pb.RemoveEndpoint("A"); err != nil {
+		t.Fatalf("RemoveEndpoint() error = %v", err)
+	}
+	if err := pb.RemoveEndpoint("A"); !errors.Is(err, ErrEndpointNotFound) {
+		t.Errorf("RemoveEndpoint() missing error = %v, want ErrEndpointNotFound", err)
+	}
+
+	eps := pb.Endpoints()
+	if len(eps) != 1 || eps[0].Name() != "B" || eps[0].URL() != "https://b2.example.com" {
+		t.Errorf("Endpoints() = %v, want only updated B", eps)
+	}
+}
+
+func TestEndpointFromSpec_KeepsAllSettings(t *testing.T) {
+	ep, err := endpointFromSpec(server.EndpointSpec{
+		Name:               "API",
+		URL:                "https://api.example.com/health",
+		Body:               `{"deep": true}`,
+		CertExpiryWarning:  "7d",
+		FailureThreshold:   3,
+		SuccessThreshold:   2,
+		FlapDetection:      &server.FlapDetectionSpec{Transitions: 4, Window: "10m"},
+		Retries:            2,
+		RetryBackoff:       "500ms",
+		RetrySharedTimeout: true,
+		LatencyThresholds:  &server.LatencyThresholdsSpec{Degraded: "2s", Down: "5s"},
+	})
+	if err != nil {
+		t.Fatalf("endpointFromSpec() error = %v", err)
+	}
+
+	if ep.Body() != `{"deep": true}` || ep.Method() != "POST" {
+		t.Errorf("Body(), Method() = %q, %q, want the body sent with POST", ep.Body(), ep.Method())
+	}
+	if ep.CertExpiryWarning() != 7*24*time.Hour {
+		t.Errorf("CertExpiryWarning() = %v, want 168h", ep.CertExpiryWarning())
+	}
+	if ep.FailureThreshold() != 3 || ep.SuccessThreshold() != 2 {
+		t.Errorf("thresholds = %d, %d, want 3, 2", ep.FailureThreshold(), ep.SuccessThreshold())
+	}
+	if transitions, window := ep.FlapDetection(); transitions != 4 || window != 10*time.Minute {
+		t.Errorf("FlapDetection() = %d, %v, want 4, 10m", transitions, window)
+	}
+	if retries, backoff := ep.Retries(); retries != 2 || backoff != 500*time.Millisecond || !ep.SharedRetryTimeout() {
+		t.Errorf("Retries() = %d, %v, shared %v, want 2, 500ms, shared", retries, backoff, ep.SharedRetryTimeout())
+	}
+	if degraded, down := ep.LatencyThresholds(); degraded != 2*time.Second || down != 5*time.Second {
+		t.Errorf("LatencyThresholds() = %v, %v, want 2s, 5s", degraded, down)
+	}
+}
+
+func TestEndpointFromSpec(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        server.EndpointSpec
+		wantType    EndpointType
+		wantErrLike string
+	}{
+		{
+			name: "http with options",
+			spec: server.EndpointSpec{
+				Name:      "API",
+				URL:       "https://api.example.com/health",
+				Labels:    map[string]string{"env": "prod"},
+				Timeout:   "3s",
+				Interval:  "1m",
+				Extractor: &server.ExtractorSpec{Type: "json", Path: "status"},
+			},
+			wantType: EndpointTypeHTTP,
+		},
+		{
+			name:     "tcp",
+			spec:     server.EndpointSpec{Name: "DB", URL: "db.internal:5432", Type: "tcp"},
+			wantType: EndpointTypeTCP,
+		},
+		{
+			name:     "tcp exchange",
+			spec:     server.EndpointSpec{Name: "Mail", URL: "mail.internal:25", Type: "tcp", Send: "EHLO pulseboard\r\n", Expect: "250"},
+			wantType: EndpointTypeTCP,
+		},
+		{
+			name:     "tls",
+			spec:     server.EndpointSpec{Name: "Cert", URL: "mail.example.com:465", Type: "tls", CertExpiryWarning: "14d"},
+			wantType: EndpointTypeTLS,
+		},
+		{
+			name: "dns",
+			spec: server.EndpointSpec{
+				Name: "API DNS", URL: "api.example.com", Type: "dns",
+				RecordType: "AAAA", Resolver: "10.0.0.2:53", ExpectedAnswers: []string{"2001:db8::1"},
+			},
+			wantType: EndpointTypeDNS,
+		},
+		{
+			name:     "grpc",
+			spec:     server.EndpointSpec{Name: "Users", URL: "users.internal:50051", Type: "grpc", Service: "users", Insecure: true},
+			wantType: EndpointTypeGRPC,
+		},
+		{
+			name:     "graphql",
+			spec:     server.EndpointSpec{Name: "Graph", URL: "https://api.example.com/graphql", GraphQL: &server.GraphQLSpec{Query: "{ health }"}},
+			wantType: EndpointTypeHTTP,
+		},
+		{
+			name:        "option for another type",
+			spec:        server.EndpointSpec{Name: "API", URL: "https://example.com", Resolver: "10.0.0.2"},
+			wantErrLike: "only supported on DNS endpoints",
+		},
+		{
+			name:        "backoff without retries",
+			spec:        server.EndpointSpec{Name: "API", URL: "https://example.com", RetryBackoff: "1s"},
+			wantErrLike: "require retries",
+		},
+		{
+			name:        "bad latency threshold",
+			spec:        server.EndpointSpec{Name: "API", URL: "https://example.com", LatencyThresholds: &server.LatencyThresholdsSpec{Down: "slow"}},
+			wantErrLike: "invalid latency_thresholds.down",
+		},
+		{
+			name:        "bad timeout",
+			spec:        server.EndpointSpec{Name: "API", URL: "https://example.com", Timeout: "soon"},
+			wantErrLike: "invalid timeout",
+		},
+		{
+			name:        "unknown type",
+			spec:        server.EndpointSpec{Name: "API", URL: "https://example.com", Type: "ftp"},
+			wantErrLike: "unknown endpoint type",
+		},
+		{
+			name:        "unknown extractor",
+			spec:        server.EndpointSpec{Name: "API", URL: "https://example.com", Extractor: &server.ExtractorSpec{Type: "xml"}},
+			wantErrLike: "unknown extractor type",
+		},
+		{
+			name:        "json extractor without path",
+			spec:        server.EndpointSpec{Name: "API", URL: "https://example.com", Extractor: &server.ExtractorSpec{Type: "json"}},
+			wantErrLike: "requires a path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep, err := endpointFromSpec(tt.spec)
+			if tt.wantErrLike != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrLike) {
+					t.Fatalf("endpointFromSpec() error = %v, want containing %q", err, tt.wantErrLike)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("endpointFromSpec() error = %v", err)
+			}
+			if ep.Type() != tt.wantType {
+				t.Errorf("Type() = %q, want %q", ep.Type(), tt.wantType)
+			}
+		})
+	}
+}
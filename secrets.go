@@ -0,0 +1,56 @@
+package pulseboard
+
+import (
+	"github.com/jpalmerr/pulseboard/internal/poller"
+	"github.com/jpalmerr/pulseboard/internal/redact"
+)
+
+// WithSecrets masks each of values wherever it would appear in the
+// dashboard, API, SSE stream, log lines or the [StatusResult] passed to
+// status callbacks, such as an API key read from the environment into an
+// endpoint URL. Masked values are replaced by "xxxxx". Values shorter than
+// four characters are ignored, so they cannot mask unrelated text.
+//
+// Independently of WithSecrets, URL passwords (or lone usernames) and the
+// values of well-known sensitive query parameters such as api_key, token
+// or signature are always masked.
+//
+// Example:
+//
+//	apiKey := os.Getenv("API_KEY")
+//	ep, _ := pulseboard.NewEndpoint("API", "https://api.example.com/health?key="+apiKey)
+//	pb, err := pulseboard.New(
+//	    pulseboard.WithEndpoint(ep),
+//	    pulseboard.WithSecrets(apiKey),
+//	)
+func WithSecrets(values ...string) Option {
+	return func(cfg *pbConfig) error {
+		cfg.secrets = append(cfg.secrets, values...)
+		return nil
+	}
+}
+
+// AddSecrets masks further values as [WithSecrets] does, for secrets used
+// by endpoints added while running. Results already recorded are not
+// changed.
+func (pb *PulseBoard) AddSecrets(values ...string) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	pb.redactor = pb.redactor.With(values...)
+}
+
+// redactResult masks credentials and secrets in the URL, errors and raw
+// response of a result.
+func redactResult(r *redact.Redactor, result poller.StatusResult) poller.StatusResult {
+	result.URL = r.String(result.URL)
+	result.Error = r.Error(result.Error)
+	result.RawResponse = r.Bytes(result.RawResponse)
+	if len(result.AttemptErrors) > 0 {
+		errs := make([]error, len(result.AttemptErrors))
+		for i, err := range result.AttemptErrors {
+			errs[i] = r.Error(err)
+		}
+		result.AttemptErrors = errs
+	}
+	return result
+}
@@ -2,9 +2,16 @@ package pulseboard
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -307,3 +314,577 @@ func TestStart_WithTimeoutContext(t *testing.T) {
 		t.Logf("Start() returned error (may be acceptable): %v", err)
 	}
 }
+
+// TestStart_EndpointChangesWhileRunning verifies that endpoints added,
+// updated and removed through the management API take effect while running.
+func TestStart_EndpointChangesWhileRunning(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ep, err := NewEndpoint("Initial", ts.URL)
+	if err != nil {
+		t.Fatalf("NewEndpoint() error = %v", err)
+	}
+
+	polled := make(chan string, 100)
+	pb, err := New(
+		WithEndpoint(ep),
+		WithPort(19012),
+		WithPollingInterval(time.Hour),
+		WithAdminToken("s3cret"),
+		WithStatusCallback(func(r StatusResult) { polled <- r.EndpointName }),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pb.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitFor := func(name string) {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case got := <-polled:
+				if got == name {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timeout waiting for %q to be polled", name)
+			}
+		}
+	}
+	waitFor("Initial")
+
+	api := func(method, target, body string) int {
+		t.Helper()
+		req, _ := http.NewRequest(method, "http://localhost:19012"+target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s error = %v", method, target, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := api(http.MethodPost, "/api/endpoints", `{"name":"Added","url":"`+ts.URL+`"}`); code != http.StatusCreated {
+		t.Fatalf("POST status = %d, want 201", code)
+	}
+	waitFor("Added")
+
+	if code := api(http.MethodPut, "/api/endpoints", `{"name":"Added","url":"`+ts.URL+`/v2"}`); code != http.StatusNoContent {
+		t.Fatalf("PUT status = %d, want 204", code)
+	}
+	waitFor("Added")
+
+	if code := api(http.MethodDelete, "/api/endpoints?name=Initial", ""); code != http.StatusNoContent {
+		t.Fatalf("DELETE status = %d, want 204", code)
+	}
+
+	resp, err := http.Get("http://localhost:19012/api/status")
+	if err != nil {
+		t.Fatalf("GET /api/status error = %v", err)
+	}
+	defer resp.Body.Close()
+	var statuses []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		t.Fatalf("decode /api/status: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "Added" {
+		t.Errorf("/api/status = %+v, want only Added", statuses)
+	}
+
+	eps := pb.Endpoints()
+	if len(eps) != 1 || eps[0].URL() != ts.URL+"/v2" {
+		t.Errorf("Endpoints() = %v, want only updated Added", eps)
+	}
+}
+
+// TestStart_FileStoreSurvivesRestart verifies that a file store replays the
+// last known status on start and drops endpoints no longer configured.
+func TestStart_FileStoreSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+
+	// hangs until the test ends, so the second run never completes a poll
+	release := make(chan struct{})
+	hang := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hang.Close()
+	defer close(release)
+
+	run := func(port int, polled chan<- string, endpoints ...Endpoint) (stop func()) {
+		t.Helper()
+		pb, err := New(
+			WithEndpoints(endpoints...),
+			WithPort(port),
+			WithPollingInterval(time.Hour),
+			WithStore(FileStore(dir)),
+			WithStatusCallback(func(r StatusResult) {
+				if polled != nil {
+					polled <- r.EndpointName
+				}
+			}),
+		)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- pb.Start(ctx) }()
+		return func() {
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("Start() error = %v", err)
+			}
+		}
+	}
+
+	api, _ := NewEndpoint("API", up.URL)
+	old, _ := NewEndpoint("Old", up.URL)
+	polled := make(chan string, 10)
+	stop := run(19013, polled, api, old)
+	for seen := map[string]bool{}; len(seen) < 2; {
+		select {
+		case name := <-polled:
+			seen[name] = true
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for first run to poll")
+		}
+	}
+	stop()
+
+	slowAPI, _ := NewEndpoint("API", hang.URL, WithTimeout(30*time.Second))
+	stop = run(19014, nil, slowAPI)
+	defer stop()
+	time.Sleep(100 * time.Millisecond)
+
+	resp, err := http.Get("http://localhost:19014/api/status")
+	if err != nil {
+		t.Fatalf("GET /api/status error = %v", err)
+	}
+	defer resp.Body.Close()
+	var statuses []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		t.Fatalf("decode /api/status: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "API" || statuses[0].Status != "up" {
+		t.Errorf("/api/status = %+v, want only the persisted API result", statuses)
+	}
+}
+
+// TestStart_Incidents verifies that a failing endpoint opens an incident that
+// closes once it recovers.
+func TestStart_Incidents(t *testing.T) {
+	var healthy atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	ep, _ := NewEndpoint("API", ts.URL, WithLabels("env", "prod"))
+	pb, err := New(WithEndpoint(ep), WithPort(19015), WithPollingInterval(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if pb.Incidents() != nil {
+		t.Error("Incidents() before Start should be nil")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pb.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitFor := func(desc string, cond func([]Incident) bool) []Incident {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if incidents := pb.Incidents(); cond(incidents) {
+				return incidents
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("timeout waiting for %s: %+v", desc, pb.Incidents())
+		return nil
+	}
+
+	open := waitFor("open incident", func(list []Incident) bool { return len(list) == 1 && list[0].Open() })
+	if open[0].EndpointName != "API" || open[0].WorstStatus != StatusDown || open[0].Labels["env"] != "prod" {
+		t.Errorf("open incident = %+v, want down incident for API with labels", open[0])
+	}
+
+	healthy.Store(true)
+	closed := waitFor("closed incident", func(list []Incident) bool { return len(list) == 1 && !list[0].Open() })
+	if closed[0].Duration <= 0 || !closed[0].End.After(closed[0].Start) {
+		t.Errorf("closed incident = %+v, want positive duration", closed[0])
+	}
+	// the same transitions are visible in /metrics
+	resp, err := http.Get("http://localhost:19015/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{`pulseboard_endpoint_status{endpoint="API",env="prod",state="up"} 1`, "pulseboard_poll_queue_depth 0"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("/metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRun_Handler(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	ep, _ := NewEndpoint("API", ts.URL)
+	pb, err := New(WithEndpoint(ep), WithBasePath("/status/"), WithPollingInterval(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/status/", pb.Handler())
+	host := httptest.NewServer(mux)
+	defer host.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(host.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, _ := get("/status/api/status"); code != http.StatusServiceUnavailable {
+		t.Errorf("before Run: status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pb.Run(ctx) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		code, body := get("/status/api/status")
+		if code == http.StatusOK && strings.Contains(body, `"status":"up"`) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for status: %d %s", code, body)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if code, body := get("/status"); code != http.StatusOK || !strings.Contains(body, "api/sse") {
+		t.Errorf("dashboard via redirect: status = %d, body missing relative SSE URL", code)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+	if code, _ := get("/status/api/status"); code != http.StatusServiceUnavailable {
+		t.Errorf("after Run: status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestStart_PublicPage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ep, _ := NewEndpoint("payments-api", ts.URL+"/health?api_key=secret", WithLabels("region", "eu", "team", "payments"))
+	pb, err := New(
+		WithEndpoint(ep),
+		WithPort(19016),
+		WithPollingInterval(50*time.Millisecond),
+		WithAuth(Auth{Tokens: []string{"internal"}}),
+		WithPublicPage(PublicPage{
+			Port:   19017,
+			Labels: []string{"region"},
+			Names:  map[string]string{"payments-api": "Payments"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pb.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var body string
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := http.Get("http://localhost:19017/api/status")
+		if err == nil {
+			b, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			body = string(b)
+			if resp.StatusCode == http.StatusOK && strings.Contains(body, `"status":"down"`) {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for public status: %v %s", err, body)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !strings.Contains(body, `"name":"Payments"`) || !strings.Contains(body, `"url":""`) {
+		t.Errorf("public status = %s, want public name without URL", body)
+	}
+	for _, leak := range []string{"secret", "127.0.0.1", "payments-api", "team"} {
+		if strings.Contains(body, leak) {
+			t.Errorf("public status leaks %q: %s", leak, body)
+		}
+	}
+
+	// the internal dashboard still requires authentication
+	resp, err := http.Get("http://localhost:19016/api/status")
+	if err != nil {
+		t.Fatalf("GET internal status error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("internal status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestStart_TLS(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	certPEM, certFile, keyFile := writeTestCert(t, t.TempDir())
+	ep, _ := NewEndpoint("api", ts.URL)
+	pb, err := New(WithEndpoint(ep), WithPort(19018), WithTLS(certFile, keyFile))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pb.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(certPEM)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots},
+		ForceAttemptHTTP2: true,
+	}}
+
+	var resp *http.Response
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err = client.Get("https://localhost:19018/api/status")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET over HTTPS error = %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ProtoMajor != 2 {
+		t.Errorf("response = %d over %s, want 200 over HTTP/2", resp.StatusCode, resp.Proto)
+	}
+
+	// plain HTTP is not served
+	if resp, err := http.Get("http://localhost:19018/api/status"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Error("plain HTTP request succeeded, want it refused")
+		}
+	}
+}
+
+func TestStart_HandlerSharesSessions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ep, _ := NewEndpoint("api", ts.URL)
+	pb, err := New(WithEndpoint(ep), WithPort(19019), WithAuth(Auth{Tokens: []string{"api-token"}}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pb.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	get := func(url string, cookies ...*http.Cookie) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", url, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// sign in through the served port once it is listening
+	var resp *http.Response
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err = http.Get("http://localhost:19019/api/status?access_token=api-token")
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET error = %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("no session cookie set")
+	}
+
+	// the session is valid on the handler served elsewhere too
+	host := httptest.NewServer(pb.Handler())
+	defer host.Close()
+	if resp := get(host.URL+"/api/status", cookies...); resp.StatusCode != http.StatusOK {
+		t.Errorf("Handler() with served port's session: status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestRun_RedactsSecrets(t *testing.T) {
+	// a closed server, so polls fail with an error quoting the URL
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	target := "http://alice:hunter22@" + strings.TrimPrefix(ts.URL, "http://") + "/v1/envsecretvalue/health?api_key=inlinekey&v=1"
+	ts.Close()
+
+	var logs syncBuffer
+	var callbackURL, callbackErr atomic.Value
+	ep, _ := NewEndpoint("API", target)
+	pb, err := New(
+		WithEndpoint(ep),
+		WithPollingInterval(50*time.Millisecond),
+		WithSecrets("envsecretvalue"),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithStatusCallback(func(r StatusResult) {
+			callbackURL.Store(r.URL)
+			callbackErr.Store(r.Error.Error())
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	host := httptest.NewServer(pb.Handler())
+	defer host.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pb.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var body string
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(body, `"status":"down"`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for status: %s", body)
+		}
+		time.Sleep(20 * time.Millisecond)
+		resp, err := http.Get(host.URL + "/api/status")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		body = string(b)
+	}
+
+	outputs := map[string]string{"/api/status": body, "logs": logs.String()}
+	for deadline := time.Now().Add(time.Second); callbackURL.Load() == nil && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if u, ok := callbackURL.Load().(string); ok {
+		outputs["callback URL"] = u
+		outputs["callback error"] = callbackErr.Load().(string)
+	} else {
+		t.Error("status callback not invoked")
+	}
+	for name, out := range outputs {
+		for _, secret := range []string{"hunter22", "envsecretvalue", "inlinekey"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("%s leaks %q: %s", name, secret, out)
+			}
+		}
+	}
+	if u, _ := callbackURL.Load().(string); !strings.Contains(u, "/v1/xxxxx/health?api_key=xxxxx&v=1") {
+		t.Errorf("callback URL = %q, want masked secrets", u)
+	}
+}
+
+// syncBuffer collects log output written concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -69,9 +69,19 @@ type StatusResult struct {
 	// URL is the target URL that was polled.
 	URL string
 
-	// Status is the determined health state of the endpoint.
+	// Status is the published health state of the endpoint. With
+	// [WithFailureThreshold], [WithSuccessThreshold] or [WithFlapDetection]
+	// it may lag behind RawStatus.
 	Status Status
 
+	// RawStatus is the status determined by this check alone, before
+	// thresholds and flap detection are applied.
+	RawStatus Status
+
+	// Flapping reports whether the endpoint is currently flapping, in which
+	// case Status is held at its last value. See [WithFlapDetection].
+	Flapping bool
+
 	// Labels contains the key-value metadata associated with the endpoint.
 	Labels map[string]string
 
@@ -86,10 +96,35 @@ type StatusResult struct {
 	// be down, degraded, or unknown based on the response content).
 	Error error
 
-	// RawResponse contains the HTTP response body, limited to 1MB.
+	// RawResponse contains the HTTP response body, limited to 1MB. Secrets
+	// and URL credentials in it are masked, as in URL and Error.
 	RawResponse []byte
 
 	// StatusCode is the HTTP status code returned by the endpoint.
 	// Zero if the request failed before receiving a response.
 	StatusCode int
+
+	// Cert describes the server's TLS certificate. Set for TLS endpoints and
+	// for HTTPS endpoints with [WithCertExpiryWarning]; nil otherwise.
+	Cert *CertInfo
+
+	// Attempts is the number of attempts made during the poll. Greater than
+	// 1 only for endpoints with [WithRetries].
+	Attempts int
+
+	// AttemptErrors holds the error of each retried attempt, in order. The
+	// final attempt's error is in Error.
+	AttemptErrors []error
+}
+
+// CertInfo describes the leaf certificate presented by a TLS server.
+type CertInfo struct {
+	// NotAfter is when the certificate expires.
+	NotAfter time.Time
+
+	// Issuer is the issuer's distinguished name.
+	Issuer string
+
+	// DNSNames contains the certificate's subject alternative names.
+	DNSNames []string
 }
@@ -0,0 +1,59 @@
+package pulseboard
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/jpalmerr/pulseboard/internal/store"
+)
+
+// StoreBackend selects where a [PulseBoard] keeps endpoint status and history.
+//
+// Create one with [MemoryStore] or [FileStore] and pass it to [WithStore].
+type StoreBackend struct {
+	file bool
+	dir  string
+}
+
+// MemoryStore keeps status and history in memory. This is the default;
+// state is lost when the process exits.
+func MemoryStore() StoreBackend {
+	return StoreBackend{}
+}
+
+// FileStore persists status, history, uptime and latency in an append-only log under dir,
+// which is created if needed. On start the log is replayed, so the dashboard
+// shows the last known status of every endpoint before the first poll.
+//
+// The log is periodically compacted, and history beyond the limits set with
+// [WithHistory] is discarded. Only one PulseBoard may use dir at a time.
+func FileStore(dir string) StoreBackend {
+	return StoreBackend{file: true, dir: dir}
+}
+
+// open creates the store for b.
+func (b StoreBackend) open(limits store.HistoryLimits, uptime store.UptimeOptions, latencyWindows []time.Duration, logger *slog.Logger) (store.Store, error) {
+	if !b.file {
+		return store.NewMemoryStore(
+			store.WithHistoryLimits(limits),
+			store.WithUptime(uptime),
+			store.WithLatencyWindows(latencyWindows),
+		), nil
+	}
+	opts := store.FileOptions{History: limits, Uptime: uptime, LatencyWindows: latencyWindows}
+	fs, err := store.OpenFileStore(b.dir, opts, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file store: %w", err)
+	}
+	return fs, nil
+}
+
+// closeStore closes st if it holds resources.
+func closeStore(st store.Store) error {
+	if c, ok := st.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package pulseboard
+
+import (
+	"errors"
+
+	"github.com/jpalmerr/pulseboard/internal/server"
+)
+
+// WithTLS serves the dashboard, and a public page on its own port, over
+// HTTPS using the PEM-encoded certificate chain and private key in
+// certFile and keyFile. HTTP/2 is negotiated unless [WithoutHTTP2] is
+// given.
+//
+// The files are checked for changes every few seconds and the certificate
+// is reloaded without a restart, so certificates rotated by tools such as
+// cert-manager are picked up automatically. If the new files cannot be
+// loaded, for example while they are half-written, the current
+// certificate is kept and the reload retried.
+//
+// TLS applies only to [PulseBoard.Start]; with [PulseBoard.Handler] the
+// caller's server terminates TLS.
+//
+// Example:
+//
+//	pulseboard.WithTLS("/etc/pulseboard/tls.crt", "/etc/pulseboard/tls.key")
+//
+// Returns an error if either file is empty or the pair cannot be loaded.
+func WithTLS(certFile, keyFile string) Option {
+	return func(cfg *pbConfig) error {
+		if err := server.ValidateTLS(server.TLS{CertFile: certFile, KeyFile: keyFile}); err != nil {
+			return err
+		}
+		t := cfg.tlsConfig()
+		t.CertFile, t.KeyFile = certFile, keyFile
+		return nil
+	}
+}
+
+// WithClientCA verifies client certificates against the PEM-encoded CAs in
+// caFile, for mutual TLS with API consumers. If required is set,
+// connections without a valid client certificate are rejected during the
+// handshake; otherwise a certificate is optional.
+//
+// When [WithAuth] or [WithOIDC] is used, a verified client certificate
+// authenticates the request like a token does. The public page of
+// [WithPublicPage] never asks for client certificates.
+//
+// Requires [WithTLS]. [New] returns an error if caFile is empty or
+// contains no certificates.
+func WithClientCA(caFile string, required bool) Option {
+	return func(cfg *pbConfig) error {
+		if caFile == "" {
+			return errors.New("client CA file is required")
+		}
+		t := cfg.tlsConfig()
+		t.ClientCAFile, t.RequireClientCert = caFile, required
+		return nil
+	}
+}
+
+// WithoutHTTP2 serves HTTPS over HTTP/1.1 only, for clients or proxies
+// that mishandle HTTP/2. Requires [WithTLS].
+func WithoutHTTP2() Option {
+	return func(cfg *pbConfig) error {
+		cfg.tlsConfig().DisableHTTP2 = true
+		return nil
+	}
+}
+
+// tlsConfig returns the TLS settings being configured, creating them if
+// needed.
+func (cfg *pbConfig) tlsConfig() *server.TLS {
+	if cfg.tls == nil {
+		cfg.tls = &server.TLS{}
+	}
+	return cfg.tls
+}
+
+// validateTLS reports whether the TLS settings, if any, are complete and
+// their files load.
+func validateTLS(t *server.TLS) error {
+	if t == nil {
+		return nil
+	}
+	if t.CertFile == "" {
+		return errors.New("client CA and HTTP/2 settings require WithTLS")
+	}
+	return server.ValidateTLS(*t)
+}
+
+// scheme returns the URL scheme the dashboard is served with.
+func (pb *PulseBoard) scheme() string {
+	if pb.tls != nil {
+		return "https"
+	}
+	return "http"
+}